@@ -1,17 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	_ "github.com/HIUNCY/url-shortener-with-analytics/docs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/repository/cached"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/repository/postgres"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/cache"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/database"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/dns"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/geoip"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/ratelimit"
 	"github.com/HIUNCY/url-shortener-with-analytics/routes"
+	"github.com/HIUNCY/url-shortener-with-analytics/web"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -47,39 +60,172 @@ func main() {
 	userRepository := postgres.NewUserRepository(db)
 	urlRepository := postgres.NewURLRepository(db)
 	clickRepository := postgres.NewClickRepository(db)
+	domainRepository := postgres.NewDomainRepository(db)
+	bulkOperationRepository := postgres.NewBulkOperationRepository(db)
+	sessionRepository := postgres.NewSessionRepository(db)
+	refreshTokenRepository := postgres.NewRefreshTokenRepository(db)
+	apiKeyRepository := postgres.NewAPIKeyRepository(db)
+	campaignRepository := postgres.NewCampaignRepository(db)
+	tagRepository := postgres.NewTagRepository(db)
+	folderRepository := postgres.NewFolderRepository(db)
 
-	authService := services.NewAuthService(userRepository, config)
-	userService := services.NewUserService(userRepository)
-	urlService := services.NewURLService(urlRepository, config)
+	var urlCache cache.Cache
+	switch config.Cache.Backend {
+	case "memory":
+		urlCache = cache.NewMemoryCache(config.Cache.Size)
+	case "redis":
+		urlCache = cache.NewRedisCache(cache.RedisOptions{
+			Addr:      config.Cache.RedisAddr,
+			Password:  config.Cache.RedisPassword,
+			DB:        config.Cache.RedisDB,
+			KeyPrefix: "url-shortener:",
+		})
+	}
+	if urlCache != nil {
+		cacheTTL, err := time.ParseDuration(config.Cache.TTL)
+		if err != nil || cacheTTL <= 0 {
+			cacheTTL = 5 * time.Minute
+		}
+		negativeCacheTTL, err := time.ParseDuration(config.Cache.NegativeTTL)
+		if err != nil || negativeCacheTTL < 0 {
+			negativeCacheTTL = 0
+		}
+		urlRepository = cached.NewURLRepository(urlRepository, urlCache, cacheTTL, negativeCacheTTL)
+		log.Printf("Cache pencarian short code aktif (%s)", config.Cache.Backend)
+	}
+
+	rateLimitStore := ratelimit.NewMemoryStore()
+	if config.RateLimit.Backend == "postgres" {
+		rateLimitStore = postgres.NewRateLimitRepository(db)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore)
+	// Redirects are always counted in memory: a database write per redirect
+	// would undo the cache and the batched click pipeline.
+	redirectLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	rateLimitWindow, err := time.ParseDuration(config.RateLimit.Window)
+	if err != nil || rateLimitWindow <= 0 {
+		rateLimitWindow = time.Minute
+	}
+
+	authService := services.NewAuthService(userRepository, sessionRepository, refreshTokenRepository, config)
+	userService := services.NewUserService(userRepository, sessionRepository)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepository)
+	urlService := services.NewURLService(urlRepository, domainRepository, userRepository, campaignRepository, tagRepository, folderRepository, config)
+	campaignService := services.NewCampaignService(campaignRepository)
+	tagService := services.NewTagService(tagRepository)
+	folderService := services.NewFolderService(folderRepository)
+	domainService := services.NewDomainService(domainRepository, urlRepository, dns.NewResolver(), config)
 	geoipService := geoip.NewGeoIPService(config.GeoIP)
-	redirectService := services.NewRedirectService(urlRepository, clickRepository, geoipService, config)
-	analyticsService := services.NewAnalyticsService(urlRepository, clickRepository)
+	visitorIdentifier := services.NewVisitorIdentifier(config)
+	clickTracker := services.NewClickTracker(urlRepository, clickRepository, geoipService, visitorIdentifier, config)
+	clickTracker.Start()
+	redirectService := services.NewRedirectService(urlRepository, domainRepository, clickTracker, geoipService, limiter, config)
+	analyticsService := services.NewAnalyticsService(urlRepository, clickRepository, campaignRepository, tagRepository, folderRepository, config)
+	clickService := services.NewClickService(urlRepository, clickRepository, userRepository)
 	qrCodeService := services.NewQRCodeService(urlRepository, config)
+	bulkRunner := services.NewBulkRunner(bulkOperationRepository, urlRepository, domainRepository, userRepository, campaignRepository, tagRepository, folderRepository, config)
+	bulkRunner.Start()
+	bulkService := services.NewBulkService(bulkOperationRepository, bulkRunner, config)
+	urlPurger := services.NewURLPurger(urlRepository, config)
+	urlPurger.Start()
 
 	authHandler := handlers.NewAuthHandler(authService, config)
 	profileHandler := handlers.NewProfileHandler(userService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
 	urlHandler := handlers.NewURLHandler(urlService, config)
+	domainHandler := handlers.NewDomainHandler(domainService)
+	campaignHandler := handlers.NewCampaignHandler(campaignService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	folderHandler := handlers.NewFolderHandler(folderService)
 	redirectHandler := handlers.NewRedirectHandler(redirectService, config)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	clickHandler := handlers.NewClickHandler(clickService)
 	qrCodeHandler := handlers.NewQRCodeHandler(qrCodeService)
+	bulkHandler := handlers.NewBulkHandler(bulkService, config)
+	metricsHandler := handlers.NewMetricsHandler(clickTracker, urlCache)
+
+	mw := routes.Middleware{
+		Auth:          middleware.AuthMiddleware(config.JWT, apiKeyRepository, sessionRepository),
+		APIRateLimit:  middleware.PlanRateLimit(limiter, config.RateLimit.Plans, userRepository),
+		AuthRateLimit: middleware.RateLimitByIP(limiter, ratelimit.Rule{Limit: config.RateLimit.Auth, Window: rateLimitWindow}),
+	}
+	redirectRateLimit := middleware.RateLimitByIP(redirectLimiter, ratelimit.Rule{Limit: config.RateLimit.Redirect, Window: rateLimitWindow})
+	unlockRateLimit := middleware.RateLimitByIP(limiter, ratelimit.Rule{Limit: config.RateLimit.Unlock, Window: rateLimitWindow})
 
 	router := gin.Default()
+	if err := router.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		log.Fatalf("Daftar trusted proxy tidak valid: %v", err)
+	}
+	router.SetHTMLTemplate(web.Templates())
 
-	router.GET("/:shortCode", redirectHandler.Redirect)
-	router.POST("/:shortCode/unlock", redirectHandler.UnlockURL)
-	router.GET("/:shortCode/info", redirectHandler.GetURLInfo)
+	router.GET("/:shortCode", redirectRateLimit, redirectHandler.Redirect)
+	router.POST("/:shortCode/unlock", unlockRateLimit, redirectHandler.UnlockURL)
+	router.GET("/:shortCode/info", redirectRateLimit, redirectHandler.GetURLInfo)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	apiV1 := router.Group("/api/v1")
-	routes.SetupAuthRoutes(apiV1, authHandler, config, userRepository)
-	routes.SetupProfileRoutes(apiV1, profileHandler, config, userRepository)
-	routes.SetupURLRoutes(apiV1, urlHandler, config, userRepository)
-	routes.SetupAnalyticsRoutes(apiV1, analyticsHandler, config, userRepository)
-	routes.SetupQRCodeRoutes(apiV1, qrCodeHandler, config, userRepository)
+	routes.SetupAuthRoutes(apiV1, authHandler, mw)
+	routes.SetupProfileRoutes(apiV1, profileHandler, mw)
+	routes.SetupAPIKeyRoutes(apiV1, apiKeyHandler, mw)
+	routes.SetupURLRoutes(apiV1, urlHandler, mw)
+	routes.SetupDomainRoutes(apiV1, domainHandler, mw)
+	routes.SetupCampaignRoutes(apiV1, campaignHandler, mw)
+	routes.SetupTagRoutes(apiV1, tagHandler, mw)
+	routes.SetupFolderRoutes(apiV1, folderHandler, mw)
+	routes.SetupAnalyticsRoutes(apiV1, analyticsHandler, mw)
+	routes.SetupClickRoutes(apiV1, clickHandler, mw)
+	routes.SetupQRCodeRoutes(apiV1, qrCodeHandler, mw)
+	routes.SetupBulkRoutes(apiV1, bulkHandler, mw)
 
 	serverAddress := fmt.Sprintf(":%s", config.Server.Port)
-	log.Printf("Server berjalan di %s", serverAddress)
-	if err := router.Run(serverAddress); err != nil {
-		log.Fatalf("Gagal menjalankan server: %v", err)
+	server := &http.Server{Addr: serverAddress, Handler: router}
+
+	go func() {
+		log.Printf("Server berjalan di %s", serverAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Gagal menjalankan server: %v", err)
+		}
+	}()
+
+	var internalServer *http.Server
+	if config.Server.InternalAddr != "" {
+		internalRouter := gin.New()
+		internalRouter.Use(gin.Recovery())
+		routes.SetupSystemRoutes(&internalRouter.RouterGroup, metricsHandler)
+		internalServer = &http.Server{Addr: config.Server.InternalAddr, Handler: internalRouter}
+
+		go func() {
+			log.Printf("Server internal berjalan di %s", config.Server.InternalAddr)
+			if err := internalServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("Gagal menjalankan server internal: %v", err)
+			}
+		}()
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Mematikan server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Gagal mematikan server dengan baik: %v", err)
+	}
+	if internalServer != nil {
+		if err := internalServer.Shutdown(ctx); err != nil {
+			log.Printf("Gagal mematikan server internal dengan baik: %v", err)
+		}
+	}
+	if err := clickTracker.Shutdown(ctx); err != nil {
+		log.Printf("Gagal menyimpan sisa antrean klik: %v", err)
+	}
+	if err := bulkRunner.Shutdown(ctx); err != nil {
+		log.Printf("Gagal menghentikan pemrosesan bulk: %v", err)
+	}
+	if err := urlPurger.Shutdown(ctx); err != nil {
+		log.Printf("Gagal menghentikan pembersihan URL terhapus: %v", err)
 	}
+	log.Println("Server berhenti.")
 }
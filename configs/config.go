@@ -1,27 +1,83 @@
 package configs
 
 import (
+	"os"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
+// JWTConfig signs access and refresh tokens. RefreshGrace is how long a
+// refresh token that was just rotated may be presented again and receive the
+// same successor, so that concurrent refreshes from one client do not count
+// as token reuse; zero disables it.
 type JWTConfig struct {
 	SecretKey        string `mapstructure:"secretkey"`
 	ExpiresIn        string `mapstructure:"expiresin"`
 	RefreshSecretKey string `mapstructure:"refreshsecretkey"`
 	RefreshExpiresIn string `mapstructure:"refreshexpiresin"`
+	RefreshGrace     string `mapstructure:"refreshgrace"`
+}
+
+// AccessTTL returns ExpiresIn, falling back to 15 minutes when it is unset
+// or invalid.
+func (j JWTConfig) AccessTTL() time.Duration {
+	ttl, err := time.ParseDuration(j.ExpiresIn)
+	if err != nil || ttl <= 0 {
+		return 15 * time.Minute
+	}
+	return ttl
+}
+
+// RefreshTTL returns RefreshExpiresIn, the lifetime of a session, falling
+// back to 7 days when it is unset or invalid.
+func (j JWTConfig) RefreshTTL() time.Duration {
+	ttl, err := time.ParseDuration(j.RefreshExpiresIn)
+	if err != nil || ttl <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return ttl
+}
+
+// RefreshGracePeriod returns RefreshGrace, falling back to 10 seconds when
+// it is unset or invalid.
+func (j JWTConfig) RefreshGracePeriod() time.Duration {
+	grace, err := time.ParseDuration(j.RefreshGrace)
+	if err != nil || grace < 0 {
+		return 10 * time.Second
+	}
+	return grace
 }
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	GeoIP    GeoIPConfig    `mapstructure:"geoip"`
+	Server    ServerConfig        `mapstructure:"server"`
+	Database  DatabaseConfig      `mapstructure:"database"`
+	JWT       JWTConfig           `mapstructure:"jwt"`
+	GeoIP     GeoIPConfig         `mapstructure:"geoip"`
+	Clicks    ClickPipelineConfig `mapstructure:"clicks"`
+	Visitor   VisitorConfig       `mapstructure:"visitor"`
+	Unlock    UnlockConfig        `mapstructure:"unlock"`
+	Domains   DomainsConfig       `mapstructure:"domains"`
+	Bulk      BulkConfig          `mapstructure:"bulk"`
+	RateLimit RateLimitConfig     `mapstructure:"ratelimit"`
+	Cache     CacheConfig         `mapstructure:"cache"`
+	Trash     TrashConfig         `mapstructure:"trash"`
+	Analytics AnalyticsConfig     `mapstructure:"analytics"`
 }
 
+// ServerConfig sets where the API listens. InternalAddr is a separate
+// listener for operational endpoints such as metrics; it binds to loopback
+// by default and an empty value disables it.
+// ServerConfig controls the HTTP servers. TrustedProxies lists the IPs or
+// CIDRs of the reverse proxies whose X-Forwarded-For header names the client;
+// by default none is trusted and the client IP, which every per-IP limit is
+// keyed by, is the address of the connection.
 type ServerConfig struct {
-	BaseURL string `mapstructure:"baseurl"`
-	Port    string `mapstructure:"port"`
-	Env     string `mapstructure:"env"`
+	BaseURL        string   `mapstructure:"baseurl"`
+	Port           string   `mapstructure:"port"`
+	Env            string   `mapstructure:"env"`
+	InternalAddr   string   `mapstructure:"internaladdr"`
+	TrustedProxies []string `mapstructure:"trustedproxies"`
 }
 
 type DatabaseConfig struct {
@@ -38,12 +94,218 @@ type GeoIPConfig struct {
 	DBPath string `mapstructure:"dbpath"`
 }
 
+// ClickPipelineConfig tunes the asynchronous click-ingestion workers.
+type ClickPipelineConfig struct {
+	Workers        int    `mapstructure:"workers"`
+	QueueSize      int    `mapstructure:"queuesize"`
+	BatchSize      int    `mapstructure:"batchsize"`
+	FlushInterval  string `mapstructure:"flushinterval"`
+	EnqueueTimeout string `mapstructure:"enqueuetimeout"`
+}
+
+// VisitorConfig controls how repeat visitors are recognised. Mode "cookie"
+// issues a first-party visitor cookie; mode "hash" never sets cookies and
+// instead hashes IP + user agent with a salt that rotates every UTC day.
+type VisitorConfig struct {
+	Mode         string `mapstructure:"mode"`
+	CookieName   string `mapstructure:"cookiename"`
+	CookieMaxAge string `mapstructure:"cookiemaxage"`
+	HashSecret   string `mapstructure:"hashsecret"`
+	UniqueWindow string `mapstructure:"uniquewindow"`
+}
+
+// visitorSaltRotation is how long a hashed visitor keeps the same key.
+const visitorSaltRotation = 24 * time.Hour
+
+// UniquePeriod returns UniqueWindow, falling back to 24 hours when it is
+// unset or invalid. Without the cookie a visitor cannot be recognised once
+// the hash salt rotates, so outside cookie mode the window is capped at a
+// day.
+func (v VisitorConfig) UniquePeriod() time.Duration {
+	window, err := time.ParseDuration(v.UniqueWindow)
+	if err != nil || window <= 0 {
+		return 24 * time.Hour
+	}
+	if v.Mode != "cookie" && window > visitorSaltRotation {
+		return visitorSaltRotation
+	}
+	return window
+}
+
+// UnlockConfig controls access to password-protected links. A correct
+// password sets a signed, HttpOnly cookie scoped to the link's path that
+// lets the browser through for SessionTTL. Password attempts are limited to
+// Attempts per AttemptWindow for each link and client IP, and to
+// LinkAttempts per AttemptWindow for each link whatever the IP. Secret signs
+// the cookie and defaults to the JWT secret.
+type UnlockConfig struct {
+	CookieName    string `mapstructure:"cookiename"`
+	SessionTTL    string `mapstructure:"sessionttl"`
+	Secret        string `mapstructure:"secret"`
+	Attempts      int    `mapstructure:"attempts"`
+	LinkAttempts  int    `mapstructure:"linkattempts"`
+	AttemptWindow string `mapstructure:"attemptwindow"`
+}
+
+// Session returns SessionTTL, falling back to one hour when it is unset or
+// invalid.
+func (u UnlockConfig) Session() time.Duration {
+	ttl, err := time.ParseDuration(u.SessionTTL)
+	if err != nil || ttl <= 0 {
+		return time.Hour
+	}
+	return ttl
+}
+
+// AttemptPeriod returns AttemptWindow, falling back to 15 minutes when it
+// is unset or invalid.
+func (u UnlockConfig) AttemptPeriod() time.Duration {
+	window, err := time.ParseDuration(u.AttemptWindow)
+	if err != nil || window <= 0 {
+		return 15 * time.Minute
+	}
+	return window
+}
+
+// DomainsConfig controls custom domain claims. A claim that is not verified
+// within ClaimTTL expires and is removed.
+type DomainsConfig struct {
+	ClaimTTL string `mapstructure:"claimttl"`
+}
+
+// ClaimPeriod returns ClaimTTL, falling back to 7 days when it is unset or
+// invalid.
+func (d DomainsConfig) ClaimPeriod() time.Duration {
+	ttl, err := time.ParseDuration(d.ClaimTTL)
+	if err != nil || ttl <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return ttl
+}
+
+// BulkConfig limits bulk uploads and tunes the background job runner.
+// MaxFileSize is in bytes. Uploads are kept under StorageDir until their job
+// finishes; unless SharedStorage says every instance mounts the same
+// StorageDir, a job only runs on the instance named NodeID that accepted it.
+type BulkConfig struct {
+	StorageDir    string `mapstructure:"storagedir"`
+	SharedStorage bool   `mapstructure:"sharedstorage"`
+	NodeID        string `mapstructure:"nodeid"`
+	MaxRows       int    `mapstructure:"maxrows"`
+	MaxFileSize   int64  `mapstructure:"maxfilesize"`
+	PollInterval  string `mapstructure:"pollinterval"`
+}
+
+// Node returns the instance name bulk jobs are pinned to: NodeID, or the
+// host name when it is unset. It is "" with SharedStorage, when any
+// instance may run any job.
+func (b BulkConfig) Node() string {
+	if b.SharedStorage {
+		return ""
+	}
+	if b.NodeID != "" {
+		return b.NodeID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return "localhost"
+	}
+	return host
+}
+
+// RateLimitConfig selects the counter store ("memory" or "postgres") and the
+// per-IP limits for unauthenticated endpoints. Each limit allows that many
+// requests per Window, per client IP and endpoint. Redirect limits are
+// always kept in memory, whatever the backend, to keep redirects off the
+// database.
+type RateLimitConfig struct {
+	Backend  string           `mapstructure:"backend"`
+	Window   string           `mapstructure:"window"`
+	Redirect int              `mapstructure:"redirect"`
+	Unlock   int              `mapstructure:"unlock"`
+	Auth     int              `mapstructure:"auth"`
+	Plans    PlanQuotasConfig `mapstructure:"plans"`
+}
+
+type PlanQuotasConfig struct {
+	Free       PlanQuota `mapstructure:"free"`
+	Pro        PlanQuota `mapstructure:"pro"`
+	Enterprise PlanQuota `mapstructure:"enterprise"`
+}
+
+// PlanQuota holds the limits of one plan. Zero means unlimited.
+type PlanQuota struct {
+	APICallsPerMinute int `mapstructure:"apicallsperminute"`
+	LinksPerMonth     int `mapstructure:"linkspermonth"`
+}
+
+// For returns the quota of a plan, falling back to the free plan for
+// unknown plan types.
+func (p PlanQuotasConfig) For(planType string) PlanQuota {
+	switch planType {
+	case "pro":
+		return p.Pro
+	case "enterprise":
+		return p.Enterprise
+	default:
+		return p.Free
+	}
+}
+
+// CacheConfig controls the short-code lookup cache used by redirects.
+// Backend "memory" keeps an LRU of Size entries in each instance, "redis"
+// shares one cache between instances and "none" disables caching. A zero
+// NegativeTTL disables caching of unknown short codes.
+type CacheConfig struct {
+	Backend       string `mapstructure:"backend"`
+	Size          int    `mapstructure:"size"`
+	TTL           string `mapstructure:"ttl"`
+	NegativeTTL   string `mapstructure:"negativettl"`
+	RedisAddr     string `mapstructure:"redisaddr"`
+	RedisPassword string `mapstructure:"redispassword"`
+	RedisDB       int    `mapstructure:"redisdb"`
+}
+
+// TrashConfig controls how long deleted links can be restored before they
+// and their click history are purged, and how often the purge runs.
+type TrashConfig struct {
+	Retention     string `mapstructure:"retention"`
+	PurgeInterval string `mapstructure:"purgeinterval"`
+}
+
+// RetentionPeriod returns Retention, falling back to 30 days when it is
+// unset or invalid.
+func (t TrashConfig) RetentionPeriod() time.Duration {
+	retention, err := time.ParseDuration(t.Retention)
+	if err != nil || retention <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return retention
+}
+
+// AnalyticsConfig bounds how long the queries behind one analytics request
+// may run in total.
+type AnalyticsConfig struct {
+	QueryTimeout string `mapstructure:"querytimeout"`
+}
+
+// Timeout returns QueryTimeout, falling back to 10 seconds when it is unset
+// or invalid.
+func (a AnalyticsConfig) Timeout() time.Duration {
+	timeout, err := time.ParseDuration(a.QueryTimeout)
+	if err != nil || timeout <= 0 {
+		return 10 * time.Second
+	}
+	return timeout
+}
+
 func LoadConfig(path string) (config Config, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName(".env")
 	viper.SetConfigType("env")
 
 	viper.AutomaticEnv()
+	setDefaults()
 
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -55,3 +317,57 @@ func LoadConfig(path string) (config Config, err error) {
 	err = viper.Unmarshal(&config)
 	return
 }
+
+func setDefaults() {
+	viper.SetDefault("server.internaladdr", "127.0.0.1:9090")
+	viper.SetDefault("server.trustedproxies", []string{})
+
+	viper.SetDefault("jwt.refreshgrace", "10s")
+
+	viper.SetDefault("clicks.workers", 4)
+	viper.SetDefault("clicks.queuesize", 10000)
+	viper.SetDefault("clicks.batchsize", 500)
+	viper.SetDefault("clicks.flushinterval", "1s")
+	viper.SetDefault("clicks.enqueuetimeout", "50ms")
+
+	viper.SetDefault("visitor.mode", "cookie")
+	viper.SetDefault("visitor.cookiename", "_vid")
+	viper.SetDefault("visitor.cookiemaxage", "8760h")
+	viper.SetDefault("visitor.uniquewindow", "24h")
+
+	viper.SetDefault("unlock.cookiename", "_unlock")
+	viper.SetDefault("unlock.sessionttl", "1h")
+	viper.SetDefault("unlock.attempts", 5)
+	viper.SetDefault("unlock.linkattempts", 50)
+	viper.SetDefault("unlock.attemptwindow", "15m")
+
+	viper.SetDefault("domains.claimttl", "168h")
+
+	viper.SetDefault("bulk.storagedir", "storage/bulk")
+	viper.SetDefault("bulk.maxrows", 50000)
+	viper.SetDefault("bulk.maxfilesize", 20<<20)
+	viper.SetDefault("bulk.pollinterval", "5s")
+
+	viper.SetDefault("ratelimit.backend", "memory")
+	viper.SetDefault("ratelimit.window", "1m")
+	viper.SetDefault("ratelimit.redirect", 120)
+	viper.SetDefault("ratelimit.unlock", 10)
+	viper.SetDefault("ratelimit.auth", 20)
+	viper.SetDefault("ratelimit.plans.free.apicallsperminute", 60)
+	viper.SetDefault("ratelimit.plans.free.linkspermonth", 500)
+	viper.SetDefault("ratelimit.plans.pro.apicallsperminute", 600)
+	viper.SetDefault("ratelimit.plans.pro.linkspermonth", 10000)
+	viper.SetDefault("ratelimit.plans.enterprise.apicallsperminute", 6000)
+	viper.SetDefault("ratelimit.plans.enterprise.linkspermonth", 0)
+
+	viper.SetDefault("cache.backend", "memory")
+	viper.SetDefault("cache.size", 10000)
+	viper.SetDefault("cache.ttl", "5m")
+	viper.SetDefault("cache.negativettl", "30s")
+	viper.SetDefault("cache.redisaddr", "localhost:6379")
+
+	viper.SetDefault("trash.retention", "720h")
+	viper.SetDefault("trash.purgeinterval", "1h")
+
+	viper.SetDefault("analytics.querytimeout", "10s")
+}
@@ -0,0 +1,88 @@
+package configs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJWTConfigDurationsFallBackToDefaults(t *testing.T) {
+	tests := []struct {
+		name                   string
+		cfg                    JWTConfig
+		access, refresh, grace time.Duration
+	}{
+		{
+			name:    "unset",
+			access:  15 * time.Minute,
+			refresh: 7 * 24 * time.Hour,
+			grace:   10 * time.Second,
+		},
+		{
+			name:    "invalid",
+			cfg:     JWTConfig{ExpiresIn: "15", RefreshExpiresIn: "a week", RefreshGrace: "soon"},
+			access:  15 * time.Minute,
+			refresh: 7 * 24 * time.Hour,
+			grace:   10 * time.Second,
+		},
+		{
+			name:    "negative",
+			cfg:     JWTConfig{ExpiresIn: "-1m", RefreshExpiresIn: "-1h", RefreshGrace: "-1s"},
+			access:  15 * time.Minute,
+			refresh: 7 * 24 * time.Hour,
+			grace:   10 * time.Second,
+		},
+		{
+			name:    "set",
+			cfg:     JWTConfig{ExpiresIn: "5m", RefreshExpiresIn: "720h", RefreshGrace: "0s"},
+			access:  5 * time.Minute,
+			refresh: 720 * time.Hour,
+			grace:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.AccessTTL(); got != tt.access {
+				t.Errorf("AccessTTL() = %v, want %v", got, tt.access)
+			}
+			if got := tt.cfg.RefreshTTL(); got != tt.refresh {
+				t.Errorf("RefreshTTL() = %v, want %v", got, tt.refresh)
+			}
+			if got := tt.cfg.RefreshGracePeriod(); got != tt.grace {
+				t.Errorf("RefreshGracePeriod() = %v, want %v", got, tt.grace)
+			}
+		})
+	}
+}
+
+func TestTrashRetentionFallsBackToThirtyDays(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":      30 * 24 * time.Hour,
+		"month": 30 * 24 * time.Hour,
+		"-24h":  30 * 24 * time.Hour,
+		"0s":    30 * 24 * time.Hour,
+		"168h":  7 * 24 * time.Hour,
+	}
+	for retention, want := range tests {
+		if got := (TrashConfig{Retention: retention}).RetentionPeriod(); got != want {
+			t.Errorf("RetentionPeriod() of %q = %v, want %v", retention, got, want)
+		}
+	}
+}
+
+func TestUniqueWindowIsCappedAtTheSaltRotationInHashMode(t *testing.T) {
+	tests := []struct {
+		cfg  VisitorConfig
+		want time.Duration
+	}{
+		{VisitorConfig{Mode: "cookie"}, 24 * time.Hour},
+		{VisitorConfig{Mode: "cookie", UniqueWindow: "a week"}, 24 * time.Hour},
+		{VisitorConfig{Mode: "cookie", UniqueWindow: "168h"}, 168 * time.Hour},
+		{VisitorConfig{Mode: "hash", UniqueWindow: "168h"}, 24 * time.Hour},
+		{VisitorConfig{Mode: "hash", UniqueWindow: "30m"}, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.UniquePeriod(); got != tt.want {
+			t.Errorf("UniquePeriod() of %+v = %v, want %v", tt.cfg, got, tt.want)
+		}
+	}
+}
@@ -23,14 +23,144 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/analytics/clicks/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams every matching click across all of the user's links, oldest first, as CSV, NDJSON or a JSON array. Each row carries the link's ID and short code. IP addresses are shown according to the account's privacy setting. In CSV, text cells starting with =, +, - or @ are prefixed with a single quote so spreadsheets do not run them as formulas.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Export account clicks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only clicks at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only clicks before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks referred from these hosts, without www.",
+                        "name": "referrer_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_campaign values",
+                        "name": "utm_campaign",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Click export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/analytics/dashboard": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves summary analytics for the authenticated user's dashboard.",
+                "description": "Retrieves summary analytics for the authenticated user's dashboard. top_tags and top_folders rank the user's tags and folders by the clicks on their links. With dimension filters, the click totals and the top URLs, tags and folders count only the matching clicks. If the top URLs, tags or folders or the recent activity cannot be loaded they are returned empty and named in warnings; if the summary cannot be loaded the request fails.",
                 "produces": [
                     "application/json"
                 ],
@@ -38,6 +168,175 @@ const docTemplate = `{
                     "Analytics"
                 ],
                 "summary": "Get user dashboard analytics",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these regions",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these cities",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these browsers",
+                        "name": "browser",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these operating systems",
+                        "name": "os",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks whose preferred language is one of these, e.g. en",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these exact referrers",
+                        "name": "referrer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks referred from these hosts, without www., e.g. twitter.com",
+                        "name": "referrer_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_source values",
+                        "name": "utm_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_medium values",
+                        "name": "utm_medium",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_campaign values",
+                        "name": "utm_campaign",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_term values",
+                        "name": "utm_term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_content values",
+                        "name": "utm_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks sent to these destinations, e.g. geo:US, split:A or default",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -45,11 +344,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.UserDashboardSuccessResponse"
                         }
                     },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Dashboard could not be loaded",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Dashboard took too long",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
                     }
                 }
             }
@@ -113,7 +430,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Invalidates the user's session (client-side).",
+                "description": "Revokes the current session. Its access and refresh tokens stop working immediately.",
                 "produces": [
                     "application/json"
                 ],
@@ -123,7 +440,44 @@ const docTemplate = `{
                 "summary": "Log out a user",
                 "responses": {
                     "200": {
-                        "description": "Logged out successfully\" // \u003c-- DIUBAH DI SINI",
+                        "description": "Logged out successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires a bearer token",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes every session of the current user on all devices, including the current one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Log out everywhere",
+                "responses": {
+                    "200": {
+                        "description": "Logged out of all sessions",
                         "schema": {
                             "$ref": "#/definitions/response.SuccessMessageResponse"
                         }
@@ -133,13 +487,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Requires a bearer token",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
                     }
                 }
             }
         },
         "/auth/refresh": {
             "post": {
-                "description": "Generates a new access token using a refresh token.",
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes the session. Refreshes that race each other within a few seconds all receive the same new refresh token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -209,7 +569,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "201": {
-                        "description": "User registered successfully\" // \u003c-- DIUBAH DI SINI",
+                        "description": "User registered successfully",
                         "schema": {
                             "$ref": "#/definitions/response.RegisterSuccessResponse"
                         }
@@ -235,79 +595,124 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile": {
-            "get": {
+        "/bulk/urls": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the profile of the currently logged-in user.",
+                "description": "Uploads a CSV or JSON file of links to create, update, deactivate or delete. The file is processed in the background; poll the returned operation for progress. CSV files need a header row using the same names as the JSON fields: original_url, custom_alias, domain, title, description, password, expires_at for create; id or short_code (plus optional domain) to identify links for the other operations, with title, description, expires_at and is_active for update.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Bulk Operations"
+                ],
+                "summary": "Submit a bulk URL operation",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or JSON file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "deactivate",
+                            "delete"
+                        ],
+                        "type": "string",
+                        "description": "Operation to apply to every row",
+                        "name": "operation",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "File format; defaults to the file extension",
+                        "name": "format",
+                        "in": "formData"
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Bulk operation accepted",
                         "schema": {
-                            "$ref": "#/definitions/response.ProfileSuccessResponse"
+                            "$ref": "#/definitions/response.BulkOperationSuccessResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/bulk/{operation_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates the first and last name of the currently logged-in user.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the status and progress of a bulk operation.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Bulk Operations"
                 ],
-                "summary": "Update user profile",
+                "summary": "Get bulk operation status",
                 "parameters": [
                     {
-                        "description": "Profile Information",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.UpdateProfileRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Bulk Operation ID",
+                        "name": "operation_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.ProfileSuccessResponse"
+                            "$ref": "#/definitions/response.BulkOperationSuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Validation error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Bulk operation not found",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -315,81 +720,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/api-key/regenerate": {
-            "post": {
+        "/bulk/{operation_id}/result": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Generates a new API key for the currently logged-in user.",
+                "description": "Downloads a CSV with one line per input row: its status, the link's ID, short code and short URL, or the error code for rows that failed.",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "Profile"
+                    "Bulk Operations"
                 ],
-                "summary": "Regenerate API Key",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIKeySuccessResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/profile/password": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates the password of the currently logged-in user.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Profile"
-                ],
-                "summary": "Change user password",
+                "summary": "Download bulk operation result",
                 "parameters": [
                     {
-                        "description": "Password Change Info",
-                        "name": "passwords",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.ChangePasswordRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Bulk Operation ID",
+                        "name": "operation_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Result CSV",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Validation error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid current password",
+                    "404": {
+                        "description": "Bulk operation not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Operation still running",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -397,7 +776,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls": {
+        "/campaigns": {
             "get": {
                 "security": [
                     {
@@ -407,62 +786,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a paginated list of URLs for the authenticated user.",
+                "description": "Retrieves every campaign of the authenticated user, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "URLs"
-                ],
-                "summary": "Get user's URLs",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search query for title or original URL",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "created_at",
-                            "click_count",
-                            "title"
-                        ],
-                        "type": "string",
-                        "description": "Sort by field (created_at, click_count, title)",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "Sort order (asc, desc)",
-                        "name": "order",
-                        "in": "query"
-                    }
+                    "Campaigns"
                 ],
+                "summary": "List campaigns",
                 "responses": {
                     "200": {
-                        "description": "List of URLs retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.URLListSuccessResponse"
+                            "$ref": "#/definitions/response.CampaignListSuccessResponse"
                         }
                     },
                     "401": {
@@ -482,7 +818,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new short URL for the authenticated user.",
+                "description": "Creates a campaign with default UTM parameters. Links created with its campaign_id get the parameters merged into their destination. utm.campaign defaults to the campaign name; source and medium are lowercased. Campaign names are unique per user, ignoring case.",
                 "consumes": [
                     "application/json"
                 ],
@@ -490,25 +826,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "URLs"
+                    "Campaigns"
                 ],
-                "summary": "Create a new short URL",
+                "summary": "Create a campaign",
                 "parameters": [
                     {
-                        "description": "URL Information",
-                        "name": "url",
+                        "description": "Campaign Information",
+                        "name": "campaign",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateURLRequest"
+                            "$ref": "#/definitions/request.CreateCampaignRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "URL created successfully",
+                        "description": "Campaign created successfully",
                         "schema": {
-                            "$ref": "#/definitions/response.CreateURLSuccessResponse"
+                            "$ref": "#/definitions/response.CampaignSuccessResponse"
                         }
                     },
                     "400": {
@@ -517,14 +853,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIErrorResponse"
-                        }
-                    },
                     "409": {
-                        "description": "Custom alias already exists",
+                        "description": "Campaign name already used",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -532,7 +862,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{url_id}": {
+        "/campaigns/{campaign_id}": {
             "get": {
                 "security": [
                     {
@@ -542,35 +872,29 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the details of a specific short URL owned by the user.",
+                "description": "Retrieves a campaign and its UTM parameters.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "URLs"
+                    "Campaigns"
                 ],
-                "summary": "Get URL details",
+                "summary": "Get a campaign",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "URL ID",
-                        "name": "url_id",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "URL details retrieved successfully",
-                        "schema": {
-                            "$ref": "#/definitions/response.URLDetailsSuccessResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APIErrorResponse"
+                            "$ref": "#/definitions/response.CampaignSuccessResponse"
                         }
                     },
                     "403": {
@@ -580,7 +904,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "URL not found",
+                        "description": "Campaign not found",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -596,7 +920,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates the properties of a specific short URL.",
+                "description": "Renames a campaign or changes its description or UTM parameters. A utm object replaces all of the campaign's parameters. Links already created in the campaign keep the parameters they were created with.",
                 "consumes": [
                     "application/json"
                 ],
@@ -604,33 +928,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "URLs"
+                    "Campaigns"
                 ],
-                "summary": "Update a URL",
+                "summary": "Update a campaign",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "URL ID",
-                        "name": "url_id",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "URL Update Information",
-                        "name": "url",
+                        "description": "Campaign Update Information",
+                        "name": "campaign",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateURLRequest"
+                            "$ref": "#/definitions/request.UpdateCampaignRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "URL updated successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.URLDetailsSuccessResponse"
+                            "$ref": "#/definitions/response.CampaignSuccessResponse"
                         }
                     },
                     "400": {
@@ -646,7 +970,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "URL not found",
+                        "description": "Campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Campaign name already used",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -662,27 +992,27 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes a specific short URL (soft delete).",
+                "description": "Removes a campaign. Its links are kept, with the UTM parameters already in their destinations, but no longer belong to a campaign.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "URLs"
+                    "Campaigns"
                 ],
-                "summary": "Delete a URL",
+                "summary": "Delete a campaign",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "URL ID",
-                        "name": "url_id",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "URL deleted successfully",
+                        "description": "Campaign deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/response.SuccessMessageResponse"
                         }
@@ -694,7 +1024,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "URL not found",
+                        "description": "Campaign not found",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -702,27 +1032,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{url_id}/analytics": {
+        "/campaigns/{campaign_id}/analytics": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves detailed analytics for a specific URL.",
+                "description": "Aggregates the analytics of every link in a campaign, except links in the trash, with the same sections, ranges and filters as the URL analytics plus the campaign's top links. The \"all\" period starts when the campaign was created.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get URL analytics",
+                "summary": "Get campaign analytics",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "URL ID",
-                        "name": "url_id",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
                         "in": "path",
                         "required": true
                     },
@@ -735,16 +1068,123 @@ const docTemplate = `{
                         ],
                         "type": "string",
                         "default": "7d",
-                        "description": "Time period for analytics",
+                        "description": "Preset time period, ignored when from is set",
                         "name": "period",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of a custom range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of a custom range (RFC 3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size of clicks over time; chosen from the range length when omitted",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA time zone for bucketing, e.g. Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_source values",
+                        "name": "utm_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_content values",
+                        "name": "utm_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks sent to these destinations, e.g. geo:US, split:A or default",
+                        "name": "variant",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.URLAnalyticsSuccessResponse"
+                            "$ref": "#/definitions/response.CampaignAnalyticsSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range, granularity or time zone",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
                     "403": {
@@ -754,7 +1194,19 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "URL not found",
+                        "description": "Campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Analytics could not be loaded",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Analytics took too long",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -762,107 +1214,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{url_id}/qr": {
+        "/domains": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves QR code as a base64 string and other info.",
+                "description": "Retrieves every custom domain registered by the authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "QR Codes"
-                ],
-                "summary": "Get QR Code Info",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "URL ID",
-                        "name": "url_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 256,
-                        "description": "QR code size in pixels",
-                        "name": "size",
-                        "in": "query"
-                    }
+                    "Domains"
                 ],
+                "summary": "List custom domains",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.QRCodeSuccessResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/response.APIErrorResponse"
+                            "$ref": "#/definitions/response.DomainListSuccessResponse"
                         }
                     },
-                    "404": {
-                        "description": "URL not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/urls/{url_id}/qr/download": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Downloads the QR code image file.",
-                "produces": [
-                    "image/png"
+                "description": "Claims a branded domain and returns the TXT record that must be published to verify ownership. Several accounts may claim a name that nobody has verified yet; the first to verify gets it. Claims that are not verified within 7 days (domains.claimttl) expire.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "QR Codes"
+                    "Domains"
                 ],
-                "summary": "Download QR Code",
+                "summary": "Register a custom domain",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "URL ID",
-                        "name": "url_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 256,
-                        "description": "QR code size in pixels",
-                        "name": "size",
-                        "in": "query"
+                        "description": "Domain Information",
+                        "name": "domain",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateDomainRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "QR Code Image",
+                    "201": {
+                        "description": "Domain registered successfully",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/response.DomainSuccessResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Validation error",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "URL not found",
+                    "409": {
+                        "description": "Domain already verified by another account, or already claimed by you",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
@@ -870,44 +1300,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/{shortCode}/info": {
+        "/domains/{domain_id}": {
             "get": {
-                "description": "Retrieves public information about a short URL before redirecting.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a custom domain and its verification record.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Redirection"
+                    "Domains"
                 ],
-                "summary": "Get URL info (Preview)",
+                "summary": "Get a custom domain",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Short Code",
-                        "name": "shortCode",
+                        "format": "uuid",
+                        "description": "Domain ID",
+                        "name": "domain_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "URL info retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.URLInfoSuccessResponse"
+                            "$ref": "#/definitions/response.DomainSuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "URL not found",
+                        "description": "Domain not found",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/{shortCode}/unlock": {
-            "post": {
-                "description": "Verifies the password for a short URL and returns the original URL.",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Activates or deactivates a custom domain. Links on an inactive domain stop redirecting.",
                 "consumes": [
                     "application/json"
                 ],
@@ -915,32 +1366,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Redirection"
+                    "Domains"
                 ],
-                "summary": "Unlock a password-protected URL",
+                "summary": "Update a custom domain",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Short Code",
-                        "name": "shortCode",
+                        "format": "uuid",
+                        "description": "Domain ID",
+                        "name": "domain_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Password",
-                        "name": "password",
+                        "description": "Domain Update Information",
+                        "name": "domain",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UnlockURLRequest"
+                            "$ref": "#/definitions/request.UpdateDomainRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "URL unlocked successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.UnlockURLSuccessResponse"
+                            "$ref": "#/definitions/response.DomainSuccessResponse"
                         }
                     },
                     "400": {
@@ -949,195 +1401,4476 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid password",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "URL not found",
+                        "description": "Domain not found",
                         "schema": {
                             "$ref": "#/definitions/response.APIErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "request.ChangePasswordRequest": {
-            "type": "object",
-            "required": [
-                "current_password",
-                "new_password"
-            ],
-            "properties": {
-                "current_password": {
-                    "type": "string"
-                },
-                "new_password": {
-                    "type": "string",
-                    "minLength": 8
-                }
-            }
-        },
-        "request.CreateURLRequest": {
-            "type": "object",
-            "required": [
-                "original_url"
-            ],
-            "properties": {
-                "custom_alias": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "expires_at": {
-                    "type": "string"
-                },
-                "original_url": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                }
-            }
-        },
-        "request.LoginRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a custom domain. Domains that still have links cannot be deleted; links on the domain that are in the trash are purged with it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Delete a custom domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Domain ID",
+                        "name": "domain_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Domain deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Domain still has links",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "request.RefreshTokenRequest": {
-            "type": "object",
-            "required": [
-                "refresh_token"
-            ],
-            "properties": {
-                "refresh_token": {
-                    "type": "string"
+        "/domains/{domain_id}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Checks the domain's verification TXT record and marks the domain as verified when it matches. Expired claims, and names another account has verified in the meantime, cannot be verified.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Verify a custom domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Domain ID",
+                        "name": "domain_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Domain verified successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.DomainSuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Domain already verified by another account",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Claim expired",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Verification record not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "request.RegisterRequest": {
-            "type": "object",
-            "required": [
-                "email",
+        "/folders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves every folder of the authenticated user, sorted by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "List folders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.FolderListSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a folder. Folder names are unique per user, ignoring case.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Create a folder",
+                "parameters": [
+                    {
+                        "description": "Folder Information",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Folder created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.FolderSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Folder name already used",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{folder_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a folder.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Get a folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Folder ID",
+                        "name": "folder_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.FolderSuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renames a folder.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Update a folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Folder ID",
+                        "name": "folder_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Folder Update Information",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.FolderSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Folder name already used",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a folder. Its links are kept outside any folder.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Delete a folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Folder ID",
+                        "name": "folder_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Folder deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{folder_id}/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aggregates the analytics of every link in a folder, except links in the trash, with the same sections, ranges and filters as the URL analytics plus the folder's top links. Clicks are counted whenever they happened, even before the link was moved into the folder. The \"all\" period starts when the folder was created.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get folder analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Folder ID",
+                        "name": "folder_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "24h",
+                            "7d",
+                            "30d",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Preset time period, ignored when from is set",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of a custom range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of a custom range (RFC 3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size of clicks over time; chosen from the range length when omitted",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA time zone for bucketing, e.g. Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_source values",
+                        "name": "utm_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_content values",
+                        "name": "utm_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks sent to these destinations, e.g. geo:US, split:A or default",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.FolderAnalyticsSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range, granularity or time zone",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Analytics could not be loaded",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Analytics took too long",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the profile of the currently logged-in user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProfileSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the first and last name of the currently logged-in user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile Information",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProfileSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the authenticated user's API keys that have not been revoked. Secrets are never returned; keys are identified by their prefix.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIKeyListSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires a bearer token",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a named API key limited to the given scopes (urls:read, urls:write, analytics:read, domains:read, domains:write). The key is only shown in this response; store it securely.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "API Key Information",
+                        "name": "api_key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API key created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIKeySuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires a bearer token",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/api-keys/{key_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes an API key. Requests using it are rejected immediately; other keys keep working.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "API Key ID",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key revoked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the password of the currently logged-in user and signs out every existing session, including the current one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "description": "Password Change Info",
+                        "name": "passwords",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid current password",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/privacy": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets how visitor IP addresses appear in click logs and exports: full, masked to their network (IPv4 /24, IPv6 /48), or hidden.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update privacy settings",
+                "parameters": [
+                    {
+                        "description": "Privacy Settings",
+                        "name": "privacy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdatePrivacyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProfileSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves every tag of the authenticated user, sorted by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.TagListSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a tag. Tag names are unique per user, ignoring case.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create a tag",
+                "parameters": [
+                    {
+                        "description": "Tag Information",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tag created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.TagSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tag name already used",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tag_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a tag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Get a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.TagSuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renames a tag. Links keep the tag under its new name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Update a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag Update Information",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.TagSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tag name already used",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a tag from every link that has it and deletes it. The links themselves are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tag_id}/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aggregates the analytics of every link with a tag, except links in the trash, with the same sections, ranges and filters as the URL analytics plus the tag's top links. Clicks are counted whenever they happened, even before the link was tagged. The \"all\" period starts when the tag was created.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get tag analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "24h",
+                            "7d",
+                            "30d",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Preset time period, ignored when from is set",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of a custom range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of a custom range (RFC 3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size of clicks over time; chosen from the range length when omitted",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA time zone for bucketing, e.g. Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_source values",
+                        "name": "utm_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_content values",
+                        "name": "utm_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks sent to these destinations, e.g. geo:US, split:A or default",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.TagAnalyticsSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range, granularity or time zone",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Analytics could not be loaded",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Analytics took too long",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a paginated, filtered list of URLs for the authenticated user. Pages can be requested by number, or by passing the next_cursor of the previous page as cursor, which stays fast and stable for large lists. A cursor is only valid with the sort and order it was issued for. Times are RFC 3339; \"from\" bounds are inclusive and \"to\" bounds exclusive.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Get user's URLs",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number; ignored when cursor is set",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from pagination.next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query for title or original URL",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "click_count",
+                            "unique_click_count",
+                            "title",
+                            "last_clicked_at"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only active or only inactive links",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only expired or only unexpired links",
+                        "name": "expired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only links with or without a password",
+                        "name": "password_protected",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Created at or after",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Created before",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Last clicked at or after",
+                        "name": "clicked_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Last clicked before",
+                        "name": "clicked_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain name, or 'default' for links on the default domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only links in this campaign",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only links with any of these tags",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only links in this folder, or 'none' for links outside every folder",
+                        "name": "folder_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of URLs retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.URLListSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new short URL for the authenticated user. With campaign_id the link joins the campaign and the campaign's UTM parameters are added to original_url; parameters given in utm override the campaign's. Existing utm_* parameters of original_url are replaced, the rest of its query and fragment are kept. folder_id and tag_ids must be the user's own folder and tags. geo_rules send visitors from the given countries or regions to other destinations; they are tried in order, the first match wins, and everyone else goes to original_url. device_rules send iOS and Android visitors to an app-store URL or a deep link and take precedence over geo_rules; custom-scheme deep links are served through a page that tries to open the app and falls back to fallback_url or the web destination. split_destinations spread the remaining visitors over several weighted destinations, e.g. 70/30, each visitor keeping the destination first assigned while the weights stay the same. max_clicks caps the redirects of the link, counted as they happen so concurrent visitors cannot exceed it; once it is reached the link is deactivated, or sends visitors to cap_fallback_url if set. burn_after_reading allows exactly one redirect. Bots such as link previews are never counted and never get the destination of a capped link. starts_at delays the link's launch and schedule limits it to recurring windows in a time zone, such as business hours; outside them visitors see when the link is available. After expires_at visitors are sent to expired_redirect_url, or shown expired_message.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Create a new short URL",
+                "parameters": [
+                    {
+                        "description": "URL Information",
+                        "name": "url",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "URL created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.CreateURLSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Custom alias already exists",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Monthly link quota reached",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/tags": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds the tags in add to, then removes the tags in remove from, every listed link. Links that are not the user's or are in the trash are skipped; tags a link already has are left alone. Every tag must belong to the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Add or remove tags on many links",
+                "parameters": [
+                    {
+                        "description": "Links and tags",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.BulkTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tags updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.BulkTagSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or unknown tag",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the authenticated user's deleted URLs, most recently deleted first, with the time each one will be purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "List deleted URLs",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted URLs retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.TrashListSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the details of a specific short URL owned by the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Get URL details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL details retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.URLDetailsSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates the properties of a specific short URL. An empty folder_id takes the link out of its folder; tag_ids, geo_rules, device_rules and split_destinations replace all of the link's tags, targeting rules and split destinations, and an empty list removes them. The short code stays the same. max_clicks 0 removes the cap and an empty cap_fallback_url the fallback; a link deactivated by its cap needs is_active true as well as a higher max_clicks. A schedule without windows, an empty expired_redirect_url and an empty expired_message remove them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Update a URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "URL Update Information",
+                        "name": "url",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.URLDetailsSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a short URL to the trash. It stops redirecting at once but keeps its short code and analytics, and can be restored until the retention period ends, after which it is purged permanently.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Delete a URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessMessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves detailed analytics for a specific URL. Dimension filters such as country and device restrict every figure to the matching clicks; repeat a filter to match any of several values. Use period for a preset range ending now, or from and to for a custom range; from is inclusive and to exclusive. Clicks over time are bucketed by granularity in the tz time zone and include empty buckets. Day, week and month buckets are labelled with their local date, shorter buckets with their RFC 3339 start time. A series may have at most 1000 buckets. Referrers are grouped by domain and classified as search, social, email, direct or other; utm breaks clicks down by the UTM parameters of the short-link request. variants counts clicks per destination of links with targeting rules or split destinations, e.g. geo:US, split:A, or default for visitors no rule matched; use it to compare the arms of an A/B split. If a breakdown cannot be loaded it is returned empty and named in warnings; if the overview or the time series cannot be loaded the request fails.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get URL analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "24h",
+                            "7d",
+                            "30d",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Preset time period, ignored when from is set",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of a custom range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of a custom range (RFC 3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size of clicks over time; chosen from the range length when omitted",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA time zone for bucketing, e.g. Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these regions",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these cities",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these browsers",
+                        "name": "browser",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these operating systems",
+                        "name": "os",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks whose preferred language is one of these, e.g. en",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these exact referrers",
+                        "name": "referrer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks referred from these hosts, without www., e.g. twitter.com",
+                        "name": "referrer_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_source values",
+                        "name": "utm_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_medium values",
+                        "name": "utm_medium",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_campaign values",
+                        "name": "utm_campaign",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_term values",
+                        "name": "utm_term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_content values",
+                        "name": "utm_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks sent to these destinations, e.g. geo:US, split:A or default",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.URLAnalyticsSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range, granularity or time zone",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Analytics could not be loaded",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Analytics took too long",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}/clicks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the individual clicks of a URL, newest first. Pass next_cursor from a response as cursor to get the following page. IP addresses are shown according to the account's privacy setting.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get URL click log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only clicks at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only clicks before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Clicks per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these regions",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these cities",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these browsers",
+                        "name": "browser",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these operating systems",
+                        "name": "os",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks whose preferred language is one of these, e.g. en",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these exact referrers",
+                        "name": "referrer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks referred from these hosts, without www.",
+                        "name": "referrer_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_source values",
+                        "name": "utm_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_medium values",
+                        "name": "utm_medium",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_campaign values",
+                        "name": "utm_campaign",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_term values",
+                        "name": "utm_term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_content values",
+                        "name": "utm_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks sent to these destinations, e.g. geo:US, split:A or default",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ClickLogSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}/clicks/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams every matching click of a URL, oldest first, as CSV, NDJSON or a JSON array. Accepts the same time and dimension filters as the click log. IP addresses are shown according to the account's privacy setting. In CSV, text cells starting with =, +, - or @ are prefixed with a single quote so spreadsheets do not run them as formulas.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Export URL clicks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only clicks at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only clicks before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these countries",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these device types",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks referred from these hosts, without www.",
+                        "name": "referrer_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "search",
+                                "social",
+                                "email",
+                                "direct",
+                                "other"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks from these referrer categories",
+                        "name": "referrer_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only clicks with these utm_campaign values",
+                        "name": "utm_campaign",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Click export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}/qr": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves QR code as a base64 string and other info.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "QR Codes"
+                ],
+                "summary": "Get QR Code Info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 256,
+                        "description": "QR code size in pixels",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.QRCodeSuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}/qr/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads the QR code image file.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "QR Codes"
+                ],
+                "summary": "Download QR Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 256,
+                        "description": "QR code size in pixels",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR Code Image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{url_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Takes a URL out of the trash. It redirects again from the same short code, with its analytics intact.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "URLs"
+                ],
+                "summary": "Restore a deleted URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "URL ID",
+                        "name": "url_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.URLDetailsSuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/{shortCode}/info": {
+            "get": {
+                "description": "Retrieves public information about a short URL before redirecting. schedule.state tells whether the link redirects now: available, scheduled before its starts_at, closed outside its availability windows, or expired; available_from says when a link that is not available opens next. Links that are not available, password-protected links and click-capped links leave out original_url and domain; capped links report remaining_clicks. Deactivated links, and links whose cap is used up and that have no fallback, are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Redirection"
+                ],
+                "summary": "Get URL info (Preview)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short Code",
+                        "name": "shortCode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL info retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.URLInfoSuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/{shortCode}/unlock": {
+            "post": {
+                "description": "Checks the password of a short URL. A correct password sets a signed, HttpOnly cookie scoped to the short link's path, which lets the browser through the redirect, recorded as a click, until expires_at. The password form served by the redirect posts here as a form and is sent back to the short link with 303 See Other, or shown the form again with the error; JSON requests get the short URL to open with the cookie. Attempts are limited per link and client IP, and per link from all IPs together; once a limit is reached the password is not checked until Retry-After has passed.",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Redirection"
+                ],
+                "summary": "Unlock a password-protected URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short Code",
+                        "name": "shortCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UnlockURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL unlocked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.UnlockURLSuccessResponse"
+                        }
+                    },
+                    "303": {
+                        "description": "Form unlocked, redirecting to the short link"
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid password",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many password attempts",
+                        "schema": {
+                            "$ref": "#/definitions/response.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "request.BulkTagRequest": {
+            "type": "object",
+            "required": [
+                "url_ids"
+            ],
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url_ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "request.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "request.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "CI deploy bot"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "urls:read",
+                        "urls:write"
+                    ]
+                }
+            }
+        },
+        "request.CreateCampaignRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Spring Sale"
+                },
+                "utm": {
+                    "$ref": "#/definitions/request.UTMRequest"
+                }
+            }
+        },
+        "request.CreateDomainRequest": {
+            "type": "object",
+            "required": [
+                "domain_name"
+            ],
+            "properties": {
+                "domain_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.CreateFolderRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Marketing"
+                }
+            }
+        },
+        "request.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "newsletter"
+                }
+            }
+        },
+        "request.CreateURLRequest": {
+            "type": "object",
+            "required": [
+                "original_url"
+            ],
+            "properties": {
+                "burn_after_reading": {
+                    "type": "boolean"
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "cap_fallback_url": {
+                    "type": "string",
+                    "example": "https://example.com/sold-out"
+                },
+                "custom_alias": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "device_rules": {
+                    "type": "array",
+                    "maxItems": 2,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/request.DeviceRuleRequest"
+                    }
+                },
+                "domain": {
+                    "type": "string",
+                    "example": "links.example.com"
+                },
+                "expired_message": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "This offer has ended."
+                },
+                "expired_redirect_url": {
+                    "type": "string",
+                    "example": "https://example.com/offer-ended"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "folder_id": {
+                    "type": "string"
+                },
+                "geo_rules": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/request.GeoRuleRequest"
+                    }
+                },
+                "max_clicks": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 100
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/request.ScheduleRequest"
+                },
+                "split_destinations": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/request.SplitDestinationRequest"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tag_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "utm": {
+                    "$ref": "#/definitions/request.UTMRequest"
+                }
+            }
+        },
+        "request.DeviceRuleRequest": {
+            "type": "object",
+            "required": [
+                "destination_url",
+                "platform"
+            ],
+            "properties": {
+                "destination_url": {
+                    "type": "string",
+                    "example": "myapp://product/42"
+                },
+                "fallback_url": {
+                    "type": "string",
+                    "example": "https://apps.apple.com/app/id123456789"
+                },
+                "platform": {
+                    "type": "string",
+                    "enum": [
+                        "ios",
+                        "android"
+                    ],
+                    "example": "ios"
+                }
+            }
+        },
+        "request.GeoRuleRequest": {
+            "type": "object",
+            "required": [
+                "country",
+                "destination_url"
+            ],
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "destination_url": {
+                    "type": "string",
+                    "example": "https://example.com/us"
+                },
+                "region": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "California"
+                }
+            }
+        },
+        "request.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
                 "first_name",
                 "last_name",
                 "password"
             ],
             "properties": {
-                "email": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "request.ScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "windows": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/request.ScheduleWindowRequest"
+                    }
+                }
+            }
+        },
+        "request.ScheduleWindowRequest": {
+            "type": "object",
+            "required": [
+                "days",
+                "end",
+                "start"
+            ],
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "maxItems": 7,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri"
+                    ]
+                },
+                "end": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "09:00"
+                }
+            }
+        },
+        "request.SplitDestinationRequest": {
+            "type": "object",
+            "required": [
+                "destination_url",
+                "name",
+                "weight"
+            ],
+            "properties": {
+                "destination_url": {
+                    "type": "string",
+                    "example": "https://example.com/landing-a"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "A"
+                },
+                "weight": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 70
+                }
+            }
+        },
+        "request.UTMRequest": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "spring_sale"
+                },
+                "content": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "medium": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "email"
+                },
+                "source": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "newsletter"
+                },
+                "term": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "request.UnlockURLRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.UpdateCampaignRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "utm": {
+                    "$ref": "#/definitions/request.UTMRequest"
+                }
+            }
+        },
+        "request.UpdateDomainRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "request.UpdateFolderRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Marketing"
+                }
+            }
+        },
+        "request.UpdatePrivacyRequest": {
+            "type": "object",
+            "required": [
+                "ip_privacy"
+            ],
+            "properties": {
+                "ip_privacy": {
+                    "type": "string",
+                    "enum": [
+                        "full",
+                        "masked",
+                        "hidden"
+                    ],
+                    "example": "masked"
+                }
+            }
+        },
+        "request.UpdateProfileRequest": {
+            "type": "object",
+            "required": [
+                "first_name",
+                "last_name"
+            ],
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.UpdateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "newsletter"
+                }
+            }
+        },
+        "request.UpdateURLRequest": {
+            "type": "object",
+            "properties": {
+                "cap_fallback_url": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "device_rules": {
+                    "type": "array",
+                    "maxItems": 2,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/request.DeviceRuleRequest"
+                    }
+                },
+                "expired_message": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "expired_redirect_url": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "folder_id": {
+                    "type": "string"
+                },
+                "geo_rules": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/request.GeoRuleRequest"
+                    }
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "schedule": {
+                    "$ref": "#/definitions/request.ScheduleRequest"
+                },
+                "split_destinations": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/request.SplitDestinationRequest"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tag_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.APIErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/response.ErrorPayload"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.APIKeyListSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.APIKeyResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "usk_3f9a1c2b"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.APIKeySuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.CreatedAPIKeyResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "API key created successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.AnalyticsOverview": {
+            "type": "object",
+            "properties": {
+                "top_country": {
+                    "type": "string"
+                },
+                "top_referrer": {
+                    "type": "string"
+                },
+                "total_clicks": {
+                    "type": "integer"
+                },
+                "unique_clicks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.AnalyticsRange": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "UTC"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.AnalyticsWarning": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "This section could not be loaded"
+                },
+                "section": {
+                    "type": "string",
+                    "example": "referrers"
+                }
+            }
+        },
+        "response.BulkOperationResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error_details": {
+                    "type": "string"
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "file_format": {
+                    "type": "string",
+                    "example": "csv"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "operation_type": {
+                    "type": "string",
+                    "example": "create"
+                },
+                "processed_count": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "result_url": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "processing"
+                },
+                "success_count": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BulkOperationSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.BulkOperationResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BulkTagResponse": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BulkTagSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.BulkTagResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CampaignAnalyticsResponse": {
+            "type": "object",
+            "properties": {
+                "browsers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
+                "clicks_over_time": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TimeSeriesStat"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operating_systems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "overview": {
+                    "$ref": "#/definitions/response.AnalyticsOverview"
+                },
+                "range": {
+                    "$ref": "#/definitions/response.AnalyticsRange"
+                },
+                "referrer_categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "referrers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
+                "top_urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DashboardTopURL"
+                    }
+                },
+                "total_urls": {
+                    "type": "integer"
+                },
+                "utm": {
+                    "$ref": "#/definitions/response.UTMBreakdown"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AnalyticsWarning"
+                    }
+                }
+            }
+        },
+        "response.CampaignAnalyticsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.CampaignAnalyticsResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CampaignListSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.CampaignResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CampaignResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "utm": {
+                    "$ref": "#/definitions/response.UTMResponse"
+                }
+            }
+        },
+        "response.CampaignSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.CampaignResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ClickLogResponse": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.ClickResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ClickLogSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.ClickLogResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ClickResponse": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "clicked_at": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "device_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "is_unique": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "referrer": {
+                    "type": "string"
+                },
+                "referrer_category": {
+                    "type": "string"
+                },
+                "referrer_domain": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "short_code": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "utm_campaign": {
+                    "type": "string"
+                },
+                "utm_content": {
+                    "type": "string"
+                },
+                "utm_medium": {
+                    "type": "string"
+                },
+                "utm_source": {
+                    "type": "string"
+                },
+                "utm_term": {
+                    "type": "string"
+                },
+                "variant": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CreateURLResponse": {
+            "type": "object",
+            "properties": {
+                "campaign_id": {
+                    "type": "string"
+                },
+                "cap_fallback_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_alias": {
+                    "type": "string"
+                },
+                "device_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DeviceRuleResponse"
+                    }
+                },
+                "expired_message": {
+                    "type": "string"
+                },
+                "expired_redirect_url": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "folder_id": {
+                    "type": "string"
+                },
+                "geo_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GeoRuleResponse"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "qr_code": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/response.ScheduleResponse"
+                },
+                "short_code": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "split_destinations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.SplitDestinationResponse"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TagResponse"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CreateURLSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.CreateURLResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Short URL created successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.CreatedAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "usk_3f9a1c2b"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.DashboardActivityItem": {
+            "type": "object",
+            "properties": {
+                "last_clicked_at": {
+                    "type": "string"
+                },
+                "short_code": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DashboardLinkGroup": {
+            "type": "object",
+            "properties": {
+                "click_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unique_click_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.DashboardSummary": {
+            "type": "object",
+            "properties": {
+                "active_urls": {
+                    "type": "integer"
+                },
+                "total_clicks": {
+                    "type": "integer"
+                },
+                "total_unique_clicks": {
+                    "type": "integer"
+                },
+                "total_urls": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.DashboardTopURL": {
+            "type": "object",
+            "properties": {
+                "click_count": {
+                    "type": "integer"
+                },
+                "short_code": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unique_click_count": {
+                    "type": "integer"
+                },
+                "url_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DeviceRuleResponse": {
+            "type": "object",
+            "properties": {
+                "destination_url": {
+                    "type": "string",
+                    "example": "myapp://product/42"
+                },
+                "fallback_url": {
+                    "type": "string",
+                    "example": "https://apps.apple.com/app/id123456789"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                }
+            }
+        },
+        "response.DomainListSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DomainResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DomainResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "first_name": {
+                "domain_name": {
                     "type": "string"
                 },
-                "last_name": {
+                "id": {
                     "type": "string"
                 },
-                "password": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
+                "verification_record": {
+                    "$ref": "#/definitions/response.DomainVerificationRecord"
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DomainSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.DomainResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DomainVerificationRecord": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string",
-                    "minLength": 8
+                    "example": "TXT"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "request.UnlockURLRequest": {
+        "response.ErrorDetail": {
             "type": "object",
-            "required": [
-                "password"
-            ],
             "properties": {
-                "password": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "request.UpdateProfileRequest": {
+        "response.ErrorPayload": {
             "type": "object",
-            "required": [
-                "first_name",
-                "last_name"
-            ],
             "properties": {
-                "first_name": {
+                "code": {
                     "type": "string"
                 },
-                "last_name": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.ErrorDetail"
+                    }
+                },
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "request.UpdateURLRequest": {
+        "response.FolderAnalyticsResponse": {
             "type": "object",
             "properties": {
-                "description": {
+                "browsers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
+                "clicks_over_time": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TimeSeriesStat"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "folder_id": {
                     "type": "string"
                 },
-                "expires_at": {
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "operating_systems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
                 },
-                "title": {
+                "overview": {
+                    "$ref": "#/definitions/response.AnalyticsOverview"
+                },
+                "range": {
+                    "$ref": "#/definitions/response.AnalyticsRange"
+                },
+                "referrer_categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "referrers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
+                "top_urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DashboardTopURL"
+                    }
+                },
+                "total_urls": {
+                    "type": "integer"
+                },
+                "utm": {
+                    "$ref": "#/definitions/response.UTMBreakdown"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AnalyticsWarning"
+                    }
+                }
+            }
+        },
+        "response.FolderAnalyticsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.FolderAnalyticsResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
                     "type": "string"
                 }
             }
         },
-        "response.APIErrorResponse": {
+        "response.FolderListSuccessResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/response.ErrorPayload"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FolderResponse"
+                    }
                 },
-                "request_id": {
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.FolderResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.FolderSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.FolderResponse"
+                },
+                "message": {
                     "type": "string"
                 },
                 "success": {
-                    "type": "boolean"
+                    "type": "boolean",
+                    "example": true
                 },
                 "timestamp": {
                     "type": "string"
                 }
             }
         },
-        "response.APIKeyResponse": {
+        "response.GeoRuleResponse": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "destination_url": {
+                    "type": "string",
+                    "example": "https://example.com/us"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "California"
+                }
+            }
+        },
+        "response.GroupedStat": {
             "type": "object",
             "properties": {
-                "api_key": {
+                "count": {
+                    "type": "integer"
+                },
+                "unique_count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.LocationStat": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "unique_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
                     "type": "string"
                 },
-                "generated_at": {
-                    "type": "string"
+                "user": {
+                    "$ref": "#/definitions/response.UserResponse"
                 }
             }
         },
-        "response.APIKeySuccessResponse": {
+        "response.LoginSuccessResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/response.APIKeyResponse"
+                    "$ref": "#/definitions/response.LoginResponse"
                 },
                 "message": {
                     "type": "string",
-                    "example": "API key regenerated successfully"
+                    "example": "Login successful"
                 },
                 "success": {
                     "type": "boolean",
@@ -1148,61 +5881,63 @@ const docTemplate = `{
                 }
             }
         },
-        "response.AnalyticsOverview": {
+        "response.PaginationResponse": {
             "type": "object",
             "properties": {
-                "top_country": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "top_referrer": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "total_clicks": {
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
                     "type": "integer"
                 }
             }
         },
-        "response.CreateURLResponse": {
+        "response.ProfileSuccessResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/response.UserResponse"
                 },
-                "custom_alias": {
-                    "type": "string"
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "expires_at": {
+                "timestamp": {
                     "type": "string"
-                },
-                "id": {
+                }
+            }
+        },
+        "response.QRCodeResponse": {
+            "type": "object",
+            "properties": {
+                "download_url": {
                     "type": "string"
                 },
-                "original_url": {
+                "format": {
                     "type": "string"
                 },
                 "qr_code": {
                     "type": "string"
                 },
-                "short_code": {
-                    "type": "string"
-                },
-                "short_url": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                "size": {
+                    "type": "integer"
                 }
             }
         },
-        "response.CreateURLSuccessResponse": {
+        "response.QRCodeSuccessResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/response.CreateURLResponse"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "Short URL created successfully"
+                    "$ref": "#/definitions/response.QRCodeResponse"
                 },
                 "success": {
                     "type": "boolean",
@@ -1213,116 +5948,122 @@ const docTemplate = `{
                 }
             }
         },
-        "response.DashboardActivityItem": {
+        "response.RefreshTokenResponse": {
             "type": "object",
             "properties": {
-                "last_clicked_at": {
-                    "type": "string"
-                },
-                "short_code": {
-                    "type": "string"
-                },
-                "title": {
+                "access_token": {
                     "type": "string"
                 },
-                "url_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "response.DashboardSummary": {
-            "type": "object",
-            "properties": {
-                "active_urls": {
+                "expires_in": {
                     "type": "integer"
                 },
-                "total_clicks": {
-                    "type": "integer"
+                "refresh_token": {
+                    "type": "string"
                 },
-                "total_urls": {
-                    "type": "integer"
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
                 }
             }
         },
-        "response.DashboardTopURL": {
+        "response.RefreshTokenSuccessResponse": {
             "type": "object",
             "properties": {
-                "click_count": {
-                    "type": "integer"
-                },
-                "short_code": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/response.RefreshTokenResponse"
                 },
-                "title": {
-                    "type": "string"
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "url_id": {
+                "timestamp": {
                     "type": "string"
                 }
             }
         },
-        "response.ErrorDetail": {
+        "response.RegisterSuccessResponse": {
             "type": "object",
             "properties": {
-                "field": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/response.UserResponse"
                 },
                 "message": {
+                    "type": "string",
+                    "example": "User registered successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
                     "type": "string"
                 }
             }
         },
-        "response.ErrorPayload": {
+        "response.ScheduleResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string"
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
                 },
-                "details": {
+                "windows": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/response.ErrorDetail"
+                        "$ref": "#/definitions/response.ScheduleWindowResponse"
                     }
-                },
-                "message": {
-                    "type": "string"
                 }
             }
         },
-        "response.GroupedStat": {
+        "response.ScheduleWindowResponse": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri"
+                    ]
+                },
+                "end": {
+                    "type": "string",
+                    "example": "17:00"
                 },
-                "value": {
-                    "type": "string"
+                "start": {
+                    "type": "string",
+                    "example": "09:00"
                 }
             }
         },
-        "response.LoginResponse": {
+        "response.SplitDestinationResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "destination_url": {
+                    "type": "string",
+                    "example": "https://example.com/landing-a"
                 },
-                "refresh_token": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "A"
                 },
-                "user": {
-                    "$ref": "#/definitions/response.UserResponse"
+                "weight": {
+                    "type": "integer",
+                    "example": 70
                 }
             }
         },
-        "response.LoginSuccessResponse": {
+        "response.SuccessMessageResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/response.LoginResponse"
-                },
                 "message": {
                     "type": "string",
-                    "example": "Login successful"
+                    "example": "Operation successful"
                 },
                 "success": {
                     "type": "boolean",
@@ -1333,28 +6074,139 @@ const docTemplate = `{
                 }
             }
         },
-        "response.PaginationResponse": {
+        "response.TagAnalyticsResponse": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
+                "browsers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
                 },
-                "page": {
-                    "type": "integer"
+                "cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
                 },
-                "total": {
-                    "type": "integer"
+                "clicks_over_time": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TimeSeriesStat"
+                    }
                 },
-                "total_pages": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operating_systems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "overview": {
+                    "$ref": "#/definitions/response.AnalyticsOverview"
+                },
+                "range": {
+                    "$ref": "#/definitions/response.AnalyticsRange"
+                },
+                "referrer_categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "referrers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
+                "tag_id": {
+                    "type": "string"
+                },
+                "top_urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DashboardTopURL"
+                    }
+                },
+                "total_urls": {
                     "type": "integer"
+                },
+                "utm": {
+                    "$ref": "#/definitions/response.UTMBreakdown"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AnalyticsWarning"
+                    }
+                }
+            }
+        },
+        "response.TagAnalyticsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/response.TagAnalyticsResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timestamp": {
+                    "type": "string"
                 }
             }
         },
-        "response.ProfileSuccessResponse": {
+        "response.TagListSuccessResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/response.UserResponse"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TagResponse"
+                    }
                 },
                 "success": {
                     "type": "boolean",
@@ -1365,28 +6217,28 @@ const docTemplate = `{
                 }
             }
         },
-        "response.QRCodeResponse": {
+        "response.TagResponse": {
             "type": "object",
             "properties": {
-                "download_url": {
+                "created_at": {
                     "type": "string"
                 },
-                "format": {
+                "id": {
                     "type": "string"
                 },
-                "qr_code": {
+                "name": {
                     "type": "string"
-                },
-                "size": {
-                    "type": "integer"
                 }
             }
         },
-        "response.QRCodeSuccessResponse": {
+        "response.TagSuccessResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/response.QRCodeResponse"
+                    "$ref": "#/definitions/response.TagResponse"
+                },
+                "message": {
+                    "type": "string"
                 },
                 "success": {
                     "type": "boolean",
@@ -1397,61 +6249,71 @@ const docTemplate = `{
                 }
             }
         },
-        "response.RefreshTokenResponse": {
+        "response.TimeSeriesStat": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "clicks": {
+                    "type": "integer"
+                },
+                "date": {
                     "type": "string"
                 },
-                "expires_in": {
+                "unique_clicks": {
                     "type": "integer"
-                },
-                "token_type": {
-                    "type": "string",
-                    "example": "Bearer"
                 }
             }
         },
-        "response.RefreshTokenSuccessResponse": {
+        "response.TrashItemResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/response.RefreshTokenResponse"
+                "click_count": {
+                    "type": "integer"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "created_at": {
+                    "type": "string"
                 },
-                "timestamp": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "type": "string"
+                },
+                "short_code": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "response.RegisterSuccessResponse": {
+        "response.TrashListResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/response.UserResponse"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "User registered successfully"
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "pagination": {
+                    "$ref": "#/definitions/response.PaginationResponse"
                 },
-                "timestamp": {
-                    "type": "string"
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TrashItemResponse"
+                    }
                 }
             }
         },
-        "response.SuccessMessageResponse": {
+        "response.TrashListSuccessResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Operation successful"
+                "data": {
+                    "$ref": "#/definitions/response.TrashListResponse"
                 },
                 "success": {
                     "type": "boolean",
@@ -1462,17 +6324,6 @@ const docTemplate = `{
                 }
             }
         },
-        "response.TimeSeriesStat": {
-            "type": "object",
-            "properties": {
-                "clicks": {
-                    "type": "integer"
-                },
-                "date": {
-                    "type": "string"
-                }
-            }
-        },
         "response.URLAnalyticsResponse": {
             "type": "object",
             "properties": {
@@ -1482,6 +6333,12 @@ const docTemplate = `{
                         "$ref": "#/definitions/response.GroupedStat"
                     }
                 },
+                "cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
                 "clicks_over_time": {
                     "type": "array",
                     "items": {
@@ -1500,6 +6357,21 @@ const docTemplate = `{
                         "$ref": "#/definitions/response.GroupedStat"
                     }
                 },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
                 "operating_systems": {
                     "type": "array",
                     "items": {
@@ -1509,11 +6381,41 @@ const docTemplate = `{
                 "overview": {
                     "$ref": "#/definitions/response.AnalyticsOverview"
                 },
+                "range": {
+                    "$ref": "#/definitions/response.AnalyticsRange"
+                },
+                "referrer_categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
                 "referrers": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/response.GroupedStat"
                     }
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LocationStat"
+                    }
+                },
+                "utm": {
+                    "$ref": "#/definitions/response.UTMBreakdown"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AnalyticsWarning"
+                    }
                 }
             }
         },
@@ -1535,6 +6437,12 @@ const docTemplate = `{
         "response.URLDetailsResponse": {
             "type": "object",
             "properties": {
+                "campaign_id": {
+                    "type": "string"
+                },
+                "cap_fallback_url": {
+                    "type": "string"
+                },
                 "click_count": {
                     "type": "integer"
                 },
@@ -1547,9 +6455,30 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "device_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DeviceRuleResponse"
+                    }
+                },
+                "expired_message": {
+                    "type": "string"
+                },
+                "expired_redirect_url": {
+                    "type": "string"
+                },
                 "expires_at": {
                     "type": "string"
                 },
+                "folder_id": {
+                    "type": "string"
+                },
+                "geo_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GeoRuleResponse"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
@@ -1562,15 +6491,43 @@ const docTemplate = `{
                 "last_clicked_at": {
                     "type": "string"
                 },
+                "max_clicks": {
+                    "type": "integer"
+                },
                 "original_url": {
                     "type": "string"
                 },
+                "remaining_clicks": {
+                    "type": "integer"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/response.ScheduleResponse"
+                },
+                "schedule_state": {
+                    "type": "string",
+                    "example": "available"
+                },
                 "short_code": {
                     "type": "string"
                 },
                 "short_url": {
                     "type": "string"
                 },
+                "split_destinations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.SplitDestinationResponse"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TagResponse"
+                    }
+                },
                 "title": {
                     "type": "string"
                 },
@@ -1612,12 +6569,21 @@ const docTemplate = `{
                 "domain": {
                     "type": "string"
                 },
+                "is_password_protected": {
+                    "type": "boolean"
+                },
                 "is_safe": {
                     "type": "boolean"
                 },
                 "original_url": {
                     "type": "string"
                 },
+                "remaining_clicks": {
+                    "type": "integer"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/response.URLScheduleStatusResponse"
+                },
                 "short_url": {
                     "type": "string"
                 },
@@ -1644,6 +6610,9 @@ const docTemplate = `{
         "response.URLListItemResponse": {
             "type": "object",
             "properties": {
+                "campaign_id": {
+                    "type": "string"
+                },
                 "click_count": {
                     "type": "integer"
                 },
@@ -1653,6 +6622,9 @@ const docTemplate = `{
                 "expires_at": {
                     "type": "string"
                 },
+                "folder_id": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -1668,8 +6640,17 @@ const docTemplate = `{
                 "short_url": {
                     "type": "string"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TagResponse"
+                    }
+                },
                 "title": {
                     "type": "string"
+                },
+                "unique_click_count": {
+                    "type": "integer"
                 }
             }
         },
@@ -1702,10 +6683,97 @@ const docTemplate = `{
                 }
             }
         },
+        "response.URLScheduleStatusResponse": {
+            "type": "object",
+            "properties": {
+                "available_from": {
+                    "type": "string"
+                },
+                "available_until": {
+                    "type": "string"
+                },
+                "expired_message": {
+                    "type": "string",
+                    "example": "This offer has ended."
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "available",
+                        "scheduled",
+                        "closed",
+                        "expired"
+                    ],
+                    "example": "scheduled"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "response.UTMBreakdown": {
+            "type": "object",
+            "properties": {
+                "campaigns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "contents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "mediums": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                },
+                "terms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.GroupedStat"
+                    }
+                }
+            }
+        },
+        "response.UTMResponse": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "type": "string",
+                    "example": "spring_sale"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "medium": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "newsletter"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
         "response.UnlockURLResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "expires_at": {
                     "type": "string"
                 },
                 "redirect_url": {
@@ -1731,6 +6799,15 @@ const docTemplate = `{
         "response.UserDashboardResponse": {
             "type": "object",
             "properties": {
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
                 "recent_activity": {
                     "type": "array",
                     "items": {
@@ -1740,11 +6817,29 @@ const docTemplate = `{
                 "summary": {
                     "$ref": "#/definitions/response.DashboardSummary"
                 },
+                "top_folders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DashboardLinkGroup"
+                    }
+                },
                 "top_performing_urls": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/response.DashboardTopURL"
                     }
+                },
+                "top_tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DashboardLinkGroup"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AnalyticsWarning"
+                    }
                 }
             }
         },
@@ -1766,9 +6861,6 @@ const docTemplate = `{
         "response.UserResponse": {
             "type": "object",
             "properties": {
-                "api_key": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
@@ -1781,6 +6873,10 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "ip_privacy": {
+                    "type": "string",
+                    "example": "masked"
+                },
                 "last_name": {
                     "type": "string"
                 },
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes that can be granted to an API key. Requests authenticated with an
+// access token are not scoped and may do anything the user can.
+const (
+	ScopeURLsRead      = "urls:read"
+	ScopeURLsWrite     = "urls:write"
+	ScopeAnalyticsRead = "analytics:read"
+	ScopeDomainsRead   = "domains:read"
+	ScopeDomainsWrite  = "domains:write"
+)
+
+// APIKeyScopes lists every scope an API key may be granted.
+var APIKeyScopes = []string{ScopeURLsRead, ScopeURLsWrite, ScopeAnalyticsRead, ScopeDomainsRead, ScopeDomainsWrite}
+
+// APIKey is a named credential for integrations. Only the SHA-256 hash of
+// the secret is stored; Prefix is its first characters, kept so the owner can
+// tell keys apart.
+type APIKey struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	Name       string    `gorm:"not null"`
+	Prefix     string    `gorm:"not null"`
+	KeyHash    string    `gorm:"unique;not null"`
+	Scopes     []string  `gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// IsActive reports whether the key may still be used to authenticate.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope reports whether the key was granted scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyRepository interface {
+	Store(key *APIKey) error
+	FindByID(id uuid.UUID) (*APIKey, error)
+	FindByHash(keyHash string) (*APIKey, error)
+	// FindByUserID returns the user's keys that have not been revoked,
+	// newest first. Expired keys are included.
+	FindByUserID(userID uuid.UUID) ([]APIKey, error)
+	Revoke(id uuid.UUID) error
+	TouchLastUsed(id uuid.UUID, at time.Time) error
+}
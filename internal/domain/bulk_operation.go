@@ -6,18 +6,40 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	BulkOperationCreate     = "create"
+	BulkOperationUpdate     = "update"
+	BulkOperationDeactivate = "deactivate"
+	BulkOperationDelete     = "delete"
+
+	BulkStatusPending    = "pending"
+	BulkStatusProcessing = "processing"
+	BulkStatusCompleted  = "completed"
+	BulkStatusFailed     = "failed"
+)
+
 type BulkOperation struct {
 	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	UserID         uuid.UUID `gorm:"type:uuid;not null"`
 	OperationType  string    `gorm:"not null"`
+	FileFormat     string    `gorm:"not null"`
 	TotalCount     int       `gorm:"not null"`
+	ProcessedCount int       `gorm:"default:0"`
 	SuccessCount   int       `gorm:"default:0"`
 	FailedCount    int       `gorm:"default:0"`
 	Status         string    `gorm:"default:'pending'"`
-	FilePath       *string
+	// NodeID is the instance that holds the input file; nil when uploads
+	// are on storage every instance shares.
+	NodeID   *string
+	FilePath *string
+	// ResultFileSize is the size of the result file at the last
+	// checkpoint. A resumed job drops anything written after it.
+	ResultFileSize int64 `gorm:"not null;default:0"`
 	ResultFilePath *string
 	ErrorDetails   *string
-	StartedAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	StartedAt      *time.Time
 	CompletedAt    *time.Time
 }
 
@@ -25,4 +47,10 @@ type BulkOperationRepository interface {
 	Store(op *BulkOperation) error
 	FindByID(id uuid.UUID) (*BulkOperation, error)
 	Update(op *BulkOperation) error
+	// ClaimNext atomically moves the oldest runnable operation pinned to
+	// node, or any operation when node is "", to processing and returns it.
+	// Operations stuck in processing since before staleBefore are
+	// considered abandoned and can be claimed again. Returns
+	// gorm.ErrRecordNotFound when there is nothing to do.
+	ClaimNext(node string, staleBefore time.Time) (*BulkOperation, error)
 }
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UTMParams are the utm_* query parameters of a link. Empty fields are not
+// set.
+type UTMParams struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Term     string
+	Content  string
+}
+
+// Override returns p with every non-empty field of o in place of its own.
+func (p UTMParams) Override(o UTMParams) UTMParams {
+	if o.Source != "" {
+		p.Source = o.Source
+	}
+	if o.Medium != "" {
+		p.Medium = o.Medium
+	}
+	if o.Campaign != "" {
+		p.Campaign = o.Campaign
+	}
+	if o.Term != "" {
+		p.Term = o.Term
+	}
+	if o.Content != "" {
+		p.Content = o.Content
+	}
+	return p
+}
+
+// Campaign groups links that share default UTM parameters. The parameters
+// are merged into the destination of each link created in the campaign;
+// changing them later does not rewrite existing links.
+type Campaign struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null"`
+	Name        string    `gorm:"not null"`
+	Description *string
+	UTM         UTMParams `gorm:"embedded;embeddedPrefix:utm_"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CampaignRepository interface {
+	Store(campaign *Campaign) error
+	FindByID(id uuid.UUID) (*Campaign, error)
+	FindByUserIDAndName(userID uuid.UUID, name string) (*Campaign, error)
+	FindAllByUserID(userID uuid.UUID) ([]Campaign, error)
+	Update(campaign *Campaign) error
+	Delete(campaign *Campaign) error
+}
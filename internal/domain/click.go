@@ -1,46 +1,192 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Click is one recorded redirect. ReferrerDomain and ReferrerCategory are
+// derived from Referer when the click is recorded (see pkg/referrer); the UTM
+// fields are the utm_* parameters of the short-link request. Variant names
+// the destination the visitor was sent to when the link has targeting rules.
 type Click struct {
-	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	URLID      uuid.UUID `gorm:"type:uuid;not null"`
-	IPAddress  string
-	UserAgent  string
-	Referer    string
-	Country    string
-	Region     string
-	City       string
-	Browser    string
-	OS         string
-	DeviceType string
-	IsUnique   bool `gorm:"default:false"`
-	ClickedAt  time.Time
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	URLID            uuid.UUID `gorm:"type:uuid;not null"`
+	IPAddress        string
+	UserAgent        string
+	Referer          string
+	ReferrerDomain   string
+	ReferrerCategory string
+	UTMSource        string
+	UTMMedium        string
+	UTMCampaign      string
+	UTMTerm          string
+	UTMContent       string
+	Variant          string
+	Country          string
+	Region           string
+	City             string
+	Browser          string
+	OS               string
+	DeviceType       string
+	Language         string
+	VisitorHash      string
+	IsUnique         bool `gorm:"default:false"`
+	ClickedAt        time.Time
 }
 
+// VisitorKey identifies one visitor on one URL for unique-click detection.
+type VisitorKey struct {
+	URLID       uuid.UUID
+	VisitorHash string
+}
+
+// Time-series bucket sizes. Weeks start on Monday.
+const (
+	GranularityMinute = "minute"
+	GranularityHour   = "hour"
+	GranularityDay    = "day"
+	GranularityWeek   = "week"
+	GranularityMonth  = "month"
+)
+
+// TimeRange selects clicks with From <= clicked_at < To. A zero bound is
+// open.
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Click dimensions that analytics can be filtered and grouped by.
+const (
+	ClickDimensionCountry          = "country"
+	ClickDimensionLanguage         = "language"
+	ClickDimensionRegion           = "region"
+	ClickDimensionCity             = "city"
+	ClickDimensionDevice           = "device"
+	ClickDimensionBrowser          = "browser"
+	ClickDimensionOS               = "os"
+	ClickDimensionReferrer         = "referrer"
+	ClickDimensionReferrerDomain   = "referrer_domain"
+	ClickDimensionReferrerCategory = "referrer_category"
+	ClickDimensionUTMSource        = "utm_source"
+	ClickDimensionUTMMedium        = "utm_medium"
+	ClickDimensionUTMCampaign      = "utm_campaign"
+	ClickDimensionUTMTerm          = "utm_term"
+	ClickDimensionUTMContent       = "utm_content"
+	ClickDimensionVariant          = "variant"
+)
+
+// ClickFilter selects the clicks analytics are computed over: those of one
+// link, of every link in a campaign, tag or folder, or of every link a user
+// owns, within Range. Dimensions maps a
+// ClickDimension to the values it may take; a click must match one value of
+// every listed dimension.
+type ClickFilter struct {
+	URLID      *uuid.UUID
+	CampaignID *uuid.UUID
+	TagID      *uuid.UUID
+	FolderID   *uuid.UUID
+	UserID     *uuid.UUID
+	Range      TimeRange
+	Dimensions map[string][]string
+}
+
+// ClickCursor is the position of the last click on a click log page. Pages
+// are ordered newest first, by clicked_at and then id.
+type ClickCursor struct {
+	ClickedAt time.Time
+	ID        uuid.UUID
+}
+
+// ClickLogEntry is a click together with the short code of its link.
+type ClickLogEntry struct {
+	Click
+	ShortCode string
+}
+
+// LocationResult is a click count for a region, or a city when City is set,
+// together with the country it lies in.
+type LocationResult struct {
+	Country     string
+	Region      string
+	City        string
+	Count       int64
+	UniqueCount int64
+}
+
+// TopURLResult is a link ranked by the clicks matching a ClickFilter.
+type TopURLResult struct {
+	URLID       uuid.UUID
+	ShortCode   string
+	Title       *string
+	Count       int64
+	UniqueCount int64
+}
+
+// Groups of links that clicks can be rolled up by.
+const (
+	LinkGroupTag    = "tag"
+	LinkGroupFolder = "folder"
+)
+
+// LinkGroupResult is a tag or folder ranked by the clicks on its links.
+type LinkGroupResult struct {
+	ID          uuid.UUID
+	Name        string
+	Count       int64
+	UniqueCount int64
+}
+
+// TimeSeriesResult is one bucket of a click time series. Bucket is the
+// bucket's start as wall-clock time in the requested time zone, carried in a
+// time.Time whose location is meaningless.
 type TimeSeriesResult struct {
-	Date  time.Time `gorm:"type:date"`
-	Count int64
+	Bucket      time.Time
+	Count       int64
+	UniqueCount int64
 }
 
 type GroupedResult struct {
-	Value string
-	Count int64
+	Value       string
+	Count       int64
+	UniqueCount int64
 }
 
 type ClickRepository interface {
 	Store(click *Click) error
-	GetTotalClicks(urlID uuid.UUID, since time.Time) (int64, error)
-	GetTopReferrer(urlID uuid.UUID, since time.Time) (string, error)
-	GetTopCountry(urlID uuid.UUID, since time.Time) (string, error)
-	GetClicksOverTime(urlID uuid.UUID, since time.Time) ([]TimeSeriesResult, error)
-	GetTopCountries(urlID uuid.UUID, since time.Time, limit int) ([]GroupedResult, error)
-	GetTopReferrers(urlID uuid.UUID, since time.Time, limit int) ([]GroupedResult, error)
-	GetDeviceStats(urlID uuid.UUID, since time.Time) ([]GroupedResult, error)
-	GetBrowserStats(urlID uuid.UUID, since time.Time) ([]GroupedResult, error)
-	GetOSStats(urlID uuid.UUID, since time.Time) ([]GroupedResult, error)
+	StoreBatch(clicks []Click) error
+	FindSeenVisitors(keys []VisitorKey, since time.Time) ([]VisitorKey, error)
+	GetTotalClicks(ctx context.Context, filter ClickFilter) (int64, error)
+	GetUniqueClicks(ctx context.Context, filter ClickFilter) (int64, error)
+	// GetTopReferrer and GetTopReferrers rank referrer domains.
+	GetTopReferrer(ctx context.Context, filter ClickFilter) (string, error)
+	GetTopCountry(ctx context.Context, filter ClickFilter) (string, error)
+	// GetClicksOverTime buckets the matching clicks by granularity in the
+	// IANA time zone timeZone. Every bucket overlapping filter.Range, which
+	// must be closed, is returned in order, including buckets without clicks.
+	GetClicksOverTime(ctx context.Context, filter ClickFilter, granularity, timeZone string) ([]TimeSeriesResult, error)
+	GetTopCountries(ctx context.Context, filter ClickFilter, limit int) ([]GroupedResult, error)
+	GetTopReferrers(ctx context.Context, filter ClickFilter, limit int) ([]GroupedResult, error)
+	GetDeviceStats(ctx context.Context, filter ClickFilter) ([]GroupedResult, error)
+	GetBrowserStats(ctx context.Context, filter ClickFilter) ([]GroupedResult, error)
+	GetOSStats(ctx context.Context, filter ClickFilter) ([]GroupedResult, error)
+	GetLanguageStats(ctx context.Context, filter ClickFilter) ([]GroupedResult, error)
+	// GetDimensionStats returns the most frequent values of a ClickDimension.
+	GetDimensionStats(ctx context.Context, filter ClickFilter, dimension string, limit int) ([]GroupedResult, error)
+	GetTopRegions(ctx context.Context, filter ClickFilter, limit int) ([]LocationResult, error)
+	GetTopCities(ctx context.Context, filter ClickFilter, limit int) ([]LocationResult, error)
+	GetTopURLs(ctx context.Context, filter ClickFilter, limit int) ([]TopURLResult, error)
+	// GetLinkGroupStats ranks tags or folders by the matching clicks on
+	// their links.
+	GetLinkGroupStats(ctx context.Context, filter ClickFilter, group string, limit int) ([]LinkGroupResult, error)
+	// FindLog returns up to limit matching clicks newest first, starting
+	// after the cursor when one is given.
+	FindLog(ctx context.Context, filter ClickFilter, after *ClickCursor, limit int) ([]ClickLogEntry, error)
+	// StreamLog calls fn for every matching click, oldest first, without
+	// loading them all at once. An error from fn stops the stream and is
+	// returned.
+	StreamLog(ctx context.Context, filter ClickFilter, fn func(*ClickLogEntry) error) error
 }
@@ -6,10 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Domain is a user's claim on a custom domain. Several users may claim the
+// same name, but only one claim can be verified; unverified claims expire so
+// that nobody can hold a name they cannot prove they control.
 type Domain struct {
 	ID                uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	UserID            uuid.UUID `gorm:"type:uuid;not null"`
-	DomainName        string    `gorm:"unique;not null"`
+	DomainName        string    `gorm:"not null"`
 	IsVerified        bool      `gorm:"default:false"`
 	VerificationToken string
 	IsActive          bool `gorm:"default:true"`
@@ -17,8 +20,24 @@ type Domain struct {
 	VerifiedAt        *time.Time
 }
 
+// ClaimExpired reports whether an unverified claim is older than ttl and can
+// no longer be verified.
+func (d *Domain) ClaimExpired(now time.Time, ttl time.Duration) bool {
+	return !d.IsVerified && !now.Before(d.CreatedAt.Add(ttl))
+}
+
+// DomainRepository stores domain claims. Store and Update return
+// gorm.ErrDuplicatedKey when the user already claims the name, or when the
+// name is already verified by another claim.
 type DomainRepository interface {
 	Store(domain *Domain) error
-	FindByDomainName(name string) (*Domain, error)
+	FindByID(id uuid.UUID) (*Domain, error)
+	// FindVerifiedByDomainName returns the verified claim on a name.
+	FindVerifiedByDomainName(name string) (*Domain, error)
+	FindByUserIDAndDomainName(userID uuid.UUID, name string) (*Domain, error)
 	FindAllByUserID(userID uuid.UUID) ([]Domain, error)
+	Update(domain *Domain) error
+	Delete(domain *Domain) error
+	// DeleteExpiredClaims removes unverified claims created before cutoff.
+	DeleteExpiredClaims(cutoff time.Time) (int64, error)
 }
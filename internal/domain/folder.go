@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Folder holds links. A link is in at most one folder.
+type Folder struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	Name      string    `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+type FolderRepository interface {
+	Store(folder *Folder) error
+	FindByID(id uuid.UUID) (*Folder, error)
+	FindByUserIDAndName(userID uuid.UUID, name string) (*Folder, error)
+	FindAllByUserID(userID uuid.UUID) ([]Folder, error)
+	Update(folder *Folder) error
+	Delete(folder *Folder) error
+}
@@ -6,8 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// RateLimit is the request counter of one limit key for one fixed window.
+// LimitKey identifies what is limited; the remaining identity columns are
+// informational.
 type RateLimit struct {
 	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LimitKey     string     `gorm:"not null"`
 	UserID       *uuid.UUID `gorm:"type:uuid"`
 	APIKey       *string
 	IPAddress    string
@@ -18,5 +22,9 @@ type RateLimit struct {
 }
 
 type RateLimitRepository interface {
-	Store(rateLimit *RateLimit) error
+	// Hit increments the counter for (LimitKey, WindowStart), stores the new
+	// value in RequestCount and returns the count of the window starting at
+	// previousWindowStart.
+	Hit(counter *RateLimit, previousWindowStart time.Time) (int, error)
+	DeleteBefore(cutoff time.Time) error
 }
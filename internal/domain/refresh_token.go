@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken records one issued refresh token, keyed by its jti. All
+// tokens of a session form one rotation family: each refresh consumes the
+// presented token and issues its successor.
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key"`
+	SessionID uuid.UUID  `gorm:"type:uuid;not null"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null"`
+	ParentID  *uuid.UUID `gorm:"type:uuid"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type RefreshTokenRepository interface {
+	Store(token *RefreshToken) error
+	FindByID(id uuid.UUID) (*RefreshToken, error)
+	// FindByParentID returns the token issued when parentID was rotated.
+	FindByParentID(parentID uuid.UUID) (*RefreshToken, error)
+	// Rotate marks the token usedID as used and stores next in one
+	// transaction. The token is only consumed if it is unused and unexpired;
+	// otherwise nothing is written and gorm.ErrRecordNotFound is returned.
+	// Of several concurrent rotations of the same token exactly one succeeds.
+	Rotate(usedID uuid.UUID, next *RefreshToken, at time.Time) error
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Schedule states of a link, as reported by URL.ScheduleStatus.
+const (
+	ScheduleAvailable = "available"
+	// ScheduleScheduled is a link whose StartsAt is still ahead.
+	ScheduleScheduled = "scheduled"
+	// ScheduleClosed is a link outside its availability windows.
+	ScheduleClosed  = "closed"
+	ScheduleExpired = "expired"
+)
+
+// ScheduleDays are the day names used by ScheduleWindow, indexed by
+// time.Weekday.
+var ScheduleDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Schedule limits a link to recurring availability windows, such as
+// business hours, in an IANA time zone.
+type Schedule struct {
+	TimeZone string           `json:"time_zone"`
+	Windows  []ScheduleWindow `json:"windows"`
+}
+
+// ScheduleWindow opens on each of Days at Start and closes at End, both
+// "15:04" in the schedule's time zone. A window whose End is not after its
+// Start closes the next day, so 22:00 to 02:00 runs overnight and 00:00 to
+// 00:00 lasts a whole day.
+type ScheduleWindow struct {
+	Days  []string `json:"days"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// ScheduleStatus is where a link stands in its schedule at one moment.
+// AvailableFrom is when a link that is not available opens next, nil if it
+// never will; AvailableUntil is when it closes next, nil if no end is set.
+type ScheduleStatus struct {
+	State          string
+	AvailableFrom  *time.Time
+	AvailableUntil *time.Time
+}
+
+// ScheduleStatus works out the link's state at now from StartsAt, ExpiresAt
+// and Schedule. It does not look at IsActive.
+func (u *URL) ScheduleStatus(now time.Time) ScheduleStatus {
+	if u.ExpiresAt != nil && !now.Before(*u.ExpiresAt) {
+		return ScheduleStatus{State: ScheduleExpired}
+	}
+
+	status := ScheduleStatus{State: ScheduleAvailable, AvailableUntil: u.ExpiresAt}
+	from := now
+	if u.StartsAt != nil && now.Before(*u.StartsAt) {
+		status.State = ScheduleScheduled
+		status.AvailableFrom = u.StartsAt
+		from = *u.StartsAt
+	}
+
+	if opens, closes, ok := u.Schedule.Next(from); ok {
+		if opens.After(from) {
+			if status.State == ScheduleAvailable {
+				status.State = ScheduleClosed
+			}
+			status.AvailableFrom = &opens
+		}
+		if u.ExpiresAt == nil || closes.Before(*u.ExpiresAt) {
+			status.AvailableUntil = &closes
+		}
+	}
+
+	if status.AvailableFrom != nil && u.ExpiresAt != nil && !status.AvailableFrom.Before(*u.ExpiresAt) {
+		status.AvailableFrom = nil
+		status.AvailableUntil = nil
+	}
+	return status
+}
+
+// Location loads the schedule's time zone.
+func (s *Schedule) Location() (*time.Location, error) {
+	if loc, ok := locationCache.Load(s.TimeZone); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	locationCache.Store(s.TimeZone, loc)
+	return loc, nil
+}
+
+// locationCache spares a zoneinfo read on every redirect of a scheduled
+// link. There are only a few hundred zones, so it needs no bound.
+var locationCache sync.Map
+
+type scheduleInterval struct {
+	opens, closes time.Time
+}
+
+// Next returns the opening of the window that contains t, or of the next
+// one to open after it, and when it closes. Windows that overlap or touch
+// count as one. ok is false for a nil schedule, one without windows or one
+// whose time zone cannot be loaded; such schedules are always open.
+func (s *Schedule) Next(t time.Time) (opens, closes time.Time, ok bool) {
+	if s == nil || len(s.Windows) == 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// Overnight windows of the day before can still be open at t; a week
+	// and a day ahead covers every weekday twice.
+	local := t.In(loc)
+	var intervals []scheduleInterval
+	for offset := -1; offset <= 8; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		for _, w := range s.Windows {
+			if interval, ok := w.on(day); ok {
+				intervals = append(intervals, interval)
+			}
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].opens.Before(intervals[j].opens) })
+
+	found := false
+	for _, interval := range intervals {
+		switch {
+		case !found && interval.closes.After(t):
+			opens, closes, found = interval.opens, interval.closes, true
+		case found && !interval.opens.After(closes):
+			if interval.closes.After(closes) {
+				closes = interval.closes
+			}
+		case found:
+			return opens, closes, true
+		}
+	}
+	return opens, closes, found
+}
+
+// on returns the interval the window is open for when it opens on day, a
+// local midnight.
+func (w ScheduleWindow) on(day time.Time) (scheduleInterval, bool) {
+	if !w.opensOn(day.Weekday()) {
+		return scheduleInterval{}, false
+	}
+	start, err1 := time.Parse("15:04", w.Start)
+	end, err2 := time.Parse("15:04", w.End)
+	if err1 != nil || err2 != nil {
+		return scheduleInterval{}, false
+	}
+
+	opens := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, day.Location())
+	endDay := day.Day()
+	if !end.After(start) {
+		endDay++
+	}
+	closes := time.Date(day.Year(), day.Month(), endDay, end.Hour(), end.Minute(), 0, 0, day.Location())
+	return scheduleInterval{opens: opens, closes: closes}, true
+}
+
+func (w ScheduleWindow) opensOn(weekday time.Weekday) bool {
+	for _, d := range w.Days {
+		if d == ScheduleDays[weekday] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s: %v", name, err)
+	}
+	return loc
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestScheduleStatus(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	// 2 March 2026 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, jakarta)
+	}
+	businessHours := &Schedule{TimeZone: "Asia/Jakarta", Windows: []ScheduleWindow{
+		{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "17:00"},
+	}}
+
+	tests := []struct {
+		name string
+		url  URL
+		now  time.Time
+		want ScheduleStatus
+	}{
+		{
+			name: "no schedule",
+			url:  URL{},
+			now:  at(2, 3, 0),
+			want: ScheduleStatus{State: ScheduleAvailable},
+		},
+		{
+			name: "inside a window",
+			url:  URL{Schedule: businessHours},
+			now:  at(2, 10, 0),
+			want: ScheduleStatus{State: ScheduleAvailable, AvailableUntil: timePtr(at(2, 17, 0))},
+		},
+		{
+			name: "window opening now",
+			url:  URL{Schedule: businessHours},
+			now:  at(2, 9, 0),
+			want: ScheduleStatus{State: ScheduleAvailable, AvailableUntil: timePtr(at(2, 17, 0))},
+		},
+		{
+			name: "window closing now",
+			url:  URL{Schedule: businessHours},
+			now:  at(2, 17, 0),
+			want: ScheduleStatus{State: ScheduleClosed, AvailableFrom: timePtr(at(3, 9, 0)), AvailableUntil: timePtr(at(3, 17, 0))},
+		},
+		{
+			name: "closed over the weekend",
+			url:  URL{Schedule: businessHours},
+			now:  at(6, 18, 0),
+			want: ScheduleStatus{State: ScheduleClosed, AvailableFrom: timePtr(at(9, 9, 0)), AvailableUntil: timePtr(at(9, 17, 0))},
+		},
+		{
+			name: "overnight window of the day before",
+			url: URL{Schedule: &Schedule{TimeZone: "Asia/Jakarta", Windows: []ScheduleWindow{
+				{Days: []string{"fri"}, Start: "22:00", End: "02:00"},
+			}}},
+			now:  at(7, 1, 0),
+			want: ScheduleStatus{State: ScheduleAvailable, AvailableUntil: timePtr(at(7, 2, 0))},
+		},
+		{
+			name: "touching windows count as one",
+			url: URL{Schedule: &Schedule{TimeZone: "Asia/Jakarta", Windows: []ScheduleWindow{
+				{Days: []string{"mon"}, Start: "12:00", End: "17:00"},
+				{Days: []string{"mon"}, Start: "09:00", End: "12:00"},
+			}}},
+			now:  at(2, 10, 0),
+			want: ScheduleStatus{State: ScheduleAvailable, AvailableUntil: timePtr(at(2, 17, 0))},
+		},
+		{
+			name: "whole-day window",
+			url: URL{Schedule: &Schedule{TimeZone: "Asia/Jakarta", Windows: []ScheduleWindow{
+				{Days: []string{"mon"}, Start: "00:00", End: "00:00"},
+			}}},
+			now:  at(2, 23, 59),
+			want: ScheduleStatus{State: ScheduleAvailable, AvailableUntil: timePtr(at(3, 0, 0))},
+		},
+		{
+			name: "time zone that cannot be loaded is always open",
+			url: URL{Schedule: &Schedule{TimeZone: "Mars/Olympus_Mons", Windows: []ScheduleWindow{
+				{Days: []string{"sun"}, Start: "09:00", End: "10:00"},
+			}}},
+			now:  at(2, 12, 0),
+			want: ScheduleStatus{State: ScheduleAvailable},
+		},
+		{
+			name: "launch inside a window",
+			url:  URL{StartsAt: timePtr(at(2, 11, 0)), Schedule: businessHours},
+			now:  at(2, 10, 0),
+			want: ScheduleStatus{State: ScheduleScheduled, AvailableFrom: timePtr(at(2, 11, 0)), AvailableUntil: timePtr(at(2, 17, 0))},
+		},
+		{
+			name: "launch outside the windows",
+			url:  URL{StartsAt: timePtr(at(2, 18, 0)), Schedule: businessHours},
+			now:  at(2, 10, 0),
+			want: ScheduleStatus{State: ScheduleScheduled, AvailableFrom: timePtr(at(3, 9, 0)), AvailableUntil: timePtr(at(3, 17, 0))},
+		},
+		{
+			name: "expiry inside the window",
+			url:  URL{ExpiresAt: timePtr(at(2, 12, 0)), Schedule: businessHours},
+			now:  at(2, 10, 0),
+			want: ScheduleStatus{State: ScheduleAvailable, AvailableUntil: timePtr(at(2, 12, 0))},
+		},
+		{
+			name: "expiry before the next window",
+			url:  URL{ExpiresAt: timePtr(at(3, 8, 0)), Schedule: businessHours},
+			now:  at(2, 18, 0),
+			want: ScheduleStatus{State: ScheduleClosed},
+		},
+		{
+			name: "expired",
+			url:  URL{ExpiresAt: timePtr(at(2, 10, 0)), Schedule: businessHours},
+			now:  at(2, 10, 0),
+			want: ScheduleStatus{State: ScheduleExpired},
+		},
+	}
+	for _, tt := range tests {
+		got := tt.url.ScheduleStatus(tt.now)
+		if got.State != tt.want.State || !equalTimes(got.AvailableFrom, tt.want.AvailableFrom) || !equalTimes(got.AvailableUntil, tt.want.AvailableUntil) {
+			t.Errorf("%s: got %s from %v until %v, want %s from %v until %v", tt.name,
+				got.State, got.AvailableFrom, got.AvailableUntil, tt.want.State, tt.want.AvailableFrom, tt.want.AvailableUntil)
+		}
+	}
+}
+
+func TestScheduleNextAcrossDaylightSaving(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	daily := &Schedule{TimeZone: "America/New_York", Windows: []ScheduleWindow{
+		{Days: ScheduleDays, Start: "09:00", End: "17:00"},
+	}}
+
+	// Clocks go forward on Sunday 8 March 2026; the window still opens at
+	// 09:00 local time, an hour earlier in UTC than the day before.
+	opens, closes, ok := daily.Next(time.Date(2026, 3, 7, 18, 0, 0, 0, newYork))
+	if !ok {
+		t.Fatal("no window found")
+	}
+	if want := time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC); !opens.Equal(want) {
+		t.Errorf("opens = %v, want %v", opens.UTC(), want)
+	}
+	if want := time.Date(2026, 3, 8, 21, 0, 0, 0, time.UTC); !closes.Equal(want) {
+		t.Errorf("closes = %v, want %v", closes.UTC(), want)
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one login. Every access and refresh token carries the session
+// ID, and a token is only accepted while its session is active.
+type Session struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// IsActive reports whether tokens of the session may still be used.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+type SessionRepository interface {
+	Store(session *Session) error
+	FindByID(id uuid.UUID) (*Session, error)
+	Touch(id uuid.UUID, at time.Time) error
+	Revoke(id uuid.UUID) error
+	RevokeAllByUserID(userID uuid.UUID) error
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag labels links. A link can carry many tags and a tag many links; the
+// pairs are kept in url_tags.
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	Name      string    `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+type TagRepository interface {
+	Store(tag *Tag) error
+	FindByID(id uuid.UUID) (*Tag, error)
+	// FindByIDs returns those of ids that are tags of the user.
+	FindByIDs(userID uuid.UUID, ids []uuid.UUID) ([]Tag, error)
+	FindByUserIDAndName(userID uuid.UUID, name string) (*Tag, error)
+	FindAllByUserID(userID uuid.UUID) ([]Tag, error)
+	Update(tag *Tag) error
+	Delete(tag *Tag) error
+	// ReplaceURLTags sets the tags of a link to exactly tagIDs.
+	ReplaceURLTags(urlID uuid.UUID, tagIDs []uuid.UUID) error
+	// AddToURLs tags those of urlIDs that are the user's links and not in
+	// the trash with every tag in tagIDs, and returns how many tags were
+	// added. Tags a link already has are left alone.
+	AddToURLs(userID uuid.UUID, urlIDs, tagIDs []uuid.UUID) (int64, error)
+	// RemoveFromURLs removes tagIDs from those of urlIDs that are the user's
+	// links and returns how many tags were removed.
+	RemoveFromURLs(userID uuid.UUID, urlIDs, tagIDs []uuid.UUID) (int64, error)
+}
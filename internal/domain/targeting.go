@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// VariantDefault is recorded on the clicks of a link with targeting rules
+// when no rule matched and the visitor was sent to OriginalURL. Links with
+// split destinations record the destination's variant instead.
+const VariantDefault = "default"
+
+// VariantClickCapReached is recorded on the clicks of visitors sent to a
+// link's CapFallbackURL after its MaxClicks was reached.
+const VariantClickCapReached = "cap_reached"
+
+// VariantExpired is recorded on the clicks of visitors sent to a link's
+// ExpiredRedirectURL.
+const VariantExpired = "expired"
+
+// GeoRule sends visitors from a country, or from one region of it, to an
+// alternate destination. Country is an ISO 3166-1 alpha-2 code; Region is
+// matched against the region name GeoIP reports, ignoring case.
+type GeoRule struct {
+	Country        string `json:"country"`
+	Region         string `json:"region,omitempty"`
+	DestinationURL string `json:"destination_url"`
+}
+
+// Matches reports whether a visitor located in country and region is
+// targeted by the rule.
+func (r GeoRule) Matches(country, region string) bool {
+	if country == "" || !strings.EqualFold(r.Country, country) {
+		return false
+	}
+	return r.Region == "" || strings.EqualFold(r.Region, region)
+}
+
+// Variant labels the rule's destination in click analytics, e.g. "geo:US"
+// or "geo:US/California".
+func (r GeoRule) Variant() string {
+	if r.Region == "" {
+		return "geo:" + r.Country
+	}
+	return "geo:" + r.Country + "/" + r.Region
+}
+
+// MatchGeoRule returns the first of rules that targets the location, or
+// nil. Rules are tried in order, so a region rule must come before a rule
+// for its whole country to take effect.
+func MatchGeoRule(rules []GeoRule, country, region string) *GeoRule {
+	for i := range rules {
+		if rules[i].Matches(country, region) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// DeviceRule sends visitors on a mobile platform to an app: an app-store
+// URL, a universal link or a custom-scheme deep link such as
+// myapp://product/42. Platform is "ios" or "android", as reported by
+// utils.ParseUserAgent.
+type DeviceRule struct {
+	Platform       string `json:"platform"`
+	DestinationURL string `json:"destination_url"`
+	// FallbackURL is where visitors go when a deep link does not open the
+	// app. Empty means the link's web destination.
+	FallbackURL string `json:"fallback_url,omitempty"`
+}
+
+// IsDeepLink reports whether DestinationURL uses a custom scheme. Browsers
+// cannot tell whether such a link opened anything, so it is served through
+// an intermediate page that falls back to the web.
+func (r DeviceRule) IsDeepLink() bool {
+	u, err := url.Parse(r.DestinationURL)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme != "http" && scheme != "https"
+}
+
+// Variant labels the rule's destination in click analytics, e.g.
+// "device:ios".
+func (r DeviceRule) Variant() string {
+	return "device:" + r.Platform
+}
+
+// MatchDeviceRule returns the rule for platform, or nil.
+func MatchDeviceRule(rules []DeviceRule, platform string) *DeviceRule {
+	if platform == "" {
+		return nil
+	}
+	for i := range rules {
+		if rules[i].Platform == platform {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// SplitDestination is one arm of an A/B split or weighted rotation. Weights
+// are relative: 7 and 3 split traffic the same way as 70 and 30.
+type SplitDestination struct {
+	Name           string `json:"name"`
+	DestinationURL string `json:"destination_url"`
+	Weight         int    `json:"weight"`
+}
+
+// Variant labels the destination in click analytics, e.g. "split:B".
+func (d SplitDestination) Variant() string {
+	return "split:" + d.Name
+}
+
+// PickSplitDestination maps point, a number derived from the visitor, onto
+// the destinations in proportion to their weights. The same point always
+// picks the same destination while the weights stay the same; changing them
+// reassigns part of the visitors. It returns nil when no destination has a
+// positive weight.
+func PickSplitDestination(destinations []SplitDestination, point uint64) *SplitDestination {
+	var total uint64
+	for _, d := range destinations {
+		if d.Weight > 0 {
+			total += uint64(d.Weight)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	point %= total
+	for i := range destinations {
+		if destinations[i].Weight <= 0 {
+			continue
+		}
+		if point < uint64(destinations[i].Weight) {
+			return &destinations[i]
+		}
+		point -= uint64(destinations[i].Weight)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "testing"
+
+func TestMatchDeviceRule(t *testing.T) {
+	rules := []DeviceRule{
+		{Platform: "ios", DestinationURL: "myapp://product/42"},
+		{Platform: "android", DestinationURL: "https://play.google.com/store/apps/details?id=com.example"},
+	}
+
+	if got := MatchDeviceRule(rules, "ios"); got != &rules[0] {
+		t.Errorf("ios matched %v, want the first rule", got)
+	}
+	if got := MatchDeviceRule(rules, "android"); got != &rules[1] {
+		t.Errorf("android matched %v, want the second rule", got)
+	}
+	if got := MatchDeviceRule(rules, ""); got != nil {
+		t.Errorf("desktop matched %v, want nil", got)
+	}
+	if got := MatchDeviceRule(nil, "ios"); got != nil {
+		t.Errorf("no rules matched %v, want nil", got)
+	}
+}
+
+func TestDeviceRuleIsDeepLink(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"myapp://product/42", true},
+		{"https://apps.apple.com/app/id123", false},
+		{"HTTP://example.com", false},
+	}
+	for _, tt := range tests {
+		if got := (DeviceRule{DestinationURL: tt.url}).IsDeepLink(); got != tt.want {
+			t.Errorf("IsDeepLink(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestPickSplitDestination(t *testing.T) {
+	destinations := []SplitDestination{
+		{Name: "A", Weight: 7},
+		{Name: "off", Weight: 0},
+		{Name: "B", Weight: 3},
+	}
+
+	counts := map[string]int{}
+	for point := uint64(0); point < 100; point++ {
+		counts[PickSplitDestination(destinations, point).Name]++
+	}
+	if counts["A"] != 70 || counts["B"] != 30 || counts["off"] != 0 {
+		t.Errorf("picks over 100 points = %v, want A:70 B:30", counts)
+	}
+
+	// Points wrap around the total weight.
+	if got := PickSplitDestination(destinations, 1007); got.Name != "B" {
+		t.Errorf("point 1007 picked %q, want B", got.Name)
+	}
+	if got := PickSplitDestination([]SplitDestination{{Name: "A"}, {Name: "B", Weight: -1}}, 3); got != nil {
+		t.Errorf("picked %v without positive weights, want nil", got)
+	}
+	if got := PickSplitDestination(nil, 3); got != nil {
+		t.Errorf("picked %v without destinations, want nil", got)
+	}
+}
+
+func TestMatchGeoRule(t *testing.T) {
+	rules := []GeoRule{
+		{Country: "US", Region: "California", DestinationURL: "https://example.com/ca"},
+		{Country: "US", DestinationURL: "https://example.com/us"},
+		{Country: "ID", DestinationURL: "https://example.com/id"},
+	}
+	tests := []struct {
+		country, region string
+		want            *GeoRule
+	}{
+		{"US", "california", &rules[0]},
+		{"us", "Texas", &rules[1]},
+		{"US", "", &rules[1]},
+		{"ID", "Jawa Barat", &rules[2]},
+		{"DE", "Berlin", nil},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		if got := MatchGeoRule(rules, tt.country, tt.region); got != tt.want {
+			t.Errorf("MatchGeoRule(%q, %q) = %v, want %v", tt.country, tt.region, got, tt.want)
+		}
+	}
+
+	if got := rules[0].Variant(); got != "geo:US/California" {
+		t.Errorf("region variant = %q", got)
+	}
+	if got := rules[1].Variant(); got != "geo:US" {
+		t.Errorf("country variant = %q", got)
+	}
+}
@@ -1,54 +1,238 @@
 package domain
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type URL struct {
-	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID           *uuid.UUID `gorm:"type:uuid"`
-	OriginalURL      string     `gorm:"not null"`
-	ShortCode        string     `gorm:"unique;not null"`
-	CustomAlias      *string    `gorm:"unique"`
-	DomainID         *uuid.UUID `gorm:"type:uuid"`
-	Title            *string
-	Description      *string
-	PasswordHash     *string
-	IsActive         bool `gorm:"default:true"`
-	ClickCount       int  `gorm:"default:0"`
-	UniqueClickCount int  `gorm:"default:0"`
-	ExpiresAt        *time.Time
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	LastClickedAt    *time.Time
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      *uuid.UUID `gorm:"type:uuid"`
+	OriginalURL string     `gorm:"not null"`
+	ShortCode   string     `gorm:"not null"`
+	CustomAlias *string
+	DomainID    *uuid.UUID `gorm:"type:uuid"`
+	Domain      *Domain    `gorm:"foreignKey:DomainID"`
+	CampaignID  *uuid.UUID `gorm:"type:uuid"`
+	FolderID    *uuid.UUID `gorm:"type:uuid"`
+	Tags        []Tag      `gorm:"many2many:url_tags"`
+	// GeoRules send visitors from some locations elsewhere than OriginalURL.
+	GeoRules []GeoRule `gorm:"type:jsonb;serializer:json"`
+	// DeviceRules send iOS and Android visitors to their app.
+	DeviceRules []DeviceRule `gorm:"type:jsonb;serializer:json"`
+	// SplitDestinations, when set, replace OriginalURL as the destination of
+	// visitors no targeting rule matched.
+	SplitDestinations []SplitDestination `gorm:"type:jsonb;serializer:json"`
+	Title             *string
+	Description       *string
+	PasswordHash      *string
+	// PasswordFingerprint stands in for PasswordHash on copies of the link
+	// kept outside the database, such as the lookup cache. It changes with
+	// the password but cannot be used to check one.
+	PasswordFingerprint string `gorm:"-"`
+	IsActive            bool   `gorm:"default:true"`
+	ClickCount          int    `gorm:"default:0"`
+	UniqueClickCount    int    `gorm:"default:0"`
+	// StartsAt is when the link goes live; nil means at once.
+	StartsAt  *time.Time
+	ExpiresAt *time.Time
+	// Schedule limits the link to recurring availability windows.
+	Schedule *Schedule `gorm:"type:jsonb;serializer:json"`
+	// ExpiredRedirectURL is where visitors go once the link has expired;
+	// without one they see ExpiredMessage, or a default message.
+	ExpiredRedirectURL *string
+	ExpiredMessage     *string
+	// MaxClicks caps the redirects of the link; nil means no cap.
+	// RedirectCount counts redirects against the cap as they happen, unlike
+	// the batched ClickCount, and is only kept for capped links.
+	MaxClicks     *int
+	RedirectCount int `gorm:"default:0"`
+	// CapFallbackURL is where visitors go once MaxClicks is reached. Without
+	// one the link is deactivated by its last allowed redirect.
+	CapFallbackURL *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LastClickedAt  *time.Time
+	// DeletedAt marks a link moved to the trash. GORM leaves trashed links
+	// out of every query unless it is run Unscoped.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// ClickCapReached reports whether the link has used up its MaxClicks, as far
+// as this copy of it knows.
+func (u *URL) ClickCapReached() bool {
+	return u.MaxClicks != nil && u.RedirectCount >= *u.MaxClicks
+}
+
+// IsPasswordProtected reports whether visitors must unlock the link first.
+func (u *URL) IsPasswordProtected() bool {
+	return u.PasswordHash != nil || u.PasswordFingerprint != ""
+}
+
+// PasswordVersion returns the fingerprint of the link's current password,
+// or "" for links without one.
+func (u *URL) PasswordVersion() string {
+	if u.PasswordHash == nil {
+		return u.PasswordFingerprint
+	}
+	sum := sha256.Sum256([]byte(*u.PasswordHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// RemainingClicks returns how many redirects the cap still allows, or nil
+// for links without a cap.
+func (u *URL) RemainingClicks() *int {
+	if u.MaxClicks == nil {
+		return nil
+	}
+	remaining := max(*u.MaxClicks-u.RedirectCount, 0)
+	return &remaining
+}
+
+// DomainName returns the custom domain the link is served on, or "" for
+// links on the default base URL.
+func (u *URL) DomainName() string {
+	if u.Domain == nil {
+		return ""
+	}
+	return u.Domain.DomainName
+}
+
+// Fields a link list can be sorted by. Repositories must only ever order by
+// one of these.
+const (
+	URLSortCreatedAt        = "created_at"
+	URLSortClickCount       = "click_count"
+	URLSortUniqueClickCount = "unique_click_count"
+	URLSortTitle            = "title"
+	URLSortLastClickedAt    = "last_clicked_at"
+)
+
+// URLSortFields lists every accepted sort field.
+var URLSortFields = []string{URLSortCreatedAt, URLSortClickCount, URLSortUniqueClickCount, URLSortTitle, URLSortLastClickedAt}
+
+// FindAllOptions selects a page of a user's links. Nil filters are not
+// applied. Date ranges include From and exclude To. When After is set the
+// page starts right after that position and Offset is ignored.
 type FindAllOptions struct {
 	Search string
-	SortBy string
-	Order  string
+	SortBy string // one of URLSortFields; anything else sorts by created_at
+	Order  string // "asc" or "desc"
 	Limit  int
 	Offset int
+	After  *URLCursor
+
+	IsActive          *bool
+	Expired           *bool
+	PasswordProtected *bool
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	ClickedFrom       *time.Time
+	ClickedTo         *time.Time
+	// DomainID restricts the list to one custom domain; DefaultDomainOnly
+	// to links on the default base URL.
+	DomainID          *uuid.UUID
+	DefaultDomainOnly bool
+	CampaignID        *uuid.UUID
+	// TagIDs keeps links with any of the tags. FolderID restricts the list
+	// to one folder; NoFolderOnly to links outside every folder.
+	TagIDs       []uuid.UUID
+	FolderID     *uuid.UUID
+	NoFolderOnly bool
+}
+
+// URLCursor is a keyset position in a sorted link list: the sort value of
+// the last link returned and its ID, which breaks ties.
+type URLCursor struct {
+	SortValue string
+	ID        uuid.UUID
+}
+
+// SortValue returns the value of sortBy for the link in the text form kept
+// in a URLCursor. Links without a title or clicks sort as "" and the zero
+// Unix time respectively.
+func (u *URL) SortValue(sortBy string) string {
+	switch sortBy {
+	case URLSortClickCount:
+		return strconv.Itoa(u.ClickCount)
+	case URLSortUniqueClickCount:
+		return strconv.Itoa(u.UniqueClickCount)
+	case URLSortTitle:
+		if u.Title == nil {
+			return ""
+		}
+		return *u.Title
+	case URLSortLastClickedAt:
+		if u.LastClickedAt == nil {
+			return time.Unix(0, 0).UTC().Format(time.RFC3339Nano)
+		}
+		return u.LastClickedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		return u.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// ClickCountDelta is the aggregated counter change for one URL produced by a
+// single flush of the click-ingestion pipeline.
+type ClickCountDelta struct {
+	URLID         uuid.UUID
+	Clicks        int
+	UniqueClicks  int
+	LastClickedAt time.Time
 }
 
 type DashboardSummaryResult struct {
-	TotalURLs   int64
-	TotalClicks int64
-	ActiveURLs  int64
+	TotalURLs         int64
+	TotalClicks       int64
+	TotalUniqueClicks int64
+	ActiveURLs        int64
 }
 
 type URLRepository interface {
+	// Store returns gorm.ErrDuplicatedKey when the short code is already
+	// taken on the link's domain.
 	Store(url *URL) error
-	FindByShortCode(shortCode string) (*URL, error)
-	FindByCustomAlias(customAlias string) (*URL, error)
+	FindByShortCode(domainID *uuid.UUID, shortCode string) (*URL, error)
+	FindByCustomAlias(domainID *uuid.UUID, customAlias string) (*URL, error)
 	FindByID(id uuid.UUID) (*URL, error)
 	FindAllByUserID(userID uuid.UUID, options *FindAllOptions) ([]URL, int64, error)
 	Update(url *URL) error
+	// Delete moves a link to the trash. Its short code stays taken until
+	// the link is purged.
 	Delete(url *URL) error
-	IncrementClickCount(urlID uuid.UUID) error
-	GetDashboardSummary(userID uuid.UUID) (*DashboardSummaryResult, error)
-	GetTopPerformingURLs(userID uuid.UUID, limit int) ([]URL, error)
-	GetRecentActivity(userID uuid.UUID, limit int) ([]URL, error)
+	// IsShortCodeTaken reports whether the code is used on the domain by any
+	// link, including links in the trash.
+	IsShortCodeTaken(domainID *uuid.UUID, shortCode string) (bool, error)
+	FindDeletedByID(id uuid.UUID) (*URL, error)
+	FindDeletedByUserID(userID uuid.UUID, limit, offset int) ([]URL, int64, error)
+	Restore(url *URL) error
+	// PurgeDeletedBefore permanently removes up to limit links trashed
+	// before cutoff, together with their clicks, and returns how many were
+	// removed.
+	PurgeDeletedBefore(cutoff time.Time, limit int) (int64, error)
+	PurgeDeletedByDomainID(domainID uuid.UUID) error
+	CountByDomainID(domainID uuid.UUID) (int64, error)
+	CountByCampaignID(ctx context.Context, campaignID uuid.UUID) (int64, error)
+	CountByTagID(ctx context.Context, tagID uuid.UUID) (int64, error)
+	CountByFolderID(ctx context.Context, folderID uuid.UUID) (int64, error)
+	CountCreatedByUserSince(userID uuid.UUID, since time.Time) (int64, error)
+	IncrementClickCounts(deltas []ClickCountDelta) error
+	// ConsumeClick counts one redirect against the link's MaxClicks and
+	// reports false, counting nothing, once the cap is reached. The check and
+	// the increment are a single statement, so concurrent redirects cannot
+	// overshoot. The redirect that reaches the cap also deactivates a link
+	// without CapFallbackURL. url.RedirectCount is updated on success.
+	ConsumeClick(url *URL) (bool, error)
+	GetDashboardSummary(ctx context.Context, userID uuid.UUID) (*DashboardSummaryResult, error)
+	GetTopPerformingURLs(ctx context.Context, userID uuid.UUID, limit int) ([]URL, error)
+	// GetLinkGroupTotals ranks the user's tags or folders by the click
+	// counters of their links.
+	GetLinkGroupTotals(ctx context.Context, userID uuid.UUID, group string, limit int) ([]LinkGroupResult, error)
+	GetRecentActivity(ctx context.Context, userID uuid.UUID, limit int) ([]URL, error)
 }
@@ -6,15 +6,23 @@ import (
 	"github.com/google/uuid"
 )
 
+// How visitor IP addresses appear in a user's click logs and exports: in
+// full, masked to their network (IPv4 /24, IPv6 /48), or not at all.
+const (
+	IPPrivacyFull   = "full"
+	IPPrivacyMasked = "masked"
+	IPPrivacyHidden = "hidden"
+)
+
 type User struct {
-	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Email        string     `gorm:"unique;not null"`
-	PasswordHash string     `gorm:"not null"`
-	APIKey       string     `gorm:"unique;not null"`
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Email        string    `gorm:"unique;not null"`
+	PasswordHash string    `gorm:"not null"`
 	FirstName    *string
 	LastName     *string
-	IsActive     bool       `gorm:"default:true"`
-	PlanType     string     `gorm:"default:'free'"`
+	IsActive     bool   `gorm:"default:true"`
+	PlanType     string `gorm:"default:'free'"`
+	IPPrivacy    string `gorm:"default:'masked'"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	LastLoginAt  *time.Time
@@ -24,6 +32,5 @@ type UserRepository interface {
 	Store(user *User) error
 	FindByID(id uuid.UUID) (*User, error)
 	FindByEmail(email string) (*User, error)
-	FindByAPIKey(apiKey string) (*User, error)
 	Update(user *User) error
 }
@@ -0,0 +1,62 @@
+package request
+
+import "time"
+
+// ClickFilterRequest narrows analytics to clicks with the given dimension
+// values. A parameter may be repeated to match any of several values; values
+// are matched exactly as they appear in the breakdowns.
+type ClickFilterRequest struct {
+	Country          []string `form:"country" binding:"max=20"`
+	Region           []string `form:"region" binding:"max=20"`
+	City             []string `form:"city" binding:"max=20"`
+	Device           []string `form:"device" binding:"max=20"`
+	Browser          []string `form:"browser" binding:"max=20"`
+	OS               []string `form:"os" binding:"max=20"`
+	Language         []string `form:"language" binding:"max=20"`
+	Referrer         []string `form:"referrer" binding:"max=20"`
+	ReferrerDomain   []string `form:"referrer_domain" binding:"max=20"`
+	ReferrerCategory []string `form:"referrer_category" binding:"max=20"`
+	UTMSource        []string `form:"utm_source" binding:"max=20"`
+	UTMMedium        []string `form:"utm_medium" binding:"max=20"`
+	UTMCampaign      []string `form:"utm_campaign" binding:"max=20"`
+	UTMTerm          []string `form:"utm_term" binding:"max=20"`
+	UTMContent       []string `form:"utm_content" binding:"max=20"`
+	Variant          []string `form:"variant" binding:"max=20"`
+}
+
+// URLAnalyticsRequest holds the query parameters of the URL analytics
+// endpoint. From and To (RFC 3339) select a custom range and take precedence
+// over Period; To defaults to now. Granularity defaults to a size suited to
+// the range, and TZ is an IANA zone name used for bucketing.
+type URLAnalyticsRequest struct {
+	ClickFilterRequest
+	Period      string     `form:"period,default=7d" binding:"oneof=24h 7d 30d all"`
+	From        *time.Time `form:"from"`
+	To          *time.Time `form:"to"`
+	Granularity string     `form:"granularity" binding:"omitempty,oneof=minute hour day week month"`
+	TZ          string     `form:"tz,default=UTC"`
+}
+
+// DashboardRequest holds the query parameters of the user dashboard. Without
+// filters the click totals are the links' lifetime counters.
+type DashboardRequest struct {
+	ClickFilterRequest
+}
+
+// ClickLogRequest holds the query parameters of the click log. From is
+// inclusive and To exclusive, both RFC 3339.
+type ClickLogRequest struct {
+	ClickFilterRequest
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+	Cursor string     `form:"cursor"`
+	Limit  int        `form:"limit,default=50" binding:"min=1,max=1000"`
+}
+
+// ClickExportRequest holds the query parameters of a click export.
+type ClickExportRequest struct {
+	ClickFilterRequest
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+	Format string     `form:"format,default=csv" binding:"oneof=csv ndjson json"`
+}
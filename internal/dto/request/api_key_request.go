@@ -0,0 +1,9 @@
+package request
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"CI deploy bot"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1" example:"urls:read,urls:write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
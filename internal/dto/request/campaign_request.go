@@ -0,0 +1,28 @@
+package request
+
+// UTMRequest holds utm_* parameters to tag a destination with. Values are
+// trimmed, and source and medium are lowercased so that "Newsletter" and
+// "newsletter" are reported together.
+type UTMRequest struct {
+	Source   string `json:"source,omitempty" binding:"max=255" example:"newsletter"`
+	Medium   string `json:"medium,omitempty" binding:"max=255" example:"email"`
+	Campaign string `json:"campaign,omitempty" binding:"max=255" example:"spring_sale"`
+	Term     string `json:"term,omitempty" binding:"max=255"`
+	Content  string `json:"content,omitempty" binding:"max=255"`
+}
+
+// CreateCampaignRequest creates a campaign. utm.campaign defaults to the
+// campaign name.
+type CreateCampaignRequest struct {
+	Name        string     `json:"name" binding:"required,max=100" example:"Spring Sale"`
+	Description *string    `json:"description,omitempty"`
+	UTM         UTMRequest `json:"utm"`
+}
+
+// UpdateCampaignRequest changes a campaign. A utm object replaces all of the
+// campaign's UTM parameters; links already created keep theirs.
+type UpdateCampaignRequest struct {
+	Name        *string     `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Description *string     `json:"description,omitempty"`
+	UTM         *UTMRequest `json:"utm,omitempty"`
+}
@@ -0,0 +1,9 @@
+package request
+
+type CreateDomainRequest struct {
+	DomainName string `json:"domain_name" binding:"required,fqdn"`
+}
+
+type UpdateDomainRequest struct {
+	IsActive *bool `json:"is_active,omitempty"`
+}
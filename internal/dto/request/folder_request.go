@@ -0,0 +1,11 @@
+package request
+
+// CreateFolderRequest creates a folder. Folder names are unique per user,
+// ignoring case.
+type CreateFolderRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Marketing"`
+}
+
+type UpdateFolderRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Marketing"`
+}
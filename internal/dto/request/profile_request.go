@@ -9,3 +9,7 @@ type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
 	NewPassword     string `json:"new_password" binding:"required,min=8"`
 }
+
+type UpdatePrivacyRequest struct {
+	IPPrivacy string `json:"ip_privacy" binding:"required,oneof=full masked hidden" example:"masked"`
+}
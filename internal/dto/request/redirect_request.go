@@ -1,5 +1,5 @@
 package request
 
 type UnlockURLRequest struct {
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password" form:"password" binding:"required"`
 }
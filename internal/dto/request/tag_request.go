@@ -0,0 +1,21 @@
+package request
+
+import "github.com/google/uuid"
+
+// CreateTagRequest creates a tag. Tag names are unique per user, ignoring
+// case.
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"newsletter"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"newsletter"`
+}
+
+// BulkTagRequest adds tags to and removes tags from many links at once.
+// Links and tags the user does not own are ignored.
+type BulkTagRequest struct {
+	URLIDs []uuid.UUID `json:"url_ids" binding:"required,min=1,max=1000"`
+	Add    []uuid.UUID `json:"add,omitempty" binding:"max=50"`
+	Remove []uuid.UUID `json:"remove,omitempty" binding:"max=50"`
+}
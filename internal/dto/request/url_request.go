@@ -1,19 +1,137 @@
 package request
 
-import "time"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// CreateURLRequest creates a link. The UTM parameters of the campaign, if
+// any, are merged into original_url; fields set in utm take precedence over
+// the campaign's. max_clicks caps the redirects of the link, after which it
+// is deactivated or sends visitors to cap_fallback_url;
+// burn_after_reading is a cap of one. Before starts_at, outside the schedule
+// and after expires_at the link does not redirect; expired links send
+// visitors to expired_redirect_url or show expired_message instead.
 type CreateURLRequest struct {
-	OriginalURL string     `json:"original_url" binding:"required,url"`
-	CustomAlias *string    `json:"custom_alias,omitempty"`
-	Title       *string    `json:"title,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	Password    *string    `json:"password,omitempty"`
+	OriginalURL        string                    `json:"original_url" binding:"required,url"`
+	CustomAlias        *string                   `json:"custom_alias,omitempty"`
+	Domain             *string                   `json:"domain,omitempty" example:"links.example.com"`
+	CampaignID         *uuid.UUID                `json:"campaign_id,omitempty"`
+	UTM                *UTMRequest               `json:"utm,omitempty"`
+	FolderID           *uuid.UUID                `json:"folder_id,omitempty"`
+	TagIDs             []uuid.UUID               `json:"tag_ids,omitempty" binding:"max=50"`
+	GeoRules           []GeoRuleRequest          `json:"geo_rules,omitempty" binding:"max=50,dive"`
+	DeviceRules        []DeviceRuleRequest       `json:"device_rules,omitempty" binding:"max=2,unique=Platform,dive"`
+	SplitDestinations  []SplitDestinationRequest `json:"split_destinations,omitempty" binding:"max=10,unique=Name,dive"`
+	Title              *string                   `json:"title,omitempty"`
+	Description        *string                   `json:"description,omitempty"`
+	StartsAt           *time.Time                `json:"starts_at,omitempty"`
+	ExpiresAt          *time.Time                `json:"expires_at,omitempty"`
+	Schedule           *ScheduleRequest          `json:"schedule,omitempty"`
+	ExpiredRedirectURL *string                   `json:"expired_redirect_url,omitempty" binding:"omitempty,url" example:"https://example.com/offer-ended"`
+	ExpiredMessage     *string                   `json:"expired_message,omitempty" binding:"omitempty,max=500" example:"This offer has ended."`
+	MaxClicks          *int                      `json:"max_clicks,omitempty" binding:"omitempty,min=1" example:"100"`
+	BurnAfterReading   bool                      `json:"burn_after_reading,omitempty" binding:"excluded_with=MaxClicks"`
+	CapFallbackURL     *string                   `json:"cap_fallback_url,omitempty" binding:"omitempty,url" example:"https://example.com/sold-out"`
+	Password           *string                   `json:"password,omitempty"`
 }
 
+// UpdateURLRequest changes a link. An empty folder_id takes the link out of
+// its folder; tag_ids, geo_rules, device_rules and split_destinations
+// replace all of the link's tags, rules and destinations. max_clicks 0 and
+// an empty cap_fallback_url remove the cap and the fallback; a link
+// deactivated by its cap needs is_active as well as a higher cap. A schedule
+// without windows, an empty expired_redirect_url and an empty
+// expired_message remove them.
 type UpdateURLRequest struct {
-	Title       *string    `json:"title,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	IsActive    *bool      `json:"is_active,omitempty"`
+	Title              *string                    `json:"title,omitempty"`
+	Description        *string                    `json:"description,omitempty"`
+	StartsAt           *time.Time                 `json:"starts_at,omitempty"`
+	ExpiresAt          *time.Time                 `json:"expires_at,omitempty"`
+	Schedule           *ScheduleRequest           `json:"schedule,omitempty"`
+	ExpiredRedirectURL *string                    `json:"expired_redirect_url,omitempty" binding:"omitempty,eq=|url"`
+	ExpiredMessage     *string                    `json:"expired_message,omitempty" binding:"omitempty,max=500"`
+	IsActive           *bool                      `json:"is_active,omitempty"`
+	MaxClicks          *int                       `json:"max_clicks,omitempty" binding:"omitempty,min=0" example:"100"`
+	CapFallbackURL     *string                    `json:"cap_fallback_url,omitempty" binding:"omitempty,eq=|url"`
+	FolderID           *string                    `json:"folder_id,omitempty" binding:"omitempty,eq=|uuid"`
+	TagIDs             *[]uuid.UUID               `json:"tag_ids,omitempty" binding:"omitempty,max=50"`
+	GeoRules           *[]GeoRuleRequest          `json:"geo_rules,omitempty" binding:"omitempty,max=50,dive"`
+	DeviceRules        *[]DeviceRuleRequest       `json:"device_rules,omitempty" binding:"omitempty,max=2,unique=Platform,dive"`
+	SplitDestinations  *[]SplitDestinationRequest `json:"split_destinations,omitempty" binding:"omitempty,max=10,unique=Name,dive"`
+}
+
+// GeoRuleRequest sends visitors from a country, or one region of it, to
+// destination_url. Rules are tried in order and the first match wins, so
+// list region rules before the rule for their whole country.
+type GeoRuleRequest struct {
+	Country        string `json:"country" binding:"required,len=2,alpha" example:"US"`
+	Region         string `json:"region,omitempty" binding:"max=100" example:"California"`
+	DestinationURL string `json:"destination_url" binding:"required,url" example:"https://example.com/us"`
+}
+
+// DeviceRuleRequest sends iOS or Android visitors to their app.
+// destination_url is an app-store URL, a universal or app link, or a
+// custom-scheme deep link such as myapp://product/42. Deep links go through
+// a page that tries to open the app and then falls back to fallback_url, or
+// to the link's web destination when it is empty. Device rules take
+// precedence over geo rules.
+type DeviceRuleRequest struct {
+	Platform       string `json:"platform" binding:"required,oneof=ios android" example:"ios"`
+	DestinationURL string `json:"destination_url" binding:"required,url" example:"myapp://product/42"`
+	FallbackURL    string `json:"fallback_url,omitempty" binding:"omitempty,http_url" example:"https://apps.apple.com/app/id123456789"`
+}
+
+// SplitDestinationRequest is one arm of an A/B split. A link needs at least
+// two; visitors no targeting rule matched are spread over them by weight
+// and keep their destination on later visits. name labels the arm in
+// analytics as split:<name>, so keep it when only changing weights.
+type SplitDestinationRequest struct {
+	Name           string `json:"name" binding:"required,max=50" example:"A"`
+	DestinationURL string `json:"destination_url" binding:"required,url" example:"https://example.com/landing-a"`
+	Weight         int    `json:"weight" binding:"required,min=1,max=1000" example:"70"`
+}
+
+// ScheduleRequest limits a link to recurring windows in an IANA time zone,
+// e.g. business hours in Europe/Berlin.
+type ScheduleRequest struct {
+	TimeZone string                  `json:"time_zone" binding:"required_with=Windows,omitempty,timezone" example:"Europe/Berlin"`
+	Windows  []ScheduleWindowRequest `json:"windows" binding:"max=20,dive"`
+}
+
+// ScheduleWindowRequest opens on each of days at start and closes at end,
+// local time. An end not after start closes the next day, so 22:00 to 02:00
+// runs overnight and 00:00 to 00:00 is the whole day.
+type ScheduleWindowRequest struct {
+	Days  []string `json:"days" binding:"required,min=1,max=7,unique,dive,oneof=mon tue wed thu fri sat sun" example:"mon,tue,wed,thu,fri"`
+	Start string   `json:"start" binding:"required,datetime=15:04" example:"09:00"`
+	End   string   `json:"end" binding:"required,datetime=15:04" example:"17:00"`
+}
+
+// ListURLsRequest holds the query parameters of the link list. Times are
+// RFC 3339; ranges include the "from" bound and exclude the "to" bound.
+type ListURLsRequest struct {
+	Page              int        `form:"page,default=1" binding:"min=1"`
+	Limit             int        `form:"limit,default=10" binding:"min=1,max=100"`
+	Cursor            string     `form:"cursor"`
+	Search            string     `form:"search"`
+	Sort              string     `form:"sort,default=created_at" binding:"oneof=created_at click_count unique_click_count title last_clicked_at"`
+	Order             string     `form:"order,default=desc" binding:"oneof=asc desc"`
+	IsActive          *bool      `form:"is_active"`
+	Expired           *bool      `form:"expired"`
+	PasswordProtected *bool      `form:"password_protected"`
+	CreatedFrom       *time.Time `form:"created_from"`
+	CreatedTo         *time.Time `form:"created_to"`
+	ClickedFrom       *time.Time `form:"clicked_from"`
+	ClickedTo         *time.Time `form:"clicked_to"`
+	Domain            string     `form:"domain"`
+	CampaignID        string     `form:"campaign_id" binding:"omitempty,uuid"`
+	TagID             []string   `form:"tag_id" binding:"max=20,dive,uuid"`
+	FolderID          string     `form:"folder_id"`
+}
+
+type ListTrashRequest struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
 }
@@ -7,29 +7,121 @@ import (
 )
 
 type TimeSeriesStat struct {
-	Date   string `json:"date"`
-	Clicks int64  `json:"clicks"`
+	Date         string `json:"date"`
+	Clicks       int64  `json:"clicks"`
+	UniqueClicks int64  `json:"unique_clicks"`
 }
 
 type GroupedStat struct {
-	Value string `json:"value"`
-	Count int64  `json:"count"`
+	Value       string `json:"value"`
+	Count       int64  `json:"count"`
+	UniqueCount int64  `json:"unique_count"`
+}
+
+// LocationStat is a region, or a city when City is set, with its country.
+type LocationStat struct {
+	Country     string `json:"country"`
+	Region      string `json:"region"`
+	City        string `json:"city,omitempty"`
+	Count       int64  `json:"count"`
+	UniqueCount int64  `json:"unique_count"`
 }
 
 type AnalyticsOverview struct {
-	TotalClicks int64  `json:"total_clicks"`
-	TopReferrer string `json:"top_referrer"`
-	TopCountry  string `json:"top_country"`
+	TotalClicks  int64  `json:"total_clicks"`
+	UniqueClicks int64  `json:"unique_clicks"`
+	TopReferrer  string `json:"top_referrer"`
+	TopCountry   string `json:"top_country"`
+}
+
+// AnalyticsWarning names a section of an analytics response that could not
+// be loaded and is empty.
+type AnalyticsWarning struct {
+	Section string `json:"section" example:"referrers"`
+	Message string `json:"message" example:"This section could not be loaded"`
 }
 
+// AnalyticsRange echoes the resolved query: From is inclusive, To exclusive.
+type AnalyticsRange struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Granularity string    `json:"granularity" example:"day"`
+	TimeZone    string    `json:"timezone" example:"UTC"`
+}
+
+// UTMBreakdown holds the most frequent values of each UTM parameter.
+type UTMBreakdown struct {
+	Sources   []GroupedStat `json:"sources"`
+	Mediums   []GroupedStat `json:"mediums"`
+	Campaigns []GroupedStat `json:"campaigns"`
+	Terms     []GroupedStat `json:"terms"`
+	Contents  []GroupedStat `json:"contents"`
+}
+
+// URLAnalyticsResponse is the analytics of one link. Referrers are grouped
+// by referrer domain. Variants counts clicks per destination of links with
+// targeting rules or split destinations.
 type URLAnalyticsResponse struct {
-	Overview         AnalyticsOverview `json:"overview"`
-	ClicksOverTime   []TimeSeriesStat  `json:"clicks_over_time"`
-	Referrers        []GroupedStat     `json:"referrers"`
-	Countries        []GroupedStat     `json:"countries"`
-	Devices          []GroupedStat     `json:"devices"`
-	Browsers         []GroupedStat     `json:"browsers"`
-	OperatingSystems []GroupedStat     `json:"operating_systems"`
+	Range              AnalyticsRange      `json:"range"`
+	Filters            map[string][]string `json:"filters,omitempty"`
+	Overview           AnalyticsOverview   `json:"overview"`
+	ClicksOverTime     []TimeSeriesStat    `json:"clicks_over_time"`
+	Referrers          []GroupedStat       `json:"referrers"`
+	ReferrerCategories []GroupedStat       `json:"referrer_categories"`
+	UTM                UTMBreakdown        `json:"utm"`
+	Variants           []GroupedStat       `json:"variants"`
+	Countries          []GroupedStat       `json:"countries"`
+	Regions            []LocationStat      `json:"regions"`
+	Cities             []LocationStat      `json:"cities"`
+	Devices            []GroupedStat       `json:"devices"`
+	Browsers           []GroupedStat       `json:"browsers"`
+	OperatingSystems   []GroupedStat       `json:"operating_systems"`
+	Languages          []GroupedStat       `json:"languages"`
+	Warnings           []AnalyticsWarning  `json:"warnings,omitempty"`
+}
+
+// LinkGroupAnalyticsResponse aggregates the clicks of every link in a
+// campaign, tag or folder.
+type LinkGroupAnalyticsResponse struct {
+	TotalURLs int64 `json:"total_urls"`
+	URLAnalyticsResponse
+	TopURLs []DashboardTopURL `json:"top_urls"`
+}
+
+type CampaignAnalyticsResponse struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Name       string    `json:"name"`
+	LinkGroupAnalyticsResponse
+}
+
+type TagAnalyticsResponse struct {
+	TagID uuid.UUID `json:"tag_id"`
+	Name  string    `json:"name"`
+	LinkGroupAnalyticsResponse
+}
+
+type FolderAnalyticsResponse struct {
+	FolderID uuid.UUID `json:"folder_id"`
+	Name     string    `json:"name"`
+	LinkGroupAnalyticsResponse
+}
+
+type CampaignAnalyticsSuccessResponse struct {
+	Success   bool                      `json:"success" example:"true"`
+	Data      CampaignAnalyticsResponse `json:"data"`
+	Timestamp time.Time                 `json:"timestamp"`
+}
+
+type TagAnalyticsSuccessResponse struct {
+	Success   bool                 `json:"success" example:"true"`
+	Data      TagAnalyticsResponse `json:"data"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+type FolderAnalyticsSuccessResponse struct {
+	Success   bool                    `json:"success" example:"true"`
+	Data      FolderAnalyticsResponse `json:"data"`
+	Timestamp time.Time               `json:"timestamp"`
 }
 
 type URLAnalyticsSuccessResponse struct {
@@ -39,9 +131,10 @@ type URLAnalyticsSuccessResponse struct {
 }
 
 type DashboardSummary struct {
-	TotalURLs   int64 `json:"total_urls"`
-	TotalClicks int64 `json:"total_clicks"`
-	ActiveURLs  int64 `json:"active_urls"`
+	TotalURLs         int64 `json:"total_urls"`
+	TotalClicks       int64 `json:"total_clicks"`
+	TotalUniqueClicks int64 `json:"total_unique_clicks"`
+	ActiveURLs        int64 `json:"active_urls"`
 }
 
 type DashboardActivityItem struct {
@@ -52,16 +145,29 @@ type DashboardActivityItem struct {
 }
 
 type DashboardTopURL struct {
-	URLID      uuid.UUID `json:"url_id"`
-	ShortCode  string    `json:"short_code"`
-	Title      *string   `json:"title,omitempty"`
-	ClickCount int       `json:"click_count"`
+	URLID            uuid.UUID `json:"url_id"`
+	ShortCode        string    `json:"short_code"`
+	Title            *string   `json:"title,omitempty"`
+	ClickCount       int       `json:"click_count"`
+	UniqueClickCount int       `json:"unique_click_count"`
+}
+
+// DashboardLinkGroup is a tag or folder ranked by the clicks on its links.
+type DashboardLinkGroup struct {
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	ClickCount       int64     `json:"click_count"`
+	UniqueClickCount int64     `json:"unique_click_count"`
 }
 
 type UserDashboardResponse struct {
+	Filters           map[string][]string     `json:"filters,omitempty"`
 	Summary           DashboardSummary        `json:"summary"`
 	RecentActivity    []DashboardActivityItem `json:"recent_activity"`
 	TopPerformingURLs []DashboardTopURL       `json:"top_performing_urls"`
+	TopTags           []DashboardLinkGroup    `json:"top_tags"`
+	TopFolders        []DashboardLinkGroup    `json:"top_folders"`
+	Warnings          []AnalyticsWarning      `json:"warnings,omitempty"`
 }
 
 type UserDashboardSuccessResponse struct {
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" example:"usk_3f9a1c2b"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse carries the secret, which is only ever returned by
+// the create call.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeySuccessResponse struct {
+	Success   bool                  `json:"success" example:"true"`
+	Message   string                `json:"message" example:"API key created successfully"`
+	Data      CreatedAPIKeyResponse `json:"data"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+type APIKeyListSuccessResponse struct {
+	Success   bool             `json:"success" example:"true"`
+	Data      []APIKeyResponse `json:"data"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+func ToAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -16,9 +16,10 @@ type LoginSuccessResponse struct {
 }
 
 type RefreshTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int64  `json:"expires_in"`
-	TokenType   string `json:"token_type" example:"Bearer"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	TokenType    string `json:"token_type" example:"Bearer"`
 }
 
 type RefreshTokenSuccessResponse struct {
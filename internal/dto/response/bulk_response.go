@@ -0,0 +1,63 @@
+package response
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type BulkOperationResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	OperationType  string     `json:"operation_type" example:"create"`
+	FileFormat     string     `json:"file_format" example:"csv"`
+	Status         string     `json:"status" example:"processing"`
+	TotalCount     int        `json:"total_count"`
+	ProcessedCount int        `json:"processed_count"`
+	SuccessCount   int        `json:"success_count"`
+	FailedCount    int        `json:"failed_count"`
+	Progress       float64    `json:"progress" example:"42.5"`
+	ErrorDetails   *string    `json:"error_details,omitempty"`
+	ResultURL      *string    `json:"result_url,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+type BulkOperationSuccessResponse struct {
+	Success   bool                  `json:"success" example:"true"`
+	Message   string                `json:"message,omitempty"`
+	Data      BulkOperationResponse `json:"data"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+func ToBulkOperationResponse(op *domain.BulkOperation) BulkOperationResponse {
+	progress := 0.0
+	if op.TotalCount > 0 {
+		progress = float64(op.ProcessedCount) * 100 / float64(op.TotalCount)
+	}
+
+	var resultURL *string
+	if op.ResultFilePath != nil && (op.Status == domain.BulkStatusCompleted || op.Status == domain.BulkStatusFailed) {
+		url := fmt.Sprintf("/api/v1/bulk/%s/result", op.ID)
+		resultURL = &url
+	}
+
+	return BulkOperationResponse{
+		ID:             op.ID,
+		OperationType:  op.OperationType,
+		FileFormat:     op.FileFormat,
+		Status:         op.Status,
+		TotalCount:     op.TotalCount,
+		ProcessedCount: op.ProcessedCount,
+		SuccessCount:   op.SuccessCount,
+		FailedCount:    op.FailedCount,
+		Progress:       progress,
+		ErrorDetails:   op.ErrorDetails,
+		ResultURL:      resultURL,
+		CreatedAt:      op.CreatedAt,
+		StartedAt:      op.StartedAt,
+		CompletedAt:    op.CompletedAt,
+	}
+}
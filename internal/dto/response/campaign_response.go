@@ -0,0 +1,49 @@
+package response
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type UTMResponse struct {
+	Source   string `json:"source,omitempty" example:"newsletter"`
+	Medium   string `json:"medium,omitempty" example:"email"`
+	Campaign string `json:"campaign,omitempty" example:"spring_sale"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+type CampaignResponse struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description,omitempty"`
+	UTM         UTMResponse `json:"utm"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type CampaignSuccessResponse struct {
+	Success   bool             `json:"success" example:"true"`
+	Message   string           `json:"message,omitempty"`
+	Data      CampaignResponse `json:"data"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+type CampaignListSuccessResponse struct {
+	Success   bool               `json:"success" example:"true"`
+	Data      []CampaignResponse `json:"data"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+func ToCampaignResponse(c *domain.Campaign) CampaignResponse {
+	return CampaignResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		UTM:         UTMResponse(c.UTM),
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
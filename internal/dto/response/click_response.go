@@ -0,0 +1,76 @@
+package response
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ClickResponse is one row of the click log and of click exports. The IP
+// address is already reduced according to the owner's privacy setting.
+type ClickResponse struct {
+	ID               uuid.UUID `json:"id"`
+	URLID            uuid.UUID `json:"url_id"`
+	ShortCode        string    `json:"short_code"`
+	ClickedAt        time.Time `json:"clicked_at"`
+	IPAddress        string    `json:"ip_address"`
+	UserAgent        string    `json:"user_agent"`
+	Referrer         string    `json:"referrer"`
+	ReferrerDomain   string    `json:"referrer_domain"`
+	ReferrerCategory string    `json:"referrer_category"`
+	UTMSource        string    `json:"utm_source"`
+	UTMMedium        string    `json:"utm_medium"`
+	UTMCampaign      string    `json:"utm_campaign"`
+	UTMTerm          string    `json:"utm_term"`
+	UTMContent       string    `json:"utm_content"`
+	Variant          string    `json:"variant"`
+	Country          string    `json:"country"`
+	Region           string    `json:"region"`
+	City             string    `json:"city"`
+	Browser          string    `json:"browser"`
+	OS               string    `json:"os"`
+	DeviceType       string    `json:"device_type"`
+	Language         string    `json:"language"`
+	IsUnique         bool      `json:"is_unique"`
+}
+
+type ClickLogResponse struct {
+	Clicks     []ClickResponse `json:"clicks"`
+	Limit      int             `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type ClickLogSuccessResponse struct {
+	Success   bool             `json:"success" example:"true"`
+	Data      ClickLogResponse `json:"data"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+func ToClickResponse(entry *domain.ClickLogEntry) ClickResponse {
+	return ClickResponse{
+		ID:               entry.ID,
+		URLID:            entry.URLID,
+		ShortCode:        entry.ShortCode,
+		ClickedAt:        entry.ClickedAt,
+		IPAddress:        entry.IPAddress,
+		UserAgent:        entry.UserAgent,
+		Referrer:         entry.Referer,
+		ReferrerDomain:   entry.ReferrerDomain,
+		ReferrerCategory: entry.ReferrerCategory,
+		UTMSource:        entry.UTMSource,
+		UTMMedium:        entry.UTMMedium,
+		UTMCampaign:      entry.UTMCampaign,
+		UTMTerm:          entry.UTMTerm,
+		UTMContent:       entry.UTMContent,
+		Variant:          entry.Variant,
+		Country:          entry.Country,
+		Region:           entry.Region,
+		City:             entry.City,
+		Browser:          entry.Browser,
+		OS:               entry.OS,
+		DeviceType:       entry.DeviceType,
+		Language:         entry.Language,
+		IsUnique:         entry.IsUnique,
+	}
+}
@@ -0,0 +1,53 @@
+package response
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type DomainVerificationRecord struct {
+	Type  string `json:"type" example:"TXT"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type DomainResponse struct {
+	ID                 uuid.UUID                `json:"id"`
+	DomainName         string                   `json:"domain_name"`
+	IsVerified         bool                     `json:"is_verified"`
+	IsActive           bool                     `json:"is_active"`
+	VerificationRecord DomainVerificationRecord `json:"verification_record"`
+	CreatedAt          time.Time                `json:"created_at"`
+	VerifiedAt         *time.Time               `json:"verified_at,omitempty"`
+}
+
+type DomainSuccessResponse struct {
+	Success   bool           `json:"success" example:"true"`
+	Message   string         `json:"message,omitempty"`
+	Data      DomainResponse `json:"data"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+type DomainListSuccessResponse struct {
+	Success   bool             `json:"success" example:"true"`
+	Data      []DomainResponse `json:"data"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+func ToDomainResponse(d *domain.Domain, verificationPrefix string) DomainResponse {
+	return DomainResponse{
+		ID:         d.ID,
+		DomainName: d.DomainName,
+		IsVerified: d.IsVerified,
+		IsActive:   d.IsActive,
+		VerificationRecord: DomainVerificationRecord{
+			Type:  "TXT",
+			Name:  verificationPrefix + "." + d.DomainName,
+			Value: d.VerificationToken,
+		},
+		CreatedAt:  d.CreatedAt,
+		VerifiedAt: d.VerifiedAt,
+	}
+}
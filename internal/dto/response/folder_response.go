@@ -0,0 +1,35 @@
+package response
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type FolderResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FolderSuccessResponse struct {
+	Success   bool           `json:"success" example:"true"`
+	Message   string         `json:"message,omitempty"`
+	Data      FolderResponse `json:"data"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+type FolderListSuccessResponse struct {
+	Success   bool             `json:"success" example:"true"`
+	Data      []FolderResponse `json:"data"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+func ToFolderResponse(f *domain.Folder) FolderResponse {
+	return FolderResponse{
+		ID:        f.ID,
+		Name:      f.Name,
+		CreatedAt: f.CreatedAt,
+	}
+}
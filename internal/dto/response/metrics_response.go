@@ -0,0 +1,41 @@
+package response
+
+import "time"
+
+type ClickPipelineMetrics struct {
+	QueueDepth    int    `json:"queue_depth"`
+	QueueCapacity int    `json:"queue_capacity"`
+	Workers       int    `json:"workers"`
+	Enqueued      uint64 `json:"enqueued"`
+	Backpressured uint64 `json:"backpressured"`
+	Dropped       uint64 `json:"dropped"`
+	Written       uint64 `json:"written"`
+	Failed        uint64 `json:"failed"`
+	Batches       uint64 `json:"batches"`
+}
+
+// URLCacheMetrics describes the short-code lookup cache. Entries and
+// capacity are only reported by the in-process cache.
+type URLCacheMetrics struct {
+	Backend   string  `json:"backend" example:"memory"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	HitRatio  float64 `json:"hit_ratio"`
+	Sets      uint64  `json:"sets"`
+	Deletes   uint64  `json:"deletes"`
+	Evictions uint64  `json:"evictions"`
+	Errors    uint64  `json:"errors"`
+	Entries   int     `json:"entries,omitempty"`
+	Capacity  int     `json:"capacity,omitempty"`
+}
+
+type MetricsResponse struct {
+	ClickPipeline ClickPipelineMetrics `json:"click_pipeline"`
+	URLCache      *URLCacheMetrics     `json:"url_cache,omitempty"`
+}
+
+type MetricsSuccessResponse struct {
+	Success   bool            `json:"success" example:"true"`
+	Data      MetricsResponse `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
@@ -2,9 +2,11 @@ package response
 
 import "time"
 
+// UnlockURLResponse points at the short link, which the unlock cookie opens
+// until ExpiresAt. The destination itself is only revealed by the redirect.
 type UnlockURLResponse struct {
-	RedirectURL string `json:"redirect_url"`
-	AccessToken string `json:"access_token"`
+	RedirectURL string    `json:"redirect_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
 
 type UnlockURLSuccessResponse struct {
@@ -13,15 +15,34 @@ type UnlockURLSuccessResponse struct {
 	Timestamp time.Time         `json:"timestamp"`
 }
 
+// URLInfoResponse previews a link. Password-protected and click-capped
+// links, and links that are not available, leave out their destination,
+// which would otherwise bypass the password, the cap or the schedule;
+// RemainingClicks is only set for capped links.
 type URLInfoResponse struct {
-	OriginalURL string    `json:"original_url"`
-	ShortURL    string    `json:"short_url"`
-	Title       *string   `json:"title,omitempty"`
-	Description *string   `json:"description,omitempty"`
-	ClickCount  int       `json:"click_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	IsSafe      bool      `json:"is_safe"`
-	Domain      string    `json:"domain"`
+	OriginalURL         string                    `json:"original_url,omitempty"`
+	ShortURL            string                    `json:"short_url"`
+	Title               *string                   `json:"title,omitempty"`
+	Description         *string                   `json:"description,omitempty"`
+	ClickCount          int                       `json:"click_count"`
+	RemainingClicks     *int                      `json:"remaining_clicks,omitempty"`
+	CreatedAt           time.Time                 `json:"created_at"`
+	IsSafe              bool                      `json:"is_safe"`
+	IsPasswordProtected bool                      `json:"is_password_protected"`
+	Domain              string                    `json:"domain"`
+	Schedule            URLScheduleStatusResponse `json:"schedule"`
+}
+
+// URLScheduleStatusResponse tells whether a link redirects now. A link that
+// is not available reports when it opens next in available_from, unless it
+// never will; available_until is when it closes next. time_zone is the one
+// of the link's schedule, for showing the times as the owner meant them.
+type URLScheduleStatusResponse struct {
+	State          string     `json:"state" enums:"available,scheduled,closed,expired" example:"scheduled"`
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+	TimeZone       string     `json:"time_zone,omitempty" example:"Europe/Berlin"`
+	ExpiredMessage *string    `json:"expired_message,omitempty" example:"This offer has ended."`
 }
 
 type URLInfoSuccessResponse struct {
@@ -0,0 +1,57 @@
+package response
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TagResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TagSuccessResponse struct {
+	Success   bool        `json:"success" example:"true"`
+	Message   string      `json:"message,omitempty"`
+	Data      TagResponse `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+type TagListSuccessResponse struct {
+	Success   bool          `json:"success" example:"true"`
+	Data      []TagResponse `json:"data"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// BulkTagResponse counts the link tags actually added and removed.
+type BulkTagResponse struct {
+	Added   int64 `json:"added"`
+	Removed int64 `json:"removed"`
+}
+
+type BulkTagSuccessResponse struct {
+	Success   bool            `json:"success" example:"true"`
+	Message   string          `json:"message,omitempty"`
+	Data      BulkTagResponse `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+func ToTagResponse(t *domain.Tag) TagResponse {
+	return TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// ToTagResponses never returns nil, so links without tags list "tags": [].
+func ToTagResponses(tags []domain.Tag) []TagResponse {
+	tagResponses := make([]TagResponse, len(tags))
+	for i := range tags {
+		tagResponses[i] = ToTagResponse(&tags[i])
+	}
+	return tagResponses
+}
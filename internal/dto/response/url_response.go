@@ -8,15 +8,56 @@ import (
 )
 
 type CreateURLResponse struct {
-	ID          uuid.UUID  `json:"id"`
-	OriginalURL string     `json:"original_url"`
-	ShortCode   string     `json:"short_code"`
-	ShortURL    string     `json:"short_url"`
-	CustomAlias *string    `json:"custom_alias,omitempty"`
-	Title       *string    `json:"title,omitempty"`
-	QRCode      string     `json:"qr_code"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ID                 uuid.UUID                  `json:"id"`
+	OriginalURL        string                     `json:"original_url"`
+	ShortCode          string                     `json:"short_code"`
+	ShortURL           string                     `json:"short_url"`
+	CustomAlias        *string                    `json:"custom_alias,omitempty"`
+	CampaignID         *uuid.UUID                 `json:"campaign_id,omitempty"`
+	FolderID           *uuid.UUID                 `json:"folder_id,omitempty"`
+	Tags               []TagResponse              `json:"tags"`
+	GeoRules           []GeoRuleResponse          `json:"geo_rules"`
+	DeviceRules        []DeviceRuleResponse       `json:"device_rules"`
+	SplitDestinations  []SplitDestinationResponse `json:"split_destinations"`
+	Title              *string                    `json:"title,omitempty"`
+	QRCode             string                     `json:"qr_code"`
+	StartsAt           *time.Time                 `json:"starts_at,omitempty"`
+	ExpiresAt          *time.Time                 `json:"expires_at,omitempty"`
+	Schedule           *ScheduleResponse          `json:"schedule,omitempty"`
+	ExpiredRedirectURL *string                    `json:"expired_redirect_url,omitempty"`
+	ExpiredMessage     *string                    `json:"expired_message,omitempty"`
+	MaxClicks          *int                       `json:"max_clicks,omitempty"`
+	CapFallbackURL     *string                    `json:"cap_fallback_url,omitempty"`
+	CreatedAt          time.Time                  `json:"created_at"`
+}
+
+type GeoRuleResponse struct {
+	Country        string `json:"country" example:"US"`
+	Region         string `json:"region,omitempty" example:"California"`
+	DestinationURL string `json:"destination_url" example:"https://example.com/us"`
+}
+
+type DeviceRuleResponse struct {
+	Platform       string `json:"platform" example:"ios"`
+	DestinationURL string `json:"destination_url" example:"myapp://product/42"`
+	FallbackURL    string `json:"fallback_url,omitempty" example:"https://apps.apple.com/app/id123456789"`
+}
+
+type SplitDestinationResponse struct {
+	Name           string `json:"name" example:"A"`
+	DestinationURL string `json:"destination_url" example:"https://example.com/landing-a"`
+	Weight         int    `json:"weight" example:"70"`
+}
+
+type ScheduleResponse struct {
+	TimeZone string                   `json:"time_zone" example:"Europe/Berlin"`
+	Windows  []ScheduleWindowResponse `json:"windows"`
+}
+
+type ScheduleWindowResponse struct {
+	Days  []string `json:"days" example:"mon,tue,wed,thu,fri"`
+	Start string   `json:"start" example:"09:00"`
+	End   string   `json:"end" example:"17:00"`
 }
 
 type CreateURLSuccessResponse struct {
@@ -27,22 +68,29 @@ type CreateURLSuccessResponse struct {
 }
 
 type URLListItemResponse struct {
-	ID          uuid.UUID  `json:"id"`
-	OriginalURL string     `json:"original_url"`
-	ShortCode   string     `json:"short_code"`
-	ShortURL    string     `json:"short_url"`
-	Title       *string    `json:"title,omitempty"`
-	ClickCount  int        `json:"click_count"`
-	IsActive    bool       `json:"is_active"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ID               uuid.UUID     `json:"id"`
+	OriginalURL      string        `json:"original_url"`
+	ShortCode        string        `json:"short_code"`
+	ShortURL         string        `json:"short_url"`
+	CampaignID       *uuid.UUID    `json:"campaign_id,omitempty"`
+	FolderID         *uuid.UUID    `json:"folder_id,omitempty"`
+	Tags             []TagResponse `json:"tags"`
+	Title            *string       `json:"title,omitempty"`
+	ClickCount       int           `json:"click_count"`
+	UniqueClickCount int           `json:"unique_click_count"`
+	IsActive         bool          `json:"is_active"`
+	ExpiresAt        *time.Time    `json:"expires_at,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
 }
 
+// PaginationResponse describes a page of a list. Page is omitted for pages
+// requested by cursor; NextCursor is omitted on the last page.
 type PaginationResponse struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"total_pages"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type URLListResponse struct {
@@ -57,21 +105,35 @@ type URLListSuccessResponse struct {
 }
 
 type URLDetailsResponse struct {
-	ID                  uuid.UUID  `json:"id"`
-	OriginalURL         string     `json:"original_url"`
-	ShortCode           string     `json:"short_code"`
-	ShortURL            string     `json:"short_url"`
-	CustomAlias         *string    `json:"custom_alias,omitempty"`
-	Title               *string    `json:"title,omitempty"`
-	Description         *string    `json:"description,omitempty"`
-	ClickCount          int        `json:"click_count"`
-	UniqueClickCount    int        `json:"unique_click_count"`
-	IsActive            bool       `json:"is_active"`
-	IsPasswordProtected bool       `json:"is_password_protected"`
-	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
-	CreatedAt           time.Time  `json:"created_at"`
-	UpdatedAt           time.Time  `json:"updated_at"`
-	LastClickedAt       *time.Time `json:"last_clicked_at,omitempty"`
+	ID                  uuid.UUID                  `json:"id"`
+	OriginalURL         string                     `json:"original_url"`
+	ShortCode           string                     `json:"short_code"`
+	ShortURL            string                     `json:"short_url"`
+	CustomAlias         *string                    `json:"custom_alias,omitempty"`
+	CampaignID          *uuid.UUID                 `json:"campaign_id,omitempty"`
+	FolderID            *uuid.UUID                 `json:"folder_id,omitempty"`
+	Tags                []TagResponse              `json:"tags"`
+	GeoRules            []GeoRuleResponse          `json:"geo_rules"`
+	DeviceRules         []DeviceRuleResponse       `json:"device_rules"`
+	SplitDestinations   []SplitDestinationResponse `json:"split_destinations"`
+	Title               *string                    `json:"title,omitempty"`
+	Description         *string                    `json:"description,omitempty"`
+	ClickCount          int                        `json:"click_count"`
+	UniqueClickCount    int                        `json:"unique_click_count"`
+	IsActive            bool                       `json:"is_active"`
+	IsPasswordProtected bool                       `json:"is_password_protected"`
+	StartsAt            *time.Time                 `json:"starts_at,omitempty"`
+	ExpiresAt           *time.Time                 `json:"expires_at,omitempty"`
+	Schedule            *ScheduleResponse          `json:"schedule,omitempty"`
+	ScheduleState       string                     `json:"schedule_state" example:"available"`
+	ExpiredRedirectURL  *string                    `json:"expired_redirect_url,omitempty"`
+	ExpiredMessage      *string                    `json:"expired_message,omitempty"`
+	MaxClicks           *int                       `json:"max_clicks,omitempty"`
+	RemainingClicks     *int                       `json:"remaining_clicks,omitempty"`
+	CapFallbackURL      *string                    `json:"cap_fallback_url,omitempty"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	UpdatedAt           time.Time                  `json:"updated_at"`
+	LastClickedAt       *time.Time                 `json:"last_clicked_at,omitempty"`
 }
 
 type URLDetailsSuccessResponse struct {
@@ -80,17 +142,54 @@ type URLDetailsSuccessResponse struct {
 	Timestamp time.Time          `json:"timestamp"`
 }
 
+// TrashItemResponse is a deleted link. It can be restored until PurgeAt,
+// when it is removed together with its analytics.
+type TrashItemResponse struct {
+	ID          uuid.UUID `json:"id"`
+	OriginalURL string    `json:"original_url"`
+	ShortCode   string    `json:"short_code"`
+	ShortURL    string    `json:"short_url"`
+	Title       *string   `json:"title,omitempty"`
+	ClickCount  int       `json:"click_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
+
+type TrashListResponse struct {
+	URLs       []TrashItemResponse `json:"urls"`
+	Pagination PaginationResponse  `json:"pagination"`
+}
+
+type TrashListSuccessResponse struct {
+	Success   bool              `json:"success" example:"true"`
+	Data      TrashListResponse `json:"data"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
 func ToCreateURLResponse(url *domain.URL, shortURL, qrCode string) CreateURLResponse {
 	return CreateURLResponse{
-		ID:          url.ID,
-		OriginalURL: url.OriginalURL,
-		ShortCode:   url.ShortCode,
-		ShortURL:    shortURL,
-		CustomAlias: url.CustomAlias,
-		Title:       url.Title,
-		QRCode:      qrCode,
-		ExpiresAt:   url.ExpiresAt,
-		CreatedAt:   url.CreatedAt,
+		ID:                 url.ID,
+		OriginalURL:        url.OriginalURL,
+		ShortCode:          url.ShortCode,
+		ShortURL:           shortURL,
+		CustomAlias:        url.CustomAlias,
+		CampaignID:         url.CampaignID,
+		FolderID:           url.FolderID,
+		Tags:               ToTagResponses(url.Tags),
+		GeoRules:           ToGeoRuleResponses(url.GeoRules),
+		DeviceRules:        ToDeviceRuleResponses(url.DeviceRules),
+		SplitDestinations:  ToSplitDestinationResponses(url.SplitDestinations),
+		Title:              url.Title,
+		QRCode:             qrCode,
+		StartsAt:           url.StartsAt,
+		ExpiresAt:          url.ExpiresAt,
+		Schedule:           ToScheduleResponse(url.Schedule),
+		ExpiredRedirectURL: url.ExpiredRedirectURL,
+		ExpiredMessage:     url.ExpiredMessage,
+		MaxClicks:          url.MaxClicks,
+		CapFallbackURL:     url.CapFallbackURL,
+		CreatedAt:          url.CreatedAt,
 	}
 }
 
@@ -101,15 +200,68 @@ func ToURLDetailsResponse(url *domain.URL, shortURL string) URLDetailsResponse {
 		ShortCode:           url.ShortCode,
 		ShortURL:            shortURL,
 		CustomAlias:         url.CustomAlias,
+		CampaignID:          url.CampaignID,
+		FolderID:            url.FolderID,
+		Tags:                ToTagResponses(url.Tags),
+		GeoRules:            ToGeoRuleResponses(url.GeoRules),
+		DeviceRules:         ToDeviceRuleResponses(url.DeviceRules),
+		SplitDestinations:   ToSplitDestinationResponses(url.SplitDestinations),
 		Title:               url.Title,
 		Description:         url.Description,
 		ClickCount:          url.ClickCount,
 		UniqueClickCount:    url.UniqueClickCount,
 		IsActive:            url.IsActive,
-		IsPasswordProtected: url.PasswordHash != nil,
+		IsPasswordProtected: url.IsPasswordProtected(),
+		StartsAt:            url.StartsAt,
 		ExpiresAt:           url.ExpiresAt,
+		Schedule:            ToScheduleResponse(url.Schedule),
+		ScheduleState:       url.ScheduleStatus(time.Now()).State,
+		ExpiredRedirectURL:  url.ExpiredRedirectURL,
+		ExpiredMessage:      url.ExpiredMessage,
+		MaxClicks:           url.MaxClicks,
+		RemainingClicks:     url.RemainingClicks(),
+		CapFallbackURL:      url.CapFallbackURL,
 		CreatedAt:           url.CreatedAt,
 		UpdatedAt:           url.UpdatedAt,
 		LastClickedAt:       url.LastClickedAt,
 	}
 }
+
+// ToGeoRuleResponses never returns nil, so links without rules list
+// "geo_rules": [].
+func ToGeoRuleResponses(rules []domain.GeoRule) []GeoRuleResponse {
+	ruleResponses := make([]GeoRuleResponse, len(rules))
+	for i, rule := range rules {
+		ruleResponses[i] = GeoRuleResponse(rule)
+	}
+	return ruleResponses
+}
+
+// ToDeviceRuleResponses never returns nil, like ToGeoRuleResponses.
+func ToDeviceRuleResponses(rules []domain.DeviceRule) []DeviceRuleResponse {
+	ruleResponses := make([]DeviceRuleResponse, len(rules))
+	for i, rule := range rules {
+		ruleResponses[i] = DeviceRuleResponse(rule)
+	}
+	return ruleResponses
+}
+
+// ToSplitDestinationResponses never returns nil, like ToGeoRuleResponses.
+func ToSplitDestinationResponses(destinations []domain.SplitDestination) []SplitDestinationResponse {
+	destinationResponses := make([]SplitDestinationResponse, len(destinations))
+	for i, d := range destinations {
+		destinationResponses[i] = SplitDestinationResponse(d)
+	}
+	return destinationResponses
+}
+
+func ToScheduleResponse(schedule *domain.Schedule) *ScheduleResponse {
+	if schedule == nil {
+		return nil
+	}
+	windows := make([]ScheduleWindowResponse, len(schedule.Windows))
+	for i, w := range schedule.Windows {
+		windows[i] = ScheduleWindowResponse(w)
+	}
+	return &ScheduleResponse{TimeZone: schedule.TimeZone, Windows: windows}
+}
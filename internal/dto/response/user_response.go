@@ -13,7 +13,7 @@ type UserResponse struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	PlanType  string    `json:"plan_type"`
-	APIKey    string    `json:"api_key"`
+	IPPrivacy string    `json:"ip_privacy" example:"masked"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -31,7 +31,7 @@ func ToUserResponse(user *domain.User) UserResponse {
 		FirstName: *user.FirstName,
 		LastName:  *user.LastName,
 		PlanType:  user.PlanType,
-		APIKey:    user.APIKey,
+		IPPrivacy: user.IPPrivacy,
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
 	"github.com/gin-gonic/gin"
@@ -20,28 +21,60 @@ func NewAnalyticsHandler(analyticsService services.AnalyticsService) *AnalyticsH
 
 // GetURLAnalytics godoc
 // @Summary Get URL analytics
-// @Description Retrieves detailed analytics for a specific URL.
+// @Description Retrieves detailed analytics for a specific URL. Dimension filters such as country and device restrict every figure to the matching clicks; repeat a filter to match any of several values. Use period for a preset range ending now, or from and to for a custom range; from is inclusive and to exclusive. Clicks over time are bucketed by granularity in the tz time zone and include empty buckets. Day, week and month buckets are labelled with their local date, shorter buckets with their RFC 3339 start time. A series may have at most 1000 buckets. Referrers are grouped by domain and classified as search, social, email, direct or other; utm breaks clicks down by the UTM parameters of the short-link request. variants counts clicks per destination of links with targeting rules or split destinations, e.g. geo:US, split:A, or default for visitors no rule matched; use it to compare the arms of an A/B split. If a breakdown cannot be loaded it is returned empty and named in warnings; if the overview or the time series cannot be loaded the request fails.
 // @Tags Analytics
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce  json
 // @Param    url_id path string true "URL ID" format(uuid)
-// @Param period query string false "Time period for analytics" Enums(24h, 7d, 30d, all) default(7d)
+// @Param period query string false "Preset time period, ignored when from is set" Enums(24h, 7d, 30d, all) default(7d)
+// @Param from query string false "Start of a custom range (RFC 3339)" format(date-time)
+// @Param to query string false "End of a custom range (RFC 3339), defaults to now" format(date-time)
+// @Param granularity query string false "Bucket size of clicks over time; chosen from the range length when omitted" Enums(minute, hour, day, week, month)
+// @Param tz query string false "IANA time zone for bucketing, e.g. Asia/Jakarta" default(UTC)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param region query []string false "Only clicks from these regions" collectionFormat(multi)
+// @Param city query []string false "Only clicks from these cities" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param browser query []string false "Only clicks from these browsers" collectionFormat(multi)
+// @Param os query []string false "Only clicks from these operating systems" collectionFormat(multi)
+// @Param language query []string false "Only clicks whose preferred language is one of these, e.g. en" collectionFormat(multi)
+// @Param referrer query []string false "Only clicks with these exact referrers" collectionFormat(multi)
+// @Param referrer_domain query []string false "Only clicks referred from these hosts, without www., e.g. twitter.com" collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_source query []string false "Only clicks with these utm_source values" collectionFormat(multi)
+// @Param utm_medium query []string false "Only clicks with these utm_medium values" collectionFormat(multi)
+// @Param utm_campaign query []string false "Only clicks with these utm_campaign values" collectionFormat(multi)
+// @Param utm_term query []string false "Only clicks with these utm_term values" collectionFormat(multi)
+// @Param utm_content query []string false "Only clicks with these utm_content values" collectionFormat(multi)
+// @Param variant query []string false "Only clicks sent to these destinations, e.g. geo:US, split:A or default" collectionFormat(multi)
 // @Success 200 {object} response.URLAnalyticsSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Invalid range, granularity or time zone"
 // @Failure 403 {object} response.APIErrorResponse "Forbidden"
 // @Failure 404 {object} response.APIErrorResponse "URL not found"
+// @Failure 500 {object} response.APIErrorResponse "Analytics could not be loaded"
+// @Failure 504 {object} response.APIErrorResponse "Analytics took too long"
 // @Router /urls/{url_id}/analytics [get]
 func (h *AnalyticsHandler) GetURLAnalytics(c *gin.Context) {
 	urlID, _ := uuid.Parse(c.Param("urlID"))
 	userID := c.MustGet("userID").(uuid.UUID)
-	period := c.DefaultQuery("period", "7d")
 
-	analyticsData, err := h.analyticsService.GetURLAnalytics(urlID, userID, period)
+	var req request.URLAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	analyticsData, err := h.analyticsService.GetURLAnalytics(c.Request.Context(), urlID, userID, req)
 	if err != nil {
-		if err.Error() == "URL_FORBIDDEN" {
+		switch err.Error() {
+		case "URL_FORBIDDEN":
 			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this URL", nil)
-			return
+		case "URL_NOT_FOUND":
+			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found", nil)
+		default:
+			sendAnalyticsError(c, err, "Failed to retrieve analytics for URL")
 		}
-		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Could not retrieve analytics for URL", nil)
 		return
 	}
 
@@ -52,20 +85,229 @@ func (h *AnalyticsHandler) GetURLAnalytics(c *gin.Context) {
 	})
 }
 
+// GetCampaignAnalytics godoc
+// @Summary Get campaign analytics
+// @Description Aggregates the analytics of every link in a campaign, except links in the trash, with the same sections, ranges and filters as the URL analytics plus the campaign's top links. The "all" period starts when the campaign was created.
+// @Tags Analytics
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    campaign_id path string true "Campaign ID" format(uuid)
+// @Param period query string false "Preset time period, ignored when from is set" Enums(24h, 7d, 30d, all) default(7d)
+// @Param from query string false "Start of a custom range (RFC 3339)" format(date-time)
+// @Param to query string false "End of a custom range (RFC 3339), defaults to now" format(date-time)
+// @Param granularity query string false "Bucket size of clicks over time; chosen from the range length when omitted" Enums(minute, hour, day, week, month)
+// @Param tz query string false "IANA time zone for bucketing, e.g. Asia/Jakarta" default(UTC)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_source query []string false "Only clicks with these utm_source values" collectionFormat(multi)
+// @Param utm_content query []string false "Only clicks with these utm_content values" collectionFormat(multi)
+// @Param variant query []string false "Only clicks sent to these destinations, e.g. geo:US, split:A or default" collectionFormat(multi)
+// @Success 200 {object} response.CampaignAnalyticsSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Invalid range, granularity or time zone"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Campaign not found"
+// @Failure 500 {object} response.APIErrorResponse "Analytics could not be loaded"
+// @Failure 504 {object} response.APIErrorResponse "Analytics took too long"
+// @Router /campaigns/{campaign_id}/analytics [get]
+func (h *AnalyticsHandler) GetCampaignAnalytics(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("campaignID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid campaign ID format", nil)
+		return
+	}
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req request.URLAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	analyticsData, err := h.analyticsService.GetCampaignAnalytics(c.Request.Context(), campaignID, userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "CAMPAIGN_FORBIDDEN":
+			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this campaign", nil)
+		case "CAMPAIGN_NOT_FOUND":
+			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Campaign not found", nil)
+		default:
+			sendAnalyticsError(c, err, "Failed to retrieve analytics for campaign")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.CampaignAnalyticsSuccessResponse{
+		Success:   true,
+		Data:      *analyticsData,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetTagAnalytics godoc
+// @Summary Get tag analytics
+// @Description Aggregates the analytics of every link with a tag, except links in the trash, with the same sections, ranges and filters as the URL analytics plus the tag's top links. Clicks are counted whenever they happened, even before the link was tagged. The "all" period starts when the tag was created.
+// @Tags Analytics
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    tag_id path string true "Tag ID" format(uuid)
+// @Param period query string false "Preset time period, ignored when from is set" Enums(24h, 7d, 30d, all) default(7d)
+// @Param from query string false "Start of a custom range (RFC 3339)" format(date-time)
+// @Param to query string false "End of a custom range (RFC 3339), defaults to now" format(date-time)
+// @Param granularity query string false "Bucket size of clicks over time; chosen from the range length when omitted" Enums(minute, hour, day, week, month)
+// @Param tz query string false "IANA time zone for bucketing, e.g. Asia/Jakarta" default(UTC)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_source query []string false "Only clicks with these utm_source values" collectionFormat(multi)
+// @Param utm_content query []string false "Only clicks with these utm_content values" collectionFormat(multi)
+// @Param variant query []string false "Only clicks sent to these destinations, e.g. geo:US, split:A or default" collectionFormat(multi)
+// @Success 200 {object} response.TagAnalyticsSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Invalid range, granularity or time zone"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Tag not found"
+// @Failure 500 {object} response.APIErrorResponse "Analytics could not be loaded"
+// @Failure 504 {object} response.APIErrorResponse "Analytics took too long"
+// @Router /tags/{tag_id}/analytics [get]
+func (h *AnalyticsHandler) GetTagAnalytics(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid tag ID format", nil)
+		return
+	}
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req request.URLAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	analyticsData, err := h.analyticsService.GetTagAnalytics(c.Request.Context(), tagID, userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "TAG_FORBIDDEN":
+			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this tag", nil)
+		case "TAG_NOT_FOUND":
+			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Tag not found", nil)
+		default:
+			sendAnalyticsError(c, err, "Failed to retrieve analytics for tag")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.TagAnalyticsSuccessResponse{
+		Success:   true,
+		Data:      *analyticsData,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetFolderAnalytics godoc
+// @Summary Get folder analytics
+// @Description Aggregates the analytics of every link in a folder, except links in the trash, with the same sections, ranges and filters as the URL analytics plus the folder's top links. Clicks are counted whenever they happened, even before the link was moved into the folder. The "all" period starts when the folder was created.
+// @Tags Analytics
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    folder_id path string true "Folder ID" format(uuid)
+// @Param period query string false "Preset time period, ignored when from is set" Enums(24h, 7d, 30d, all) default(7d)
+// @Param from query string false "Start of a custom range (RFC 3339)" format(date-time)
+// @Param to query string false "End of a custom range (RFC 3339), defaults to now" format(date-time)
+// @Param granularity query string false "Bucket size of clicks over time; chosen from the range length when omitted" Enums(minute, hour, day, week, month)
+// @Param tz query string false "IANA time zone for bucketing, e.g. Asia/Jakarta" default(UTC)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_source query []string false "Only clicks with these utm_source values" collectionFormat(multi)
+// @Param utm_content query []string false "Only clicks with these utm_content values" collectionFormat(multi)
+// @Param variant query []string false "Only clicks sent to these destinations, e.g. geo:US, split:A or default" collectionFormat(multi)
+// @Success 200 {object} response.FolderAnalyticsSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Invalid range, granularity or time zone"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Folder not found"
+// @Failure 500 {object} response.APIErrorResponse "Analytics could not be loaded"
+// @Failure 504 {object} response.APIErrorResponse "Analytics took too long"
+// @Router /folders/{folder_id}/analytics [get]
+func (h *AnalyticsHandler) GetFolderAnalytics(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid folder ID format", nil)
+		return
+	}
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req request.URLAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	analyticsData, err := h.analyticsService.GetFolderAnalytics(c.Request.Context(), folderID, userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "FOLDER_FORBIDDEN":
+			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this folder", nil)
+		case "FOLDER_NOT_FOUND":
+			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Folder not found", nil)
+		default:
+			sendAnalyticsError(c, err, "Failed to retrieve analytics for folder")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FolderAnalyticsSuccessResponse{
+		Success:   true,
+		Data:      *analyticsData,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
 // GetUserDashboard godoc
 // @Summary Get user dashboard analytics
-// @Description Retrieves summary analytics for the authenticated user's dashboard.
+// @Description Retrieves summary analytics for the authenticated user's dashboard. top_tags and top_folders rank the user's tags and folders by the clicks on their links. With dimension filters, the click totals and the top URLs, tags and folders count only the matching clicks. If the top URLs, tags or folders or the recent activity cannot be loaded they are returned empty and named in warnings; if the summary cannot be loaded the request fails.
 // @Tags Analytics
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce  json
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param region query []string false "Only clicks from these regions" collectionFormat(multi)
+// @Param city query []string false "Only clicks from these cities" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param browser query []string false "Only clicks from these browsers" collectionFormat(multi)
+// @Param os query []string false "Only clicks from these operating systems" collectionFormat(multi)
+// @Param language query []string false "Only clicks whose preferred language is one of these, e.g. en" collectionFormat(multi)
+// @Param referrer query []string false "Only clicks with these exact referrers" collectionFormat(multi)
+// @Param referrer_domain query []string false "Only clicks referred from these hosts, without www., e.g. twitter.com" collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_source query []string false "Only clicks with these utm_source values" collectionFormat(multi)
+// @Param utm_medium query []string false "Only clicks with these utm_medium values" collectionFormat(multi)
+// @Param utm_campaign query []string false "Only clicks with these utm_campaign values" collectionFormat(multi)
+// @Param utm_term query []string false "Only clicks with these utm_term values" collectionFormat(multi)
+// @Param utm_content query []string false "Only clicks with these utm_content values" collectionFormat(multi)
+// @Param variant query []string false "Only clicks sent to these destinations, e.g. geo:US, split:A or default" collectionFormat(multi)
 // @Success 200 {object} response.UserDashboardSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
 // @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Failure 500 {object} response.APIErrorResponse "Dashboard could not be loaded"
+// @Failure 504 {object} response.APIErrorResponse "Dashboard took too long"
 // @Router /analytics/dashboard [get]
 func (h *AnalyticsHandler) GetUserDashboard(c *gin.Context) {
-	userID := c.MustGet("userID").(uuid.UUID)
+	var req request.DashboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
 
-	dashboardData, err := h.analyticsService.GetUserDashboard(userID)
+	userID := c.MustGet("userID").(uuid.UUID)
+	dashboardData, err := h.analyticsService.GetUserDashboard(c.Request.Context(), userID, req)
 	if err != nil {
+		if err.Error() == "ANALYTICS_TIMEOUT" {
+			response.SendError(c, http.StatusGatewayTimeout, "ANALYTICS_TIMEOUT", "Dashboard took too long to compute", nil)
+			return
+		}
 		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve dashboard data", nil)
 		return
 	}
@@ -76,3 +318,22 @@ func (h *AnalyticsHandler) GetUserDashboard(c *gin.Context) {
 		Timestamp: time.Now().UTC(),
 	})
 }
+
+// sendAnalyticsError reports the errors shared by the click analytics
+// endpoints: an invalid range or time zone, and a timeout.
+func sendAnalyticsError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "ANALYTICS_INVALID_TIMEZONE":
+		response.SendError(c, http.StatusBadRequest, "INVALID_TIMEZONE", "tz must be an IANA time zone name", []response.ErrorDetail{{Field: "tz", Message: "unknown time zone"}})
+	case "ANALYTICS_INVALID_RANGE":
+		response.SendError(c, http.StatusBadRequest, "INVALID_RANGE", "from must be before to", nil)
+	case "ANALYTICS_INVALID_GRANULARITY":
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "granularity must be one of minute, hour, day, week, month", nil)
+	case "ANALYTICS_TOO_MANY_BUCKETS":
+		response.SendError(c, http.StatusBadRequest, "TOO_MANY_BUCKETS", "The range is too long for this granularity; use a larger granularity or a shorter range", nil)
+	case "ANALYTICS_TIMEOUT":
+		response.SendError(c, http.StatusGatewayTimeout, "ANALYTICS_TIMEOUT", "Analytics took too long to compute; try a shorter range", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Creates a named API key limited to the given scopes (urls:read, urls:write, analytics:read, domains:read, domains:write). The key is only shown in this response; store it securely.
+// @Tags API Keys
+// @Security BearerAuth
+// @Accept   json
+// @Produce  json
+// @Param    api_key body request.CreateAPIKeyRequest true "API Key Information"
+// @Success 201 {object} response.APIKeySuccessResponse "API key created successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Failure 403 {object} response.APIErrorResponse "Requires a bearer token"
+// @Router /profile/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req request.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	key, secret, err := h.apiKeyService.CreateKey(userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "API_KEY_INVALID_SCOPE":
+			response.SendError(c, http.StatusBadRequest, "INVALID_SCOPE", "Unknown scope requested", []response.ErrorDetail{{Field: "scopes", Message: "allowed scopes are " + strings.Join(domain.APIKeyScopes, ", ")}})
+		case "API_KEY_INVALID_EXPIRY":
+			response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "expires_at must be in the future", nil)
+		case "API_KEY_LIMIT_REACHED":
+			response.SendError(c, http.StatusConflict, "API_KEY_LIMIT_REACHED", "Maximum number of API keys reached; revoke an unused key first", nil)
+		default:
+			response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to create API key", nil)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIKeySuccessResponse{
+		Success:   true,
+		Message:   "API key created successfully",
+		Data:      response.CreatedAPIKeyResponse{APIKeyResponse: response.ToAPIKeyResponse(key), Key: secret},
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetAPIKeys godoc
+// @Summary List API keys
+// @Description Retrieves the authenticated user's API keys that have not been revoked. Secrets are never returned; keys are identified by their prefix.
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} response.APIKeyListSuccessResponse
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Failure 403 {object} response.APIErrorResponse "Requires a bearer token"
+// @Router /profile/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	keys, err := h.apiKeyService.ListKeys(userID)
+	if err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve API keys", nil)
+		return
+	}
+
+	data := make([]response.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		data = append(data, response.ToAPIKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, response.APIKeyListSuccessResponse{
+		Success:   true,
+		Data:      data,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revokes an API key. Requests using it are rejected immediately; other keys keep working.
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce  json
+// @Param    key_id path string true "API Key ID" format(uuid)
+// @Success 200 {object} response.SuccessMessageResponse "API key revoked successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "API key not found"
+// @Router /profile/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("keyID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid API key ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.apiKeyService.RevokeKey(keyID, userID); err != nil {
+		switch err.Error() {
+		case "API_KEY_NOT_FOUND":
+			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "API key not found", nil)
+		case "API_KEY_FORBIDDEN":
+			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to revoke this API key", nil)
+		default:
+			response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to revoke API key", nil)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessMessageResponse{
+		Success:   true,
+		Message:   "API key revoked successfully",
+		Timestamp: time.Now().UTC(),
+	})
+}
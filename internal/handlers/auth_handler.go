@@ -9,6 +9,7 @@ import (
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -76,7 +77,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	loginResult, err := h.authService.Login(req)
+	loginResult, err := h.authService.Login(req, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
 		if err.Error() == "AUTH_INVALID_CREDENTIALS" {
 			response.SendError(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid email or password", nil)
@@ -100,7 +101,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Generates a new access token using a refresh token.
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes the session. Refreshes that race each other within a few seconds all receive the same new refresh token.
 // @Tags Authentication
 // @Accept  json
 // @Produce  json
@@ -116,20 +117,25 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, err := h.authService.RefreshToken(req.RefreshToken)
+	result, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
+		if err.Error() == "AUTH_REFRESH_TOKEN_REUSED" {
+			response.SendError(c, http.StatusUnauthorized, "REFRESH_TOKEN_REUSED", "Refresh token was already used; the session has been revoked", nil)
+			return
+		}
 		response.SendError(c, http.StatusUnauthorized, "INVALID_REFRESH_TOKEN", "Invalid or expired refresh token", nil)
 		return
 	}
 
-	expiresIn, _ := time.ParseDuration(h.cfg.JWT.ExpiresIn)
+	expiresIn := h.cfg.JWT.AccessTTL()
 
 	c.JSON(http.StatusOK, response.RefreshTokenSuccessResponse{
 		Success: true,
 		Data: response.RefreshTokenResponse{
-			AccessToken: newAccessToken,
-			ExpiresIn:   int64(expiresIn.Seconds()),
-			TokenType:   "Bearer",
+			AccessToken:  result.AccessToken,
+			RefreshToken: result.RefreshToken,
+			ExpiresIn:    int64(expiresIn.Seconds()),
+			TokenType:    "Bearer",
 		},
 		Timestamp: time.Now().UTC(),
 	})
@@ -137,17 +143,50 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 // Logout godoc
 // @Summary Log out a user
-// @Description Invalidates the user's session (client-side).
+// @Description Revokes the current session. Its access and refresh tokens stop working immediately.
 // @Tags Authentication
 // @Security BearerAuth
 // @Produce  json
 // @Success 200 {object} response.SuccessMessageResponse "Logged out successfully"
 // @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Failure 403 {object} response.APIErrorResponse "Requires a bearer token"
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID := c.MustGet("sessionID").(uuid.UUID)
+
+	if err := h.authService.Logout(sessionID); err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to log out", nil)
+		return
+	}
+
 	c.JSON(http.StatusOK, response.SuccessMessageResponse{
 		Success:   true,
 		Message:   "Logged out successfully",
 		Timestamp: time.Now().UTC(),
 	})
 }
+
+// LogoutAll godoc
+// @Summary Log out everywhere
+// @Description Revokes every session of the current user on all devices, including the current one.
+// @Tags Authentication
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} response.SuccessMessageResponse "Logged out of all sessions"
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Failure 403 {object} response.APIErrorResponse "Requires a bearer token"
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.authService.LogoutAll(userID); err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to log out of all sessions", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessMessageResponse{
+		Success:   true,
+		Message:   "Logged out of all sessions",
+		Timestamp: time.Now().UTC(),
+	})
+}
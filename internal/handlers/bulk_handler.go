@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead leaves room for the form fields and part headers around
+// the uploaded file when capping the request body.
+const multipartOverhead = 1 << 20
+
+type BulkHandler struct {
+	bulkService services.BulkService
+	cfg         configs.Config
+}
+
+func NewBulkHandler(bulkService services.BulkService, cfg configs.Config) *BulkHandler {
+	return &BulkHandler{bulkService: bulkService, cfg: cfg}
+}
+
+// SubmitURLOperation godoc
+// @Summary Submit a bulk URL operation
+// @Description Uploads a CSV or JSON file of links to create, update, deactivate or delete. The file is processed in the background; poll the returned operation for progress. CSV files need a header row using the same names as the JSON fields: original_url, custom_alias, domain, title, description, password, expires_at for create; id or short_code (plus optional domain) to identify links for the other operations, with title, description, expires_at and is_active for update.
+// @Tags Bulk Operations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   multipart/form-data
+// @Produce  json
+// @Param    file formData file true "CSV or JSON file"
+// @Param    operation formData string true "Operation to apply to every row" Enums(create, update, deactivate, delete)
+// @Param    format formData string false "File format; defaults to the file extension" Enums(csv, json)
+// @Success 202 {object} response.BulkOperationSuccessResponse "Bulk operation accepted"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 413 {object} response.APIErrorResponse "File too large"
+// @Router /bulk/urls [post]
+func (h *BulkHandler) SubmitURLOperation(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.Bulk.MaxFileSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.SendError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", fmt.Sprintf("File must not exceed %d bytes", h.cfg.Bulk.MaxFileSize), nil)
+			return
+		}
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "A file upload is required", nil)
+		return
+	}
+
+	operation := strings.ToLower(c.PostForm("operation"))
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(fileHeader.Filename), "."))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Uploaded file could not be read", nil)
+		return
+	}
+	defer file.Close()
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	op, err := h.bulkService.SubmitURLOperation(userID, operation, format, file)
+	if err != nil {
+		var fileErr *services.BulkFileError
+		switch {
+		case errors.As(err, &fileErr):
+			response.SendError(c, http.StatusBadRequest, "INVALID_FILE", "Uploaded file could not be parsed", []response.ErrorDetail{{Field: "file", Message: fileErr.Reason}})
+		case err.Error() == "BULK_INVALID_OPERATION":
+			response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "operation must be one of create, update, deactivate, delete", nil)
+		case err.Error() == "BULK_UNSUPPORTED_FORMAT":
+			response.SendError(c, http.StatusBadRequest, "UNSUPPORTED_FORMAT", "Only CSV and JSON files are supported", nil)
+		case err.Error() == "BULK_EMPTY_FILE":
+			response.SendError(c, http.StatusBadRequest, "EMPTY_FILE", "Uploaded file contains no rows", nil)
+		case err.Error() == "BULK_TOO_MANY_ROWS":
+			response.SendError(c, http.StatusBadRequest, "TOO_MANY_ROWS", fmt.Sprintf("Uploaded file must not exceed %d rows", h.cfg.Bulk.MaxRows), nil)
+		case err.Error() == "BULK_FILE_TOO_LARGE":
+			response.SendError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", fmt.Sprintf("File must not exceed %d bytes", h.cfg.Bulk.MaxFileSize), nil)
+		default:
+			response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to submit bulk operation", nil)
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.BulkOperationSuccessResponse{
+		Success:   true,
+		Message:   "Bulk operation accepted",
+		Data:      response.ToBulkOperationResponse(op),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetOperation godoc
+// @Summary Get bulk operation status
+// @Description Retrieves the status and progress of a bulk operation.
+// @Tags Bulk Operations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    operation_id path string true "Bulk Operation ID" format(uuid)
+// @Success 200 {object} response.BulkOperationSuccessResponse
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Bulk operation not found"
+// @Router /bulk/{operation_id} [get]
+func (h *BulkHandler) GetOperation(c *gin.Context) {
+	operationID, err := uuid.Parse(c.Param("operationID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid bulk operation ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	op, err := h.bulkService.GetOperation(operationID, userID)
+	if err != nil {
+		sendBulkLookupError(c, err, "Failed to retrieve bulk operation")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.BulkOperationSuccessResponse{
+		Success:   true,
+		Data:      response.ToBulkOperationResponse(op),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// DownloadResult godoc
+// @Summary Download bulk operation result
+// @Description Downloads a CSV with one line per input row: its status, the link's ID, short code and short URL, or the error code for rows that failed.
+// @Tags Bulk Operations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  text/csv
+// @Param    operation_id path string true "Bulk Operation ID" format(uuid)
+// @Success 200 {file} binary "Result CSV"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Bulk operation not found"
+// @Failure 409 {object} response.APIErrorResponse "Operation still running"
+// @Router /bulk/{operation_id}/result [get]
+func (h *BulkHandler) DownloadResult(c *gin.Context) {
+	operationID, err := uuid.Parse(c.Param("operationID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid bulk operation ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	path, err := h.bulkService.GetResultFile(operationID, userID)
+	if err != nil {
+		if err.Error() == "BULK_RESULT_NOT_READY" {
+			response.SendError(c, http.StatusConflict, "RESULT_NOT_READY", "The bulk operation has not finished yet", nil)
+			return
+		}
+		sendBulkLookupError(c, err, "Failed to retrieve bulk operation result")
+		return
+	}
+
+	c.FileAttachment(path, fmt.Sprintf("bulk_%s_result.csv", operationID))
+}
+
+func sendBulkLookupError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "BULK_NOT_FOUND":
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Bulk operation not found", nil)
+	case "BULK_FORBIDDEN":
+		response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this bulk operation", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CampaignHandler struct {
+	campaignService services.CampaignService
+}
+
+func NewCampaignHandler(campaignService services.CampaignService) *CampaignHandler {
+	return &CampaignHandler{campaignService: campaignService}
+}
+
+// CreateCampaign godoc
+// @Summary Create a campaign
+// @Description Creates a campaign with default UTM parameters. Links created with its campaign_id get the parameters merged into their destination. utm.campaign defaults to the campaign name; source and medium are lowercased. Campaign names are unique per user, ignoring case.
+// @Tags Campaigns
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    campaign body request.CreateCampaignRequest true "Campaign Information"
+// @Success 201 {object} response.CampaignSuccessResponse "Campaign created successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 409 {object} response.APIErrorResponse "Campaign name already used"
+// @Router /campaigns [post]
+func (h *CampaignHandler) CreateCampaign(c *gin.Context) {
+	var req request.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	campaign, err := h.campaignService.CreateCampaign(userID, req)
+	if err != nil {
+		sendCampaignError(c, err, "Failed to create campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.CampaignSuccessResponse{
+		Success:   true,
+		Message:   "Campaign created successfully",
+		Data:      response.ToCampaignResponse(campaign),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetUserCampaigns godoc
+// @Summary List campaigns
+// @Description Retrieves every campaign of the authenticated user, newest first.
+// @Tags Campaigns
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Success 200 {object} response.CampaignListSuccessResponse
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Router /campaigns [get]
+func (h *CampaignHandler) GetUserCampaigns(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	campaigns, err := h.campaignService.GetUserCampaigns(userID)
+	if err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve campaigns", nil)
+		return
+	}
+
+	campaignResponses := make([]response.CampaignResponse, len(campaigns))
+	for i := range campaigns {
+		campaignResponses[i] = response.ToCampaignResponse(&campaigns[i])
+	}
+
+	c.JSON(http.StatusOK, response.CampaignListSuccessResponse{
+		Success:   true,
+		Data:      campaignResponses,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetCampaign godoc
+// @Summary Get a campaign
+// @Description Retrieves a campaign and its UTM parameters.
+// @Tags Campaigns
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    campaign_id path string true "Campaign ID" format(uuid)
+// @Success 200 {object} response.CampaignSuccessResponse
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Campaign not found"
+// @Router /campaigns/{campaign_id} [get]
+func (h *CampaignHandler) GetCampaign(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("campaignID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid campaign ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	campaign, err := h.campaignService.GetCampaign(campaignID, userID)
+	if err != nil {
+		sendCampaignError(c, err, "Failed to retrieve campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.CampaignSuccessResponse{
+		Success:   true,
+		Data:      response.ToCampaignResponse(campaign),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// UpdateCampaign godoc
+// @Summary Update a campaign
+// @Description Renames a campaign or changes its description or UTM parameters. A utm object replaces all of the campaign's parameters. Links already created in the campaign keep the parameters they were created with.
+// @Tags Campaigns
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    campaign_id path string true "Campaign ID" format(uuid)
+// @Param    campaign body request.UpdateCampaignRequest true "Campaign Update Information"
+// @Success 200 {object} response.CampaignSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Campaign not found"
+// @Failure 409 {object} response.APIErrorResponse "Campaign name already used"
+// @Router /campaigns/{campaign_id} [put]
+func (h *CampaignHandler) UpdateCampaign(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("campaignID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid campaign ID format", nil)
+		return
+	}
+
+	var req request.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	campaign, err := h.campaignService.UpdateCampaign(campaignID, userID, req)
+	if err != nil {
+		sendCampaignError(c, err, "Failed to update campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.CampaignSuccessResponse{
+		Success:   true,
+		Message:   "Campaign updated successfully",
+		Data:      response.ToCampaignResponse(campaign),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// DeleteCampaign godoc
+// @Summary Delete a campaign
+// @Description Removes a campaign. Its links are kept, with the UTM parameters already in their destinations, but no longer belong to a campaign.
+// @Tags Campaigns
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    campaign_id path string true "Campaign ID" format(uuid)
+// @Success 200 {object} response.SuccessMessageResponse "Campaign deleted successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Campaign not found"
+// @Router /campaigns/{campaign_id} [delete]
+func (h *CampaignHandler) DeleteCampaign(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("campaignID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid campaign ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.campaignService.DeleteCampaign(campaignID, userID); err != nil {
+		sendCampaignError(c, err, "Failed to delete campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessMessageResponse{
+		Success:   true,
+		Message:   "Campaign deleted successfully",
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+func sendCampaignError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "CAMPAIGN_NOT_FOUND":
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Campaign not found", nil)
+	case "CAMPAIGN_FORBIDDEN":
+		response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to manage this campaign", nil)
+	case "CAMPAIGN_ALREADY_EXISTS":
+		response.SendError(c, http.StatusConflict, "CAMPAIGN_CONFLICT", "You already have a campaign with this name", nil)
+	case "CAMPAIGN_NAME_REQUIRED":
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "name must not be blank", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
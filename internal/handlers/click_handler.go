@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ClickHandler struct {
+	clickService services.ClickService
+}
+
+func NewClickHandler(clickService services.ClickService) *ClickHandler {
+	return &ClickHandler{clickService: clickService}
+}
+
+// GetURLClicks godoc
+// @Summary Get URL click log
+// @Description Lists the individual clicks of a URL, newest first. Pass next_cursor from a response as cursor to get the following page. IP addresses are shown according to the account's privacy setting.
+// @Tags Analytics
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    url_id path string true "URL ID" format(uuid)
+// @Param from query string false "Only clicks at or after this time (RFC 3339)" format(date-time)
+// @Param to query string false "Only clicks before this time (RFC 3339)" format(date-time)
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Clicks per page" default(50) maximum(1000)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param region query []string false "Only clicks from these regions" collectionFormat(multi)
+// @Param city query []string false "Only clicks from these cities" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param browser query []string false "Only clicks from these browsers" collectionFormat(multi)
+// @Param os query []string false "Only clicks from these operating systems" collectionFormat(multi)
+// @Param language query []string false "Only clicks whose preferred language is one of these, e.g. en" collectionFormat(multi)
+// @Param referrer query []string false "Only clicks with these exact referrers" collectionFormat(multi)
+// @Param referrer_domain query []string false "Only clicks referred from these hosts, without www." collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_source query []string false "Only clicks with these utm_source values" collectionFormat(multi)
+// @Param utm_medium query []string false "Only clicks with these utm_medium values" collectionFormat(multi)
+// @Param utm_campaign query []string false "Only clicks with these utm_campaign values" collectionFormat(multi)
+// @Param utm_term query []string false "Only clicks with these utm_term values" collectionFormat(multi)
+// @Param utm_content query []string false "Only clicks with these utm_content values" collectionFormat(multi)
+// @Param variant query []string false "Only clicks sent to these destinations, e.g. geo:US, split:A or default" collectionFormat(multi)
+// @Success 200 {object} response.ClickLogSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "URL not found"
+// @Router /urls/{url_id}/clicks [get]
+func (h *ClickHandler) GetURLClicks(c *gin.Context) {
+	urlID, err := uuid.Parse(c.Param("urlID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid URL ID format", nil)
+		return
+	}
+
+	var req request.ClickLogRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	result, err := h.clickService.GetURLClicks(c.Request.Context(), urlID, userID, req)
+	if err != nil {
+		sendClickError(c, err, "Failed to retrieve clicks")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ClickLogSuccessResponse{
+		Success:   true,
+		Data:      *result,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// ExportURLClicks godoc
+// @Summary Export URL clicks
+// @Description Streams every matching click of a URL, oldest first, as CSV, NDJSON or a JSON array. Accepts the same time and dimension filters as the click log. IP addresses are shown according to the account's privacy setting. In CSV, text cells starting with =, +, - or @ are prefixed with a single quote so spreadsheets do not run them as formulas.
+// @Tags Analytics
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  json
+// @Param    url_id path string true "URL ID" format(uuid)
+// @Param format query string false "Export format" Enums(csv, ndjson, json) default(csv)
+// @Param from query string false "Only clicks at or after this time (RFC 3339)" format(date-time)
+// @Param to query string false "Only clicks before this time (RFC 3339)" format(date-time)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param referrer_domain query []string false "Only clicks referred from these hosts, without www." collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_campaign query []string false "Only clicks with these utm_campaign values" collectionFormat(multi)
+// @Success 200 {file} binary "Click export"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "URL not found"
+// @Router /urls/{url_id}/clicks/export [get]
+func (h *ClickHandler) ExportURLClicks(c *gin.Context) {
+	urlID, err := uuid.Parse(c.Param("urlID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid URL ID format", nil)
+		return
+	}
+
+	var req request.ClickExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	export, err := h.clickService.ExportURLClicks(urlID, userID, req)
+	if err != nil {
+		sendClickError(c, err, "Failed to export clicks")
+		return
+	}
+	streamClickExport(c, export)
+}
+
+// ExportUserClicks godoc
+// @Summary Export account clicks
+// @Description Streams every matching click across all of the user's links, oldest first, as CSV, NDJSON or a JSON array. Each row carries the link's ID and short code. IP addresses are shown according to the account's privacy setting. In CSV, text cells starting with =, +, - or @ are prefixed with a single quote so spreadsheets do not run them as formulas.
+// @Tags Analytics
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  json
+// @Param format query string false "Export format" Enums(csv, ndjson, json) default(csv)
+// @Param from query string false "Only clicks at or after this time (RFC 3339)" format(date-time)
+// @Param to query string false "Only clicks before this time (RFC 3339)" format(date-time)
+// @Param country query []string false "Only clicks from these countries" collectionFormat(multi)
+// @Param device query []string false "Only clicks from these device types" collectionFormat(multi)
+// @Param referrer_domain query []string false "Only clicks referred from these hosts, without www." collectionFormat(multi)
+// @Param referrer_category query []string false "Only clicks from these referrer categories" collectionFormat(multi) Enums(search, social, email, direct, other)
+// @Param utm_campaign query []string false "Only clicks with these utm_campaign values" collectionFormat(multi)
+// @Success 200 {file} binary "Click export"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Router /analytics/clicks/export [get]
+func (h *ClickHandler) ExportUserClicks(c *gin.Context) {
+	var req request.ClickExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	export, err := h.clickService.ExportUserClicks(userID, req)
+	if err != nil {
+		sendClickError(c, err, "Failed to export clicks")
+		return
+	}
+	streamClickExport(c, export)
+}
+
+// streamClickExport writes the export as the response body. Once streaming
+// has started the status line is gone, so a failure can only cut the body
+// short and be logged.
+func streamClickExport(c *gin.Context, export *services.ClickExport) {
+	c.Header("Content-Type", export.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if err := export.Write(c.Request.Context(), c.Writer); err != nil {
+		log.Printf("Click export %s interrupted: %v", export.Filename, err)
+	}
+}
+
+func sendClickError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "URL_NOT_FOUND":
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found", nil)
+	case "URL_FORBIDDEN":
+		response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this URL", nil)
+	case "CLICK_INVALID_CURSOR":
+		response.SendError(c, http.StatusBadRequest, "INVALID_CURSOR", "Cursor is invalid", nil)
+	case "CLICK_INVALID_RANGE":
+		response.SendError(c, http.StatusBadRequest, "INVALID_RANGE", "from must be before to", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DomainHandler struct {
+	domainService services.DomainService
+}
+
+func NewDomainHandler(domainService services.DomainService) *DomainHandler {
+	return &DomainHandler{domainService: domainService}
+}
+
+// AddDomain godoc
+// @Summary Register a custom domain
+// @Description Claims a branded domain and returns the TXT record that must be published to verify ownership. Several accounts may claim a name that nobody has verified yet; the first to verify gets it. Claims that are not verified within 7 days (domains.claimttl) expire.
+// @Tags Domains
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    domain body request.CreateDomainRequest true "Domain Information"
+// @Success 201 {object} response.DomainSuccessResponse "Domain registered successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 409 {object} response.APIErrorResponse "Domain already verified by another account, or already claimed by you"
+// @Router /domains [post]
+func (h *DomainHandler) AddDomain(c *gin.Context) {
+	var req request.CreateDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	newDomain, err := h.domainService.AddDomain(userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "DOMAIN_ALREADY_EXISTS":
+			response.SendError(c, http.StatusConflict, "DOMAIN_CONFLICT", "Domain is already verified by another account, or you have already added it", nil)
+		case "DOMAIN_RESERVED":
+			response.SendError(c, http.StatusBadRequest, "DOMAIN_RESERVED", "This domain is reserved by the service", nil)
+		default:
+			response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to register domain", nil)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.DomainSuccessResponse{
+		Success:   true,
+		Message:   "Domain registered successfully",
+		Data:      response.ToDomainResponse(newDomain, services.DomainVerificationPrefix),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetUserDomains godoc
+// @Summary List custom domains
+// @Description Retrieves every custom domain registered by the authenticated user.
+// @Tags Domains
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Success 200 {object} response.DomainListSuccessResponse
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Router /domains [get]
+func (h *DomainHandler) GetUserDomains(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	domains, err := h.domainService.GetUserDomains(userID)
+	if err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve domains", nil)
+		return
+	}
+
+	domainResponses := make([]response.DomainResponse, len(domains))
+	for i := range domains {
+		domainResponses[i] = response.ToDomainResponse(&domains[i], services.DomainVerificationPrefix)
+	}
+
+	c.JSON(http.StatusOK, response.DomainListSuccessResponse{
+		Success:   true,
+		Data:      domainResponses,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetDomain godoc
+// @Summary Get a custom domain
+// @Description Retrieves a custom domain and its verification record.
+// @Tags Domains
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    domain_id path string true "Domain ID" format(uuid)
+// @Success 200 {object} response.DomainSuccessResponse
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Domain not found"
+// @Router /domains/{domain_id} [get]
+func (h *DomainHandler) GetDomain(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid domain ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	d, err := h.domainService.GetDomain(domainID, userID)
+	if err != nil {
+		sendDomainLookupError(c, err, "Failed to retrieve domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.DomainSuccessResponse{
+		Success:   true,
+		Data:      response.ToDomainResponse(d, services.DomainVerificationPrefix),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// UpdateDomain godoc
+// @Summary Update a custom domain
+// @Description Activates or deactivates a custom domain. Links on an inactive domain stop redirecting.
+// @Tags Domains
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    domain_id path string true "Domain ID" format(uuid)
+// @Param    domain body request.UpdateDomainRequest true "Domain Update Information"
+// @Success 200 {object} response.DomainSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Domain not found"
+// @Router /domains/{domain_id} [put]
+func (h *DomainHandler) UpdateDomain(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid domain ID format", nil)
+		return
+	}
+
+	var req request.UpdateDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	d, err := h.domainService.UpdateDomain(domainID, userID, req)
+	if err != nil {
+		sendDomainLookupError(c, err, "Failed to update domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.DomainSuccessResponse{
+		Success:   true,
+		Message:   "Domain updated successfully",
+		Data:      response.ToDomainResponse(d, services.DomainVerificationPrefix),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// VerifyDomain godoc
+// @Summary Verify a custom domain
+// @Description Checks the domain's verification TXT record and marks the domain as verified when it matches. Expired claims, and names another account has verified in the meantime, cannot be verified.
+// @Tags Domains
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    domain_id path string true "Domain ID" format(uuid)
+// @Success 200 {object} response.DomainSuccessResponse "Domain verified successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Domain not found"
+// @Failure 409 {object} response.APIErrorResponse "Domain already verified by another account"
+// @Failure 410 {object} response.APIErrorResponse "Claim expired"
+// @Failure 422 {object} response.APIErrorResponse "Verification record not found"
+// @Router /domains/{domain_id}/verify [post]
+func (h *DomainHandler) VerifyDomain(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid domain ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	d, err := h.domainService.VerifyDomain(domainID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "DOMAIN_VERIFICATION_FAILED":
+			response.SendError(c, http.StatusUnprocessableEntity, "VERIFICATION_FAILED", "Verification TXT record was not found or does not match", nil)
+		case "DOMAIN_ALREADY_EXISTS":
+			response.SendError(c, http.StatusConflict, "DOMAIN_CONFLICT", "Domain has been verified by another account", nil)
+		case "DOMAIN_CLAIM_EXPIRED":
+			response.SendError(c, http.StatusGone, "CLAIM_EXPIRED", "This claim expired before it was verified; add the domain again for a new token", nil)
+		default:
+			sendDomainLookupError(c, err, "Failed to verify domain")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.DomainSuccessResponse{
+		Success:   true,
+		Message:   "Domain verified successfully",
+		Data:      response.ToDomainResponse(d, services.DomainVerificationPrefix),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// DeleteDomain godoc
+// @Summary Delete a custom domain
+// @Description Removes a custom domain. Domains that still have links cannot be deleted; links on the domain that are in the trash are purged with it.
+// @Tags Domains
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    domain_id path string true "Domain ID" format(uuid)
+// @Success 200 {object} response.SuccessMessageResponse "Domain deleted successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Domain not found"
+// @Failure 409 {object} response.APIErrorResponse "Domain still has links"
+// @Router /domains/{domain_id} [delete]
+func (h *DomainHandler) DeleteDomain(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid domain ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.domainService.DeleteDomain(domainID, userID); err != nil {
+		if err.Error() == "DOMAIN_IN_USE" {
+			response.SendError(c, http.StatusConflict, "DOMAIN_IN_USE", "Domain still has links; move or delete them first", nil)
+			return
+		}
+		sendDomainLookupError(c, err, "Failed to delete domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessMessageResponse{
+		Success:   true,
+		Message:   "Domain deleted successfully",
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+func sendDomainLookupError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "DOMAIN_NOT_FOUND":
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Domain not found", nil)
+	case "DOMAIN_FORBIDDEN":
+		response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to manage this domain", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FolderHandler struct {
+	folderService services.FolderService
+}
+
+func NewFolderHandler(folderService services.FolderService) *FolderHandler {
+	return &FolderHandler{folderService: folderService}
+}
+
+// CreateFolder godoc
+// @Summary Create a folder
+// @Description Creates a folder. Folder names are unique per user, ignoring case.
+// @Tags Folders
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    folder body request.CreateFolderRequest true "Folder Information"
+// @Success 201 {object} response.FolderSuccessResponse "Folder created successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 409 {object} response.APIErrorResponse "Folder name already used"
+// @Router /folders [post]
+func (h *FolderHandler) CreateFolder(c *gin.Context) {
+	var req request.CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	folder, err := h.folderService.CreateFolder(userID, req)
+	if err != nil {
+		sendFolderError(c, err, "Failed to create folder")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FolderSuccessResponse{
+		Success:   true,
+		Message:   "Folder created successfully",
+		Data:      response.ToFolderResponse(folder),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetUserFolders godoc
+// @Summary List folders
+// @Description Retrieves every folder of the authenticated user, sorted by name.
+// @Tags Folders
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Success 200 {object} response.FolderListSuccessResponse
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Router /folders [get]
+func (h *FolderHandler) GetUserFolders(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	folders, err := h.folderService.GetUserFolders(userID)
+	if err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve folders", nil)
+		return
+	}
+
+	folderResponses := make([]response.FolderResponse, len(folders))
+	for i := range folders {
+		folderResponses[i] = response.ToFolderResponse(&folders[i])
+	}
+
+	c.JSON(http.StatusOK, response.FolderListSuccessResponse{
+		Success:   true,
+		Data:      folderResponses,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetFolder godoc
+// @Summary Get a folder
+// @Description Retrieves a folder.
+// @Tags Folders
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    folder_id path string true "Folder ID" format(uuid)
+// @Success 200 {object} response.FolderSuccessResponse
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Folder not found"
+// @Router /folders/{folder_id} [get]
+func (h *FolderHandler) GetFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid folder ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	folder, err := h.folderService.GetFolder(folderID, userID)
+	if err != nil {
+		sendFolderError(c, err, "Failed to retrieve folder")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FolderSuccessResponse{
+		Success:   true,
+		Data:      response.ToFolderResponse(folder),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// UpdateFolder godoc
+// @Summary Update a folder
+// @Description Renames a folder.
+// @Tags Folders
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    folder_id path string true "Folder ID" format(uuid)
+// @Param    folder body request.UpdateFolderRequest true "Folder Update Information"
+// @Success 200 {object} response.FolderSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Folder not found"
+// @Failure 409 {object} response.APIErrorResponse "Folder name already used"
+// @Router /folders/{folder_id} [put]
+func (h *FolderHandler) UpdateFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid folder ID format", nil)
+		return
+	}
+
+	var req request.UpdateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	folder, err := h.folderService.UpdateFolder(folderID, userID, req)
+	if err != nil {
+		sendFolderError(c, err, "Failed to update folder")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FolderSuccessResponse{
+		Success:   true,
+		Message:   "Folder updated successfully",
+		Data:      response.ToFolderResponse(folder),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// DeleteFolder godoc
+// @Summary Delete a folder
+// @Description Removes a folder. Its links are kept outside any folder.
+// @Tags Folders
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    folder_id path string true "Folder ID" format(uuid)
+// @Success 200 {object} response.SuccessMessageResponse "Folder deleted successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Folder not found"
+// @Router /folders/{folder_id} [delete]
+func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid folder ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.folderService.DeleteFolder(folderID, userID); err != nil {
+		sendFolderError(c, err, "Failed to delete folder")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessMessageResponse{
+		Success:   true,
+		Message:   "Folder deleted successfully",
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+func sendFolderError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "FOLDER_NOT_FOUND":
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Folder not found", nil)
+	case "FOLDER_FORBIDDEN":
+		response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to manage this folder", nil)
+	case "FOLDER_ALREADY_EXISTS":
+		response.SendError(c, http.StatusConflict, "FOLDER_CONFLICT", "You already have a folder with this name", nil)
+	case "FOLDER_NAME_REQUIRED":
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "name must not be blank", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/cache"
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsHandler struct {
+	clickTracker services.ClickTracker
+	urlCache     cache.Cache
+}
+
+// NewMetricsHandler reports on the click pipeline and, unless urlCache is
+// nil, on the short-code lookup cache.
+func NewMetricsHandler(clickTracker services.ClickTracker, urlCache cache.Cache) *MetricsHandler {
+	return &MetricsHandler{clickTracker: clickTracker, urlCache: urlCache}
+}
+
+// GetMetrics reports queue depth and backpressure counters for the
+// click-ingestion pipeline, and hit/miss counters of the short-code lookup
+// cache when it is enabled. It is served on the internal listener only.
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	stats := h.clickTracker.Stats()
+
+	var urlCache *response.URLCacheMetrics
+	if h.urlCache != nil {
+		cacheStats := h.urlCache.Stats()
+		urlCache = &response.URLCacheMetrics{
+			Backend:   cacheStats.Backend,
+			Hits:      cacheStats.Hits,
+			Misses:    cacheStats.Misses,
+			Sets:      cacheStats.Sets,
+			Deletes:   cacheStats.Deletes,
+			Evictions: cacheStats.Evictions,
+			Errors:    cacheStats.Errors,
+			Entries:   cacheStats.Entries,
+			Capacity:  cacheStats.Capacity,
+		}
+		if lookups := cacheStats.Hits + cacheStats.Misses; lookups > 0 {
+			urlCache.HitRatio = float64(cacheStats.Hits) / float64(lookups)
+		}
+	}
+
+	c.JSON(http.StatusOK, response.MetricsSuccessResponse{
+		Success: true,
+		Data: response.MetricsResponse{
+			ClickPipeline: response.ClickPipelineMetrics{
+				QueueDepth:    stats.QueueDepth,
+				QueueCapacity: stats.QueueCapacity,
+				Workers:       stats.Workers,
+				Enqueued:      stats.Enqueued,
+				Backpressured: stats.Backpressured,
+				Dropped:       stats.Dropped,
+				Written:       stats.Written,
+				Failed:        stats.Failed,
+				Batches:       stats.Batches,
+			},
+			URLCache: urlCache,
+		},
+		Timestamp: time.Now().UTC(),
+	})
+}
@@ -67,7 +67,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 
 // ChangePassword godoc
 // @Summary Change user password
-// @Description Updates the password of the currently logged-in user.
+// @Description Updates the password of the currently logged-in user and signs out every existing session, including the current one.
 // @Tags Profile
 // @Security BearerAuth
 // @Accept   json
@@ -96,32 +96,34 @@ func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessMessageResponse{
 		Success:   true,
-		Message:   "Password changed successfully",
+		Message:   "Password changed successfully; please log in again",
 		Timestamp: time.Now().UTC(),
 	})
 }
 
-// RegenerateAPIKey godoc
-// @Summary Regenerate API Key
-// @Description Generates a new API key for the currently logged-in user.
+// UpdatePrivacy godoc
+// @Summary Update privacy settings
+// @Description Sets how visitor IP addresses appear in click logs and exports: full, masked to their network (IPv4 /24, IPv6 /48), or hidden.
 // @Tags Profile
 // @Security BearerAuth
+// @Accept   json
 // @Produce  json
-// @Success 200 {object} response.APIKeySuccessResponse
+// @Param    privacy body request.UpdatePrivacyRequest true "Privacy Settings"
+// @Success 200 {object} response.ProfileSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
 // @Failure 401 {object} response.APIErrorResponse "Unauthorized"
-// @Router /profile/api-key/regenerate [post]
-func (h *ProfileHandler) RegenerateAPIKey(c *gin.Context) {
+// @Router /profile/privacy [put]
+func (h *ProfileHandler) UpdatePrivacy(c *gin.Context) {
+	var req request.UpdatePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
 	userID := c.MustGet("userID").(uuid.UUID)
-	newAPIKey, err := h.userService.RegenerateAPIKey(userID)
+	updatedUser, err := h.userService.UpdatePrivacy(userID, req)
 	if err != nil {
-		response.SendError(c, http.StatusInternalServerError, "GENERATION_FAILED", "Failed to regenerate API key", nil)
+		response.SendError(c, http.StatusInternalServerError, "UPDATE_FAILED", "Failed to update privacy settings", nil)
 		return
 	}
-
-	c.JSON(http.StatusOK, response.APIKeySuccessResponse{
-		Success:   true,
-		Message:   "API key regenerated successfully",
-		Data:      response.APIKeyResponse{APIKey: newAPIKey, GeneratedAt: time.Now().UTC()},
-		Timestamp: time.Now().UTC(),
-	})
+	response.SendSuccess(c, http.StatusOK, "Privacy settings updated successfully", response.ToUserResponse(updatedUser))
 }
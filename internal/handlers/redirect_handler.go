@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"html/template"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,64 +31,205 @@ func NewRedirectHandler(redirectService services.RedirectService, cfg configs.Co
 func (h *RedirectHandler) Redirect(c *gin.Context) {
 	shortCode := c.Param("shortCode")
 
-	originalURL, err := h.redirectService.ProcessRedirect(c, shortCode)
+	visitor := services.VisitorInfo{
+		VisitorID: h.visitorID(c),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Referer:   c.Request.Referer(),
+		Language:  c.GetHeader("Accept-Language"),
+		UTM: domain.UTMParams{
+			Source:   c.Query("utm_source"),
+			Medium:   c.Query("utm_medium"),
+			Campaign: c.Query("utm_campaign"),
+			Term:     c.Query("utm_term"),
+			Content:  c.Query("utm_content"),
+		},
+	}
+
+	unlockToken, _ := c.Cookie(h.cfg.Unlock.CookieName)
+	result, err := h.redirectService.ProcessRedirect(c.Request.Host, shortCode, visitor, unlockToken)
 	if err != nil {
+		var unavailable *services.UnavailableError
+		if errors.As(err, &unavailable) {
+			h.renderUnavailable(c, unavailable)
+			return
+		}
 		if err.Error() == "URL_PASSWORD_PROTECTED" {
-			response.SendError(c, http.StatusUnauthorized, "PASSWORD_PROTECTED", "This URL is password protected", nil)
+			if c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON {
+				response.SendError(c, http.StatusUnauthorized, "PASSWORD_PROTECTED", "This URL is password protected", nil)
+				return
+			}
+			h.renderPasswordForm(c, http.StatusUnauthorized, shortCode, "")
 			return
 		}
 		c.HTML(http.StatusNotFound, "404.html", nil)
 		return
 	}
 
-	c.Redirect(http.StatusFound, originalURL)
+	if result.FallbackURL != "" {
+		h.renderAppRedirect(c, result)
+		return
+	}
+	c.Redirect(http.StatusFound, result.URL)
+}
+
+// renderAppRedirect serves the page that opens a custom-scheme deep link
+// and sends visitors without the app to the fallback. html/template replaces
+// URLs with schemes it does not know, so the deep link is passed as trusted
+// once its scheme has been checked. The fallback is opened from script, where
+// no such filtering happens, so a link whose fallback is not http(s), stored
+// before destinations were checked, is not found.
+func (h *RedirectHandler) renderAppRedirect(c *gin.Context, result *services.RedirectResult) {
+	if !utils.IsValidURL(result.FallbackURL) {
+		c.HTML(http.StatusNotFound, "404.html", nil)
+		return
+	}
+	if !utils.IsSafeDeepLink(result.URL) {
+		c.Redirect(http.StatusFound, result.FallbackURL)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.HTML(http.StatusOK, "app_redirect.html", gin.H{
+		"DeepLink":    template.URL(result.URL),
+		"FallbackURL": result.FallbackURL,
+	})
+}
+
+// renderUnavailable serves the page of a link that is not live, saying when
+// it opens next in the time zone of its schedule. Expired links are gone
+// for good; links that are not live yet are not found for now.
+func (h *RedirectHandler) renderUnavailable(c *gin.Context, unavailable *services.UnavailableError) {
+	status, title, message := http.StatusNotFound, "Not available yet", "This link is not available right now."
+	if unavailable.Status.State == domain.ScheduleExpired {
+		status, title, message = http.StatusGone, "Link expired", "This link has expired."
+		if unavailable.Message != nil {
+			message = *unavailable.Message
+		}
+	}
+
+	data := gin.H{"Title": title, "Message": message}
+	if from := unavailable.Status.AvailableFrom; from != nil {
+		data["AvailableFrom"] = from.In(unavailable.Location).Format("Monday, 2 January 2006 at 15:04 MST")
+		data["AvailableFromISO"] = from.UTC().Format(time.RFC3339)
+	}
+	c.Header("Cache-Control", "no-store")
+	c.HTML(status, "unavailable.html", data)
+}
+
+// visitorID returns the first-party visitor cookie, issuing one when the
+// visitor has none. It returns "" in hash mode, where no cookie is ever set.
+func (h *RedirectHandler) visitorID(c *gin.Context) string {
+	if h.cfg.Visitor.Mode != "cookie" {
+		return ""
+	}
+	if id, err := c.Cookie(h.cfg.Visitor.CookieName); err == nil && id != "" {
+		return id
+	}
+
+	id, err := utils.GenerateVisitorID()
+	if err != nil {
+		return ""
+	}
+	maxAge, _ := time.ParseDuration(h.cfg.Visitor.CookieMaxAge)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(h.cfg.Visitor.CookieName, id, int(maxAge.Seconds()), "/", "", h.secureCookies(), true)
+	return id
+}
+
+// secureCookies reports whether cookies should be limited to HTTPS, which
+// is whenever the service is served over it.
+func (h *RedirectHandler) secureCookies() bool {
+	return strings.HasPrefix(h.cfg.Server.BaseURL, "https://")
+}
+
+// renderPasswordForm serves the password prompt of a protected link, with
+// problem explaining why a previous attempt failed.
+func (h *RedirectHandler) renderPasswordForm(c *gin.Context, status int, shortCode, problem string) {
+	c.Header("Cache-Control", "no-store")
+	c.HTML(status, "password.html", gin.H{"ShortCode": shortCode, "Error": problem})
 }
 
 // UnlockURL godoc
 // @Summary Unlock a password-protected URL
-// @Description Verifies the password for a short URL and returns the original URL.
+// @Description Checks the password of a short URL. A correct password sets a signed, HttpOnly cookie scoped to the short link's path, which lets the browser through the redirect, recorded as a click, until expires_at. The password form served by the redirect posts here as a form and is sent back to the short link with 303 See Other, or shown the form again with the error; JSON requests get the short URL to open with the cookie. Attempts are limited per link and client IP, and per link from all IPs together; once a limit is reached the password is not checked until Retry-After has passed.
 // @Tags Redirection
 // @Accept   json
+// @Accept   x-www-form-urlencoded
 // @Produce  json
+// @Produce  html
 // @Param    shortCode path string true "Short Code"
 // @Param    password body request.UnlockURLRequest true "Password"
 // @Success 200 {object} response.UnlockURLSuccessResponse "URL unlocked successfully"
+// @Success 303 "Form unlocked, redirecting to the short link"
 // @Failure 400 {object} response.APIErrorResponse "Validation error"
 // @Failure 401 {object} response.APIErrorResponse "Invalid password"
 // @Failure 404 {object} response.APIErrorResponse "URL not found"
+// @Failure 429 {object} response.APIErrorResponse "Too many password attempts"
 // @Router /{shortCode}/unlock [post]
 func (h *RedirectHandler) UnlockURL(c *gin.Context) {
 	shortCode := c.Param("shortCode")
-	var req request.UnlockURLRequest
+	isForm := c.ContentType() != gin.MIMEJSON
 
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var req request.UnlockURLRequest
+	if err := c.ShouldBind(&req); err != nil {
+		if isForm {
+			h.renderPasswordForm(c, http.StatusBadRequest, shortCode, "Please enter the password.")
+			return
+		}
 		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
 		return
 	}
 
-	result, err := h.redirectService.UnlockURL(shortCode, req.Password)
+	result, err := h.redirectService.UnlockURL(c.Request.Host, shortCode, req.Password, c.ClientIP())
 	if err != nil {
-		if err.Error() == "URL_INVALID_PASSWORD" {
-			response.SendError(c, http.StatusUnauthorized, "INVALID_PASSWORD", "The provided password is incorrect", nil)
-			return
-		}
-		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found or not password protected", nil)
+		h.sendUnlockError(c, err, shortCode, isForm)
 		return
 	}
 
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(h.cfg.Unlock.CookieName, result.Token, int(time.Until(result.ExpiresAt).Seconds()), "/"+result.URL.ShortCode, "", h.secureCookies(), true)
+
+	if isForm {
+		c.Redirect(http.StatusSeeOther, "/"+result.URL.ShortCode)
+		return
+	}
 	c.JSON(http.StatusOK, response.UnlockURLSuccessResponse{
 		Success: true,
 		Data: response.UnlockURLResponse{
-			RedirectURL: result.RedirectURL,
-			AccessToken: result.AccessToken,
+			RedirectURL: utils.BuildShortURL(h.cfg.Server.BaseURL, result.URL.DomainName(), result.URL.ShortCode),
+			ExpiresAt:   result.ExpiresAt.UTC(),
 		},
 		Timestamp: time.Now().UTC(),
 	})
 }
 
+func (h *RedirectHandler) sendUnlockError(c *gin.Context, err error, shortCode string, isForm bool) {
+	var throttled *services.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		if isForm {
+			minutes := int(math.Ceil(throttled.RetryAfter.Minutes()))
+			h.renderPasswordForm(c, http.StatusTooManyRequests, shortCode, fmt.Sprintf("Too many attempts. Please try again in %d minute(s).", minutes))
+			return
+		}
+		response.SendError(c, http.StatusTooManyRequests, "UNLOCK_THROTTLED", "Too many password attempts, please retry later", nil)
+	case err.Error() == "URL_INVALID_PASSWORD":
+		if isForm {
+			h.renderPasswordForm(c, http.StatusUnauthorized, shortCode, "The password is incorrect.")
+			return
+		}
+		response.SendError(c, http.StatusUnauthorized, "INVALID_PASSWORD", "The provided password is incorrect", nil)
+	case isForm:
+		c.HTML(http.StatusNotFound, "404.html", nil)
+	default:
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found or not password protected", nil)
+	}
+}
+
 // GetURLInfo godoc
 // @Summary Get URL info (Preview)
-// @Description Retrieves public information about a short URL before redirecting.
+// @Description Retrieves public information about a short URL before redirecting. schedule.state tells whether the link redirects now: available, scheduled before its starts_at, closed outside its availability windows, or expired; available_from says when a link that is not available opens next. Links that are not available, password-protected links and click-capped links leave out original_url and domain; capped links report remaining_clicks. Deactivated links, and links whose cap is used up and that have no fallback, are not found.
 // @Tags Redirection
 // @Produce  json
 // @Param    shortCode path string true "Short Code"
@@ -91,25 +239,45 @@ func (h *RedirectHandler) UnlockURL(c *gin.Context) {
 func (h *RedirectHandler) GetURLInfo(c *gin.Context) {
 	shortCode := c.Param("shortCode")
 
-	result, err := h.redirectService.GetURLInfo(shortCode)
+	result, err := h.redirectService.GetURLInfo(c.Request.Host, shortCode)
 	if err != nil {
 		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found or has expired", nil)
 		return
 	}
 
-	shortURLString := fmt.Sprintf("%s/%s", h.cfg.Server.BaseURL, result.URL.ShortCode)
+	shortURLString := utils.BuildShortURL(h.cfg.Server.BaseURL, result.URL.DomainName(), result.URL.ShortCode)
+
+	originalURL, domainName := result.URL.OriginalURL, result.Domain
+	if result.URL.MaxClicks != nil || result.URL.IsPasswordProtected() || result.Schedule.State != domain.ScheduleAvailable {
+		originalURL, domainName = "", ""
+	}
+
+	schedule := response.URLScheduleStatusResponse{
+		State:          result.Schedule.State,
+		AvailableFrom:  result.Schedule.AvailableFrom,
+		AvailableUntil: result.Schedule.AvailableUntil,
+	}
+	if result.URL.Schedule != nil {
+		schedule.TimeZone = result.URL.Schedule.TimeZone
+	}
+	if result.Schedule.State == domain.ScheduleExpired {
+		schedule.ExpiredMessage = result.URL.ExpiredMessage
+	}
 
 	c.JSON(http.StatusOK, response.URLInfoSuccessResponse{
 		Success: true,
 		Data: response.URLInfoResponse{
-			OriginalURL: result.URL.OriginalURL,
-			ShortURL:    shortURLString,
-			Title:       result.URL.Title,
-			Description: result.URL.Description,
-			ClickCount:  result.URL.ClickCount,
-			CreatedAt:   result.URL.CreatedAt,
-			IsSafe:      result.IsSafe,
-			Domain:      result.Domain,
+			OriginalURL:         originalURL,
+			ShortURL:            shortURLString,
+			Title:               result.URL.Title,
+			Description:         result.URL.Description,
+			ClickCount:          result.URL.ClickCount,
+			RemainingClicks:     result.URL.RemainingClicks(),
+			CreatedAt:           result.URL.CreatedAt,
+			IsSafe:              result.IsSafe,
+			IsPasswordProtected: result.URL.IsPasswordProtected(),
+			Domain:              domainName,
+			Schedule:            schedule,
 		},
 		Timestamp: time.Now().UTC(),
 	})
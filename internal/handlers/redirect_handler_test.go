@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/HIUNCY/url-shortener-with-analytics/web"
+	"github.com/gin-gonic/gin"
+)
+
+// stubRedirectService sends every visitor to result.
+type stubRedirectService struct {
+	services.RedirectService
+	result *services.RedirectResult
+}
+
+func (s stubRedirectService) ProcessRedirect(host, shortCode string, visitor services.VisitorInfo, unlockToken string) (*services.RedirectResult, error) {
+	return s.result, nil
+}
+
+func serveRedirect(t *testing.T, result *services.RedirectResult) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.SetHTMLTemplate(web.Templates())
+	router.GET("/:shortCode", NewRedirectHandler(stubRedirectService{result: result}, configs.Config{}).Redirect)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app", nil))
+	return w
+}
+
+func TestRedirectOpensAppWithWebFallback(t *testing.T) {
+	w := serveRedirect(t, &services.RedirectResult{URL: "myapp://product/42", FallbackURL: "https://example.com/product/42"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `var fallbackURL = "https://example.com/product/42"`) {
+		t.Errorf("fallback missing from the page:\n%s", w.Body)
+	}
+}
+
+func TestRedirectRefusesScriptFallbacks(t *testing.T) {
+	for _, fallback := range []string{"javascript:alert(document.domain)//x", "data:text/html,<script>alert(1)</script>", "JavaScript:alert(1)"} {
+		for _, deepLink := range []string{"myapp://product/42", "javascript:alert(1)"} {
+			w := serveRedirect(t, &services.RedirectResult{URL: deepLink, FallbackURL: fallback})
+			if w.Code != http.StatusNotFound {
+				t.Errorf("fallback %q, deep link %q: status = %d, want 404", fallback, deepLink, w.Code)
+			}
+			if strings.Contains(strings.ToLower(w.Body.String()), "alert") || w.Header().Get("Location") != "" {
+				t.Errorf("fallback %q, deep link %q: fallback was served", fallback, deepLink)
+			}
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	tagService services.TagService
+}
+
+func NewTagHandler(tagService services.TagService) *TagHandler {
+	return &TagHandler{tagService: tagService}
+}
+
+// CreateTag godoc
+// @Summary Create a tag
+// @Description Creates a tag. Tag names are unique per user, ignoring case.
+// @Tags Tags
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    tag body request.CreateTagRequest true "Tag Information"
+// @Success 201 {object} response.TagSuccessResponse "Tag created successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 409 {object} response.APIErrorResponse "Tag name already used"
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req request.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	tag, err := h.tagService.CreateTag(userID, req)
+	if err != nil {
+		sendTagError(c, err, "Failed to create tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.TagSuccessResponse{
+		Success:   true,
+		Message:   "Tag created successfully",
+		Data:      response.ToTagResponse(tag),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetUserTags godoc
+// @Summary List tags
+// @Description Retrieves every tag of the authenticated user, sorted by name.
+// @Tags Tags
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Success 200 {object} response.TagListSuccessResponse
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Router /tags [get]
+func (h *TagHandler) GetUserTags(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	tags, err := h.tagService.GetUserTags(userID)
+	if err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve tags", nil)
+		return
+	}
+
+	tagResponses := make([]response.TagResponse, len(tags))
+	for i := range tags {
+		tagResponses[i] = response.ToTagResponse(&tags[i])
+	}
+
+	c.JSON(http.StatusOK, response.TagListSuccessResponse{
+		Success:   true,
+		Data:      tagResponses,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// GetTag godoc
+// @Summary Get a tag
+// @Description Retrieves a tag.
+// @Tags Tags
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    tag_id path string true "Tag ID" format(uuid)
+// @Success 200 {object} response.TagSuccessResponse
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Tag not found"
+// @Router /tags/{tag_id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid tag ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	tag, err := h.tagService.GetTag(tagID, userID)
+	if err != nil {
+		sendTagError(c, err, "Failed to retrieve tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.TagSuccessResponse{
+		Success:   true,
+		Data:      response.ToTagResponse(tag),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// UpdateTag godoc
+// @Summary Update a tag
+// @Description Renames a tag. Links keep the tag under its new name.
+// @Tags Tags
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    tag_id path string true "Tag ID" format(uuid)
+// @Param    tag body request.UpdateTagRequest true "Tag Update Information"
+// @Success 200 {object} response.TagSuccessResponse
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Tag not found"
+// @Failure 409 {object} response.APIErrorResponse "Tag name already used"
+// @Router /tags/{tag_id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid tag ID format", nil)
+		return
+	}
+
+	var req request.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	tag, err := h.tagService.UpdateTag(tagID, userID, req)
+	if err != nil {
+		sendTagError(c, err, "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.TagSuccessResponse{
+		Success:   true,
+		Message:   "Tag updated successfully",
+		Data:      response.ToTagResponse(tag),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Removes a tag from every link that has it and deletes it. The links themselves are kept.
+// @Tags Tags
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    tag_id path string true "Tag ID" format(uuid)
+// @Success 200 {object} response.SuccessMessageResponse "Tag deleted successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "Tag not found"
+// @Router /tags/{tag_id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid tag ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.tagService.DeleteTag(tagID, userID); err != nil {
+		sendTagError(c, err, "Failed to delete tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessMessageResponse{
+		Success:   true,
+		Message:   "Tag deleted successfully",
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// BulkTagURLs godoc
+// @Summary Add or remove tags on many links
+// @Description Adds the tags in add to, then removes the tags in remove from, every listed link. Links that are not the user's or are in the trash are skipped; tags a link already has are left alone. Every tag must belong to the user.
+// @Tags URLs
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept   json
+// @Produce  json
+// @Param    tags body request.BulkTagRequest true "Links and tags"
+// @Success 200 {object} response.BulkTagSuccessResponse "Tags updated successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error or unknown tag"
+// @Router /urls/tags [post]
+func (h *TagHandler) BulkTagURLs(c *gin.Context) {
+	var req request.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	result, err := h.tagService.BulkTagURLs(userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "TAG_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "TAG_NOT_FOUND", "One or more tags do not exist", nil)
+		case "TAG_NOTHING_TO_DO":
+			response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "add or remove must list at least one tag", nil)
+		default:
+			response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to update tags", nil)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.BulkTagSuccessResponse{
+		Success:   true,
+		Message:   "Tags updated successfully",
+		Data:      response.BulkTagResponse{Added: result.Added, Removed: result.Removed},
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+func sendTagError(c *gin.Context, err error, fallbackMessage string) {
+	switch err.Error() {
+	case "TAG_NOT_FOUND":
+		response.SendError(c, http.StatusNotFound, "NOT_FOUND", "Tag not found", nil)
+	case "TAG_FORBIDDEN":
+		response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to manage this tag", nil)
+	case "TAG_ALREADY_EXISTS":
+		response.SendError(c, http.StatusConflict, "TAG_CONFLICT", "You already have a tag with this name", nil)
+	case "TAG_NAME_REQUIRED":
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "name must not be blank", nil)
+	default:
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", fallbackMessage, nil)
+	}
+}
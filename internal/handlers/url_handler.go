@@ -2,16 +2,14 @@ package handlers
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
-	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/services"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -28,7 +26,7 @@ func NewURLHandler(urlService services.URLService, cfg configs.Config) *URLHandl
 
 // CreateShortURL godoc
 // @Summary Create a new short URL
-// @Description Creates a new short URL for the authenticated user.
+// @Description Creates a new short URL for the authenticated user. With campaign_id the link joins the campaign and the campaign's UTM parameters are added to original_url; parameters given in utm override the campaign's. Existing utm_* parameters of original_url are replaced, the rest of its query and fragment are kept. folder_id and tag_ids must be the user's own folder and tags. geo_rules send visitors from the given countries or regions to other destinations; they are tried in order, the first match wins, and everyone else goes to original_url. device_rules send iOS and Android visitors to an app-store URL or a deep link and take precedence over geo_rules; custom-scheme deep links are served through a page that tries to open the app and falls back to fallback_url or the web destination. split_destinations spread the remaining visitors over several weighted destinations, e.g. 70/30, each visitor keeping the destination first assigned while the weights stay the same. max_clicks caps the redirects of the link, counted as they happen so concurrent visitors cannot exceed it; once it is reached the link is deactivated, or sends visitors to cap_fallback_url if set. burn_after_reading allows exactly one redirect. Bots such as link previews are never counted and never get the destination of a capped link. starts_at delays the link's launch and schedule limits it to recurring windows in a time zone, such as business hours; outside them visitors see when the link is available. After expires_at visitors are sent to expired_redirect_url, or shown expired_message.
 // @Tags URLs
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -39,6 +37,7 @@ func NewURLHandler(urlService services.URLService, cfg configs.Config) *URLHandl
 // @Failure 400 {object} response.APIErrorResponse "Validation error"
 // @Failure 401 {object} response.APIErrorResponse "Unauthorized"
 // @Failure 409 {object} response.APIErrorResponse "Custom alias already exists"
+// @Failure 429 {object} response.APIErrorResponse "Monthly link quota reached"
 // @Router /urls [post]
 func (h *URLHandler) CreateShortURL(c *gin.Context) {
 	var req request.CreateURLRequest
@@ -50,9 +49,40 @@ func (h *URLHandler) CreateShortURL(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 	result, err := h.urlService.CreateShortURL(userID, req)
 	if err != nil {
-		if err.Error() == "URL_CUSTOM_ALIAS_EXISTS" {
+		switch err.Error() {
+		case "URL_CUSTOM_ALIAS_EXISTS":
 			response.SendError(c, http.StatusConflict, "ALIAS_CONFLICT", "Custom alias already exists", nil)
 			return
+		case "URL_DOMAIN_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "DOMAIN_NOT_FOUND", "Custom domain not found", nil)
+			return
+		case "URL_DOMAIN_NOT_VERIFIED":
+			response.SendError(c, http.StatusBadRequest, "DOMAIN_NOT_VERIFIED", "Custom domain is not verified or is inactive", nil)
+			return
+		case "URL_CAMPAIGN_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "CAMPAIGN_NOT_FOUND", "Campaign not found", nil)
+			return
+		case "URL_FOLDER_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "FOLDER_NOT_FOUND", "Folder not found", nil)
+			return
+		case "URL_TAG_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "TAG_NOT_FOUND", "One or more tags do not exist", nil)
+			return
+		case "URL_INVALID_DEEP_LINK":
+			response.SendError(c, http.StatusBadRequest, "INVALID_DEEP_LINK", "device_rules destination_url must be a web URL or an app link", nil)
+			return
+		case "URL_INVALID_DESTINATION":
+			response.SendError(c, http.StatusBadRequest, "INVALID_DESTINATION", "Destination URLs must be http or https URLs", nil)
+			return
+		case "URL_INVALID_SPLIT":
+			response.SendError(c, http.StatusBadRequest, "INVALID_SPLIT", "split_destinations needs at least two destinations", nil)
+			return
+		case "URL_INVALID_SCHEDULE":
+			response.SendError(c, http.StatusBadRequest, "INVALID_SCHEDULE", "starts_at must be before expires_at", nil)
+			return
+		case "URL_QUOTA_EXCEEDED":
+			response.SendError(c, http.StatusTooManyRequests, "QUOTA_EXCEEDED", "Monthly link quota of your plan has been reached", nil)
+			return
 		}
 		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to create short URL", nil)
 		return
@@ -68,53 +98,76 @@ func (h *URLHandler) CreateShortURL(c *gin.Context) {
 
 // GetUserURLs godoc
 // @Summary Get user's URLs
-// @Description Retrieves a paginated list of URLs for the authenticated user.
+// @Description Retrieves a paginated, filtered list of URLs for the authenticated user. Pages can be requested by number, or by passing the next_cursor of the previous page as cursor, which stays fast and stable for large lists. A cursor is only valid with the sort and order it was issued for. Times are RFC 3339; "from" bounds are inclusive and "to" bounds exclusive.
 // @Tags URLs
 // @Security BearerAuth
 // @Security ApiKeyAuth
 // @Produce  json
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param page query int false "Page number; ignored when cursor is set" default(1) minimum(1)
+// @Param limit query int false "Items per page" default(10) minimum(1) maximum(100)
+// @Param cursor query string false "Opaque cursor from pagination.next_cursor"
 // @Param search query string false "Search query for title or original URL"
-// @Param sort query string false "Sort by field (created_at, click_count, title)" Enums(created_at, click_count, title)
-// @Param order query string false "Sort order (asc, desc)" Enums(asc, desc)
+// @Param sort query string false "Sort field" Enums(created_at, click_count, unique_click_count, title, last_clicked_at) default(created_at)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
+// @Param is_active query bool false "Only active or only inactive links"
+// @Param expired query bool false "Only expired or only unexpired links"
+// @Param password_protected query bool false "Only links with or without a password"
+// @Param created_from query string false "Created at or after" format(date-time)
+// @Param created_to query string false "Created before" format(date-time)
+// @Param clicked_from query string false "Last clicked at or after" format(date-time)
+// @Param clicked_to query string false "Last clicked before" format(date-time)
+// @Param domain query string false "Custom domain name, or 'default' for links on the default domain"
+// @Param campaign_id query string false "Only links in this campaign" format(uuid)
+// @Param tag_id query []string false "Only links with any of these tags" collectionFormat(multi)
+// @Param folder_id query string false "Only links in this folder, or 'none' for links outside every folder"
 // @Success 200 {object} response.URLListSuccessResponse "List of URLs retrieved successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
 // @Failure 401 {object} response.APIErrorResponse "Unauthorized"
 // @Router /urls [get]
 func (h *URLHandler) GetUserURLs(c *gin.Context) {
-	userID := c.MustGet("userID").(uuid.UUID)
-
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
-
-	options := &domain.FindAllOptions{
-		Search: c.Query("search"),
-		SortBy: c.DefaultQuery("sort", "created_at"),
-		Order:  c.DefaultQuery("order", "desc"),
-		Limit:  limit,
-		Offset: offset,
+	var req request.ListURLsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
 	}
 
-	result, err := h.urlService.GetUserURLs(userID, options)
+	userID := c.MustGet("userID").(uuid.UUID)
+	result, err := h.urlService.GetUserURLs(userID, req)
 	if err != nil {
-		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve URLs", nil)
+		switch err.Error() {
+		case "URL_INVALID_CURSOR":
+			response.SendError(c, http.StatusBadRequest, "INVALID_CURSOR", "Cursor is invalid or was issued for a different sort order", nil)
+		case "URL_DOMAIN_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "DOMAIN_NOT_FOUND", "Custom domain not found", nil)
+		case "URL_CAMPAIGN_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "CAMPAIGN_NOT_FOUND", "Campaign not found", nil)
+		case "URL_FOLDER_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "FOLDER_NOT_FOUND", "folder_id must be a folder ID or 'none'", nil)
+		case "URL_TAG_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "TAG_NOT_FOUND", "tag_id must be a tag ID", nil)
+		default:
+			response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve URLs", nil)
+		}
 		return
 	}
 
 	urlResponses := make([]response.URLListItemResponse, len(result.URLs))
 	for i, url := range result.URLs {
-		shortURLString := fmt.Sprintf("%s/%s", h.cfg.Server.BaseURL, url.ShortCode)
+		shortURLString := utils.BuildShortURL(h.cfg.Server.BaseURL, url.DomainName(), url.ShortCode)
 		urlResponses[i] = response.URLListItemResponse{
-			ID:          url.ID,
-			OriginalURL: url.OriginalURL,
-			ShortCode:   url.ShortCode,
-			ShortURL:    shortURLString,
-			Title:       url.Title,
-			ClickCount:  url.ClickCount,
-			IsActive:    url.IsActive,
-			ExpiresAt:   url.ExpiresAt,
-			CreatedAt:   url.CreatedAt,
+			ID:               url.ID,
+			OriginalURL:      url.OriginalURL,
+			ShortCode:        url.ShortCode,
+			ShortURL:         shortURLString,
+			CampaignID:       url.CampaignID,
+			FolderID:         url.FolderID,
+			Tags:             response.ToTagResponses(url.Tags),
+			Title:            url.Title,
+			ClickCount:       url.ClickCount,
+			UniqueClickCount: url.UniqueClickCount,
+			IsActive:         url.IsActive,
+			ExpiresAt:        url.ExpiresAt,
+			CreatedAt:        url.CreatedAt,
 		}
 	}
 
@@ -164,7 +217,7 @@ func (h *URLHandler) GetURLDetails(c *gin.Context) {
 		return
 	}
 
-	shortURLString := fmt.Sprintf("%s/%s", h.cfg.Server.BaseURL, url.ShortCode)
+	shortURLString := utils.BuildShortURL(h.cfg.Server.BaseURL, url.DomainName(), url.ShortCode)
 
 	c.JSON(http.StatusOK, response.URLDetailsSuccessResponse{
 		Success:   true,
@@ -175,7 +228,7 @@ func (h *URLHandler) GetURLDetails(c *gin.Context) {
 
 // UpdateURL godoc
 // @Summary Update a URL
-// @Description Updates the properties of a specific short URL.
+// @Description Updates the properties of a specific short URL. An empty folder_id takes the link out of its folder; tag_ids, geo_rules, device_rules and split_destinations replace all of the link's tags, targeting rules and split destinations, and an empty list removes them. The short code stays the same. max_clicks 0 removes the cap and an empty cap_fallback_url the fallback; a link deactivated by its cap needs is_active true as well as a higher max_clicks. A schedule without windows, an empty expired_redirect_url and an empty expired_message remove them.
 // @Tags URLs
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -208,15 +261,34 @@ func (h *URLHandler) UpdateURL(c *gin.Context) {
 			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found", nil)
 			return
 		}
-		if err.Error() == "URL_FORBIDDEN" {
+		switch err.Error() {
+		case "URL_FORBIDDEN":
 			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to view this URL", nil)
 			return
+		case "URL_FOLDER_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "FOLDER_NOT_FOUND", "Folder not found", nil)
+			return
+		case "URL_TAG_NOT_FOUND":
+			response.SendError(c, http.StatusBadRequest, "TAG_NOT_FOUND", "One or more tags do not exist", nil)
+			return
+		case "URL_INVALID_DEEP_LINK":
+			response.SendError(c, http.StatusBadRequest, "INVALID_DEEP_LINK", "device_rules destination_url must be a web URL or an app link", nil)
+			return
+		case "URL_INVALID_DESTINATION":
+			response.SendError(c, http.StatusBadRequest, "INVALID_DESTINATION", "Destination URLs must be http or https URLs", nil)
+			return
+		case "URL_INVALID_SPLIT":
+			response.SendError(c, http.StatusBadRequest, "INVALID_SPLIT", "split_destinations needs at least two destinations", nil)
+			return
+		case "URL_INVALID_SCHEDULE":
+			response.SendError(c, http.StatusBadRequest, "INVALID_SCHEDULE", "starts_at must be before expires_at", nil)
+			return
 		}
 		response.SendError(c, http.StatusInternalServerError, "UPDATE_FAILED", "Failed to update URL", nil)
 		return
 	}
 
-	shortURLString := fmt.Sprintf("%s/%s", h.cfg.Server.BaseURL, updatedURL.ShortCode)
+	shortURLString := utils.BuildShortURL(h.cfg.Server.BaseURL, updatedURL.DomainName(), updatedURL.ShortCode)
 	c.JSON(http.StatusOK, response.URLDetailsSuccessResponse{
 		Success:   true,
 		Data:      response.ToURLDetailsResponse(updatedURL, shortURLString),
@@ -226,7 +298,7 @@ func (h *URLHandler) UpdateURL(c *gin.Context) {
 
 // DeleteURL godoc
 // @Summary Delete a URL
-// @Description Deletes a specific short URL (soft delete).
+// @Description Moves a short URL to the trash. It stops redirecting at once but keeps its short code and analytics, and can be restored until the retention period ends, after which it is purged permanently.
 // @Tags URLs
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -263,3 +335,98 @@ func (h *URLHandler) DeleteURL(c *gin.Context) {
 		Timestamp: time.Now().UTC(),
 	})
 }
+
+// GetTrash godoc
+// @Summary List deleted URLs
+// @Description Retrieves the authenticated user's deleted URLs, most recently deleted first, with the time each one will be purged.
+// @Tags URLs
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param page query int false "Page number" default(1) minimum(1)
+// @Param limit query int false "Items per page" default(10) minimum(1) maximum(100)
+// @Success 200 {object} response.TrashListSuccessResponse "Deleted URLs retrieved successfully"
+// @Failure 400 {object} response.APIErrorResponse "Validation error"
+// @Failure 401 {object} response.APIErrorResponse "Unauthorized"
+// @Router /urls/trash [get]
+func (h *URLHandler) GetTrash(c *gin.Context) {
+	var req request.ListTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error(), nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	result, err := h.urlService.GetTrash(userID, req)
+	if err != nil {
+		response.SendError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Failed to retrieve deleted URLs", nil)
+		return
+	}
+
+	retention := h.cfg.Trash.RetentionPeriod()
+	items := make([]response.TrashItemResponse, len(result.URLs))
+	for i, url := range result.URLs {
+		items[i] = response.TrashItemResponse{
+			ID:          url.ID,
+			OriginalURL: url.OriginalURL,
+			ShortCode:   url.ShortCode,
+			ShortURL:    utils.BuildShortURL(h.cfg.Server.BaseURL, url.DomainName(), url.ShortCode),
+			Title:       url.Title,
+			ClickCount:  url.ClickCount,
+			CreatedAt:   url.CreatedAt,
+			DeletedAt:   url.DeletedAt.Time,
+			PurgeAt:     url.DeletedAt.Time.Add(retention),
+		}
+	}
+
+	c.JSON(http.StatusOK, response.TrashListSuccessResponse{
+		Success: true,
+		Data: response.TrashListResponse{
+			URLs:       items,
+			Pagination: result.Pagination,
+		},
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// RestoreURL godoc
+// @Summary Restore a deleted URL
+// @Description Takes a URL out of the trash. It redirects again from the same short code, with its analytics intact.
+// @Tags URLs
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce  json
+// @Param    url_id path string true "URL ID" format(uuid)
+// @Success 200 {object} response.URLDetailsSuccessResponse "URL restored successfully"
+// @Failure 403 {object} response.APIErrorResponse "Forbidden"
+// @Failure 404 {object} response.APIErrorResponse "URL not found in the trash"
+// @Router /urls/{url_id}/restore [post]
+func (h *URLHandler) RestoreURL(c *gin.Context) {
+	urlID, err := uuid.Parse(c.Param("urlID"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid URL ID format", nil)
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	url, err := h.urlService.RestoreURL(urlID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendError(c, http.StatusNotFound, "NOT_FOUND", "URL not found in the trash", nil)
+			return
+		}
+		if err.Error() == "URL_FORBIDDEN" {
+			response.SendError(c, http.StatusForbidden, "FORBIDDEN", "You do not have permission to restore this URL", nil)
+			return
+		}
+		response.SendError(c, http.StatusInternalServerError, "RESTORE_FAILED", "Failed to restore URL", nil)
+		return
+	}
+
+	shortURLString := utils.BuildShortURL(h.cfg.Server.BaseURL, url.DomainName(), url.ShortCode)
+	c.JSON(http.StatusOK, response.URLDetailsSuccessResponse{
+		Success:   true,
+		Data:      response.ToURLDetailsResponse(url, shortURLString),
+		Timestamp: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,230 @@
+// Package cached wraps repositories with a read-through cache.
+package cached
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/cache"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// versionSlack is how much longer a short code's version outlives the
+// entries cached under it, to cover lookups still reading the database
+// when the version changed.
+const versionSlack = time.Minute
+
+// urlRepository caches FindByShortCode, the lookup behind every redirect,
+// preview and unlock. All other methods go straight to the wrapped
+// repository. Entries are invalidated whenever a link is stored, updated,
+// deleted or restored through this repository, and when a link reaches its
+// click cap; click counters are not invalidated, so the counts on a cached
+// link may lag by up to the TTL.
+//
+// Entries are keyed by a version of their short code, and invalidating a
+// link gives its short code a new version. A lookup that read the old row
+// while the link was being changed, on any instance sharing the cache, fills
+// the entry of the old version, which nobody reads any more.
+type urlRepository struct {
+	domain.URLRepository
+	cache       cache.Cache
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+// NewURLRepository returns next with a lookup cache in front of it. Unknown
+// short codes are remembered for negativeTTL; zero disables negative caching.
+func NewURLRepository(next domain.URLRepository, c cache.Cache, ttl, negativeTTL time.Duration) domain.URLRepository {
+	return &urlRepository{URLRepository: next, cache: c, ttl: ttl, negativeTTL: negativeTTL}
+}
+
+// cachedURL is what the cache keeps of a link: what redirects, previews and
+// unlocks read. The password hash is replaced by its fingerprint, so a
+// shared cache holds nothing a password could be checked against.
+type cachedURL struct {
+	ID                  uuid.UUID                 `json:"id"`
+	UserID              *uuid.UUID                `json:"user_id,omitempty"`
+	OriginalURL         string                    `json:"original_url"`
+	ShortCode           string                    `json:"short_code"`
+	DomainID            *uuid.UUID                `json:"domain_id,omitempty"`
+	DomainName          string                    `json:"domain_name,omitempty"`
+	CampaignID          *uuid.UUID                `json:"campaign_id,omitempty"`
+	GeoRules            []domain.GeoRule          `json:"geo_rules,omitempty"`
+	DeviceRules         []domain.DeviceRule       `json:"device_rules,omitempty"`
+	SplitDestinations   []domain.SplitDestination `json:"split_destinations,omitempty"`
+	Title               *string                   `json:"title,omitempty"`
+	Description         *string                   `json:"description,omitempty"`
+	PasswordFingerprint string                    `json:"password_fingerprint,omitempty"`
+	IsActive            bool                      `json:"is_active"`
+	ClickCount          int                       `json:"click_count"`
+	StartsAt            *time.Time                `json:"starts_at,omitempty"`
+	ExpiresAt           *time.Time                `json:"expires_at,omitempty"`
+	Schedule            *domain.Schedule          `json:"schedule,omitempty"`
+	ExpiredRedirectURL  *string                   `json:"expired_redirect_url,omitempty"`
+	ExpiredMessage      *string                   `json:"expired_message,omitempty"`
+	MaxClicks           *int                      `json:"max_clicks,omitempty"`
+	RedirectCount       int                       `json:"redirect_count"`
+	CapFallbackURL      *string                   `json:"cap_fallback_url,omitempty"`
+	CreatedAt           time.Time                 `json:"created_at"`
+}
+
+func newCachedURL(url *domain.URL) cachedURL {
+	return cachedURL{
+		ID:                  url.ID,
+		UserID:              url.UserID,
+		OriginalURL:         url.OriginalURL,
+		ShortCode:           url.ShortCode,
+		DomainID:            url.DomainID,
+		DomainName:          url.DomainName(),
+		CampaignID:          url.CampaignID,
+		GeoRules:            url.GeoRules,
+		DeviceRules:         url.DeviceRules,
+		SplitDestinations:   url.SplitDestinations,
+		Title:               url.Title,
+		Description:         url.Description,
+		PasswordFingerprint: url.PasswordVersion(),
+		IsActive:            url.IsActive,
+		ClickCount:          url.ClickCount,
+		StartsAt:            url.StartsAt,
+		ExpiresAt:           url.ExpiresAt,
+		Schedule:            url.Schedule,
+		ExpiredRedirectURL:  url.ExpiredRedirectURL,
+		ExpiredMessage:      url.ExpiredMessage,
+		MaxClicks:           url.MaxClicks,
+		RedirectCount:       url.RedirectCount,
+		CapFallbackURL:      url.CapFallbackURL,
+		CreatedAt:           url.CreatedAt,
+	}
+}
+
+func (c cachedURL) toDomain() *domain.URL {
+	url := &domain.URL{
+		ID:                  c.ID,
+		UserID:              c.UserID,
+		OriginalURL:         c.OriginalURL,
+		ShortCode:           c.ShortCode,
+		DomainID:            c.DomainID,
+		CampaignID:          c.CampaignID,
+		GeoRules:            c.GeoRules,
+		DeviceRules:         c.DeviceRules,
+		SplitDestinations:   c.SplitDestinations,
+		Title:               c.Title,
+		Description:         c.Description,
+		PasswordFingerprint: c.PasswordFingerprint,
+		IsActive:            c.IsActive,
+		ClickCount:          c.ClickCount,
+		StartsAt:            c.StartsAt,
+		ExpiresAt:           c.ExpiresAt,
+		Schedule:            c.Schedule,
+		ExpiredRedirectURL:  c.ExpiredRedirectURL,
+		ExpiredMessage:      c.ExpiredMessage,
+		MaxClicks:           c.MaxClicks,
+		RedirectCount:       c.RedirectCount,
+		CapFallbackURL:      c.CapFallbackURL,
+		CreatedAt:           c.CreatedAt,
+	}
+	if c.DomainID != nil {
+		url.Domain = &domain.Domain{ID: *c.DomainID, DomainName: c.DomainName}
+	}
+	return url
+}
+
+func shortCodeKey(domainID *uuid.UUID, shortCode string) string {
+	if domainID == nil {
+		return "-:" + shortCode
+	}
+	return domainID.String() + ":" + shortCode
+}
+
+func versionKey(domainID *uuid.UUID, shortCode string) string {
+	return "urlv:" + shortCodeKey(domainID, shortCode)
+}
+
+// entryKey returns the key of the short code's entry under its current
+// version. Short codes that were never invalidated, or not for longer than
+// any entry lives, have version "0".
+func (r *urlRepository) entryKey(domainID *uuid.UUID, shortCode string) string {
+	version := "0"
+	if data, ok := r.cache.Get(versionKey(domainID, shortCode)); ok {
+		version = string(data)
+	}
+	return "url:" + version + ":" + shortCodeKey(domainID, shortCode)
+}
+
+func (r *urlRepository) FindByShortCode(domainID *uuid.UUID, shortCode string) (*domain.URL, error) {
+	key := r.entryKey(domainID, shortCode)
+
+	if data, ok := r.cache.Get(key); ok {
+		if len(data) == 0 {
+			return &domain.URL{}, gorm.ErrRecordNotFound
+		}
+		var entry cachedURL
+		if err := json.Unmarshal(data, &entry); err == nil {
+			return entry.toDomain(), nil
+		}
+		r.cache.Delete(key)
+	}
+
+	url, err := r.URLRepository.FindByShortCode(domainID, shortCode)
+	switch {
+	case err == nil:
+		data, marshalErr := json.Marshal(newCachedURL(url))
+		if marshalErr != nil {
+			log.Printf("Could not cache link %s: %v", url.ID, marshalErr)
+			break
+		}
+		r.cache.Set(key, data, r.ttl)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if r.negativeTTL > 0 {
+			r.cache.Set(key, []byte{}, r.negativeTTL)
+		}
+	}
+	return url, err
+}
+
+// invalidate gives the link's short code a new version. The version is kept
+// until every entry cached under an earlier one has expired.
+func (r *urlRepository) invalidate(url *domain.URL) {
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	r.cache.Set(versionKey(url.DomainID, url.ShortCode), []byte(version), max(r.ttl, r.negativeTTL)+versionSlack)
+}
+
+// Store drops a cached "not found" for the new link's short code.
+func (r *urlRepository) Store(url *domain.URL) error {
+	err := r.URLRepository.Store(url)
+	r.invalidate(url)
+	return err
+}
+
+func (r *urlRepository) Update(url *domain.URL) error {
+	err := r.URLRepository.Update(url)
+	r.invalidate(url)
+	return err
+}
+
+func (r *urlRepository) Delete(url *domain.URL) error {
+	err := r.URLRepository.Delete(url)
+	r.invalidate(url)
+	return err
+}
+
+func (r *urlRepository) Restore(url *domain.URL) error {
+	err := r.URLRepository.Restore(url)
+	r.invalidate(url)
+	return err
+}
+
+// ConsumeClick invalidates the link once the cap is reached, so redirects
+// see at once that it was deactivated or now goes to its fallback.
+func (r *urlRepository) ConsumeClick(url *domain.URL) (bool, error) {
+	ok, err := r.URLRepository.ConsumeClick(url)
+	if ok && url.ClickCapReached() {
+		r.invalidate(url)
+	}
+	return ok, err
+}
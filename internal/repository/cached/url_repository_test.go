@@ -0,0 +1,291 @@
+package cached
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/cache"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// storedURLs stands in for the database: it keeps the current row of each
+// link and counts the lookups that reach it.
+type storedURLs struct {
+	domain.URLRepository
+
+	mu      sync.Mutex
+	urls    map[string]domain.URL
+	lookups int
+	// duringLookup, if set, runs after a lookup has read its row and before
+	// it returns, as a change made while the lookup is in flight.
+	duringLookup func()
+}
+
+func newStoredURLs(urls ...domain.URL) *storedURLs {
+	s := &storedURLs{urls: map[string]domain.URL{}}
+	for _, url := range urls {
+		s.urls[url.ShortCode] = url
+	}
+	return s
+}
+
+func (s *storedURLs) FindByShortCode(_ *uuid.UUID, shortCode string) (*domain.URL, error) {
+	s.mu.Lock()
+	s.lookups++
+	url, ok := s.urls[shortCode]
+	hook := s.duringLookup
+	s.duringLookup = nil
+	s.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	if !ok || url.DeletedAt.Valid {
+		return &domain.URL{}, gorm.ErrRecordNotFound
+	}
+	return &url, nil
+}
+
+func (s *storedURLs) save(url *domain.URL) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.urls[url.ShortCode] = *url
+	return nil
+}
+
+func (s *storedURLs) Store(url *domain.URL) error  { return s.save(url) }
+func (s *storedURLs) Update(url *domain.URL) error { return s.save(url) }
+
+func (s *storedURLs) Delete(url *domain.URL) error {
+	url.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return s.save(url)
+}
+
+func (s *storedURLs) Restore(url *domain.URL) error {
+	url.DeletedAt = gorm.DeletedAt{}
+	return s.save(url)
+}
+
+func (s *storedURLs) ConsumeClick(url *domain.URL) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.urls[url.ShortCode]
+	if stored.RedirectCount >= *stored.MaxClicks {
+		return false, nil
+	}
+	stored.RedirectCount++
+	if stored.RedirectCount >= *stored.MaxClicks && stored.CapFallbackURL == nil {
+		stored.IsActive = false
+	}
+	s.urls[url.ShortCode] = stored
+	url.RedirectCount = stored.RedirectCount
+	return true, nil
+}
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type fixture struct {
+	stored *storedURLs
+	cache  cache.Cache
+	clock  *testClock
+	repo   domain.URLRepository
+}
+
+func newFixture(urls ...domain.URL) *fixture {
+	f := &fixture{
+		stored: newStoredURLs(urls...),
+		clock:  &testClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	f.cache = cache.NewMemoryCacheWithClock(100, f.clock.Now)
+	f.repo = NewURLRepository(f.stored, f.cache, 5*time.Minute, 30*time.Second)
+	return f
+}
+
+// lookup finds the link with shortCode and checks whether the lookup was
+// answered from the cache.
+func (f *fixture) lookup(t *testing.T, shortCode string, wantCached bool) (*domain.URL, error) {
+	t.Helper()
+	before := f.stored.lookups
+	url, err := f.repo.FindByShortCode(nil, shortCode)
+	if cached := f.stored.lookups == before; cached != wantCached {
+		t.Fatalf("lookup of %s: served from cache = %v, want %v", shortCode, cached, wantCached)
+	}
+	return url, err
+}
+
+func activeLink(shortCode string) domain.URL {
+	return domain.URL{ID: uuid.New(), ShortCode: shortCode, OriginalURL: "https://example.com/" + shortCode, IsActive: true}
+}
+
+func TestURLRepositoryServesRepeatedLookupsFromCache(t *testing.T) {
+	f := newFixture(activeLink("abc"))
+
+	f.lookup(t, "abc", false)
+	url, err := f.lookup(t, "abc", true)
+	if err != nil || url.OriginalURL != "https://example.com/abc" {
+		t.Fatalf("cached lookup = %+v, %v", url, err)
+	}
+
+	f.clock.advance(5 * time.Minute)
+	f.lookup(t, "abc", false)
+}
+
+func TestURLRepositoryUpdateDeactivatesAtOnce(t *testing.T) {
+	f := newFixture(activeLink("abc"))
+	url, _ := f.lookup(t, "abc", false)
+
+	url.IsActive = false
+	if err := f.repo.Update(url); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := f.lookup(t, "abc", false)
+	if err != nil || url.IsActive {
+		t.Fatalf("lookup after deactivation = active %v, %v", url.IsActive, err)
+	}
+	f.lookup(t, "abc", true)
+}
+
+func TestURLRepositoryUpdateExpiresAtOnce(t *testing.T) {
+	f := newFixture(activeLink("abc"))
+	url, _ := f.lookup(t, "abc", false)
+
+	expired := f.clock.Now().Add(-time.Minute)
+	url.ExpiresAt = &expired
+	if err := f.repo.Update(url); err != nil {
+		t.Fatal(err)
+	}
+
+	url, _ = f.lookup(t, "abc", false)
+	if state := url.ScheduleStatus(f.clock.Now()).State; state != domain.ScheduleExpired {
+		t.Fatalf("schedule state after expiring = %v, want expired", state)
+	}
+}
+
+func TestURLRepositoryDeleteAndRestore(t *testing.T) {
+	f := newFixture(activeLink("abc"))
+	url, _ := f.lookup(t, "abc", false)
+
+	if err := f.repo.Delete(url); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.lookup(t, "abc", false); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("lookup after delete: err = %v", err)
+	}
+	// The "not found" is cached too.
+	if _, err := f.lookup(t, "abc", true); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("cached lookup after delete: err = %v", err)
+	}
+
+	if err := f.repo.Restore(url); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.lookup(t, "abc", false); err != nil {
+		t.Fatalf("lookup after restore: %v", err)
+	}
+}
+
+func TestURLRepositoryStoreDropsCachedNotFound(t *testing.T) {
+	f := newFixture()
+	f.lookup(t, "new", false)
+	f.lookup(t, "new", true)
+
+	link := activeLink("new")
+	if err := f.repo.Store(&link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.lookup(t, "new", false); err != nil {
+		t.Fatalf("lookup after store: %v", err)
+	}
+}
+
+func TestURLRepositoryConsumeClickInvalidatesAtCap(t *testing.T) {
+	link := activeLink("once")
+	maxClicks := 2
+	link.MaxClicks = &maxClicks
+	f := newFixture(link)
+
+	url, _ := f.lookup(t, "once", false)
+	if ok, err := f.repo.ConsumeClick(url); !ok || err != nil {
+		t.Fatalf("first click = %v, %v", ok, err)
+	}
+	// Below the cap the cached copy is kept; ConsumeClick is atomic in the
+	// database, so a lagging RedirectCount cannot overshoot the cap.
+	url, _ = f.lookup(t, "once", true)
+
+	if ok, err := f.repo.ConsumeClick(url); !ok || err != nil {
+		t.Fatalf("last click = %v, %v", ok, err)
+	}
+	url, _ = f.lookup(t, "once", false)
+	if url.IsActive || !url.ClickCapReached() {
+		t.Fatalf("lookup after the last click: active %v, redirects %d", url.IsActive, url.RedirectCount)
+	}
+}
+
+func TestURLRepositoryKeepsLookupRacingAnUpdateOutOfTheCache(t *testing.T) {
+	f := newFixture(activeLink("abc"))
+	// Another instance sharing the cache.
+	other := NewURLRepository(f.stored, f.cache, 5*time.Minute, 30*time.Second)
+
+	// The lookup reads the active row, then the link is deactivated before
+	// the lookup fills the cache with what it read.
+	f.stored.duringLookup = func() {
+		url := activeLink("abc")
+		url.IsActive = false
+		if err := other.Update(&url); err != nil {
+			t.Error(err)
+		}
+	}
+	if url, _ := f.lookup(t, "abc", false); !url.IsActive {
+		t.Fatal("the racing lookup should still see the row it read")
+	}
+
+	url, _ := f.lookup(t, "abc", false)
+	if url.IsActive {
+		t.Fatal("stale row from the racing lookup was served from the cache")
+	}
+}
+
+func TestURLRepositoryCachesNoPasswordHash(t *testing.T) {
+	link := activeLink("secret")
+	hash := "$2a$10$abcdefghijklmnopqrstuuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0"
+	link.PasswordHash = &hash
+	f := newFixture(link)
+
+	f.lookup(t, "secret", false)
+	data, ok := f.cache.Get("url:0:-:secret")
+	if !ok {
+		t.Fatal("link was not cached")
+	}
+	if bytes.Contains(data, []byte(hash)) {
+		t.Fatalf("cache entry holds the password hash: %s", data)
+	}
+
+	url, _ := f.lookup(t, "secret", true)
+	if url.PasswordHash != nil {
+		t.Fatal("cached copy carries a password hash")
+	}
+	if !url.IsPasswordProtected() || url.PasswordVersion() != link.PasswordVersion() {
+		t.Fatalf("cached copy lost the password fingerprint: %+v", url)
+	}
+}
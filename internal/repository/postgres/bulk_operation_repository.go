@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type bulkOperationRepository struct {
+	db *gorm.DB
+}
+
+func NewBulkOperationRepository(db *gorm.DB) domain.BulkOperationRepository {
+	return &bulkOperationRepository{db: db}
+}
+
+func (r *bulkOperationRepository) Store(op *domain.BulkOperation) error {
+	return r.db.Create(op).Error
+}
+
+func (r *bulkOperationRepository) FindByID(id uuid.UUID) (*domain.BulkOperation, error) {
+	var op domain.BulkOperation
+	err := r.db.Where("id = ?", id).First(&op).Error
+	return &op, err
+}
+
+func (r *bulkOperationRepository) Update(op *domain.BulkOperation) error {
+	return r.db.Save(op).Error
+}
+
+func (r *bulkOperationRepository) ClaimNext(node string, staleBefore time.Time) (*domain.BulkOperation, error) {
+	nodeFilter := ""
+	args := []interface{}{domain.BulkStatusProcessing, domain.BulkStatusPending, domain.BulkStatusProcessing, staleBefore}
+	if node != "" {
+		nodeFilter = "AND node_id = ?"
+		args = append(args, node)
+	}
+
+	var op domain.BulkOperation
+	result := r.db.Raw(`
+		UPDATE bulk_operations
+		SET status = ?, started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM bulk_operations
+			WHERE (status = ? OR (status = ? AND updated_at < ?)) `+nodeFilter+`
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		args...,
+	).Scan(&op)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &op, nil
+}
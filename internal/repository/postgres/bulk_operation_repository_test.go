@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"gorm.io/gorm"
+)
+
+func TestBulkOperationRepositoryClaimNextFiltersByNode(t *testing.T) {
+	staleBefore := time.Now().Add(-5 * time.Minute)
+	tests := []struct {
+		name  string
+		node  string
+		query string
+		args  []driver.Value
+	}{
+		{
+			name:  "pinned",
+			node:  "worker-1",
+			query: `WHERE \(status = \$2 OR \(status = \$3 AND updated_at < \$4\)\) AND node_id = \$5`,
+			args:  []driver.Value{domain.BulkStatusProcessing, domain.BulkStatusPending, domain.BulkStatusProcessing, staleBefore, "worker-1"},
+		},
+		{
+			name:  "shared storage",
+			node:  "",
+			query: `WHERE \(status = \$2 OR \(status = \$3 AND updated_at < \$4\)\)\s+ORDER BY`,
+			args:  []driver.Value{domain.BulkStatusProcessing, domain.BulkStatusPending, domain.BulkStatusProcessing, staleBefore},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t, nil)
+			mock.ExpectQuery(tt.query).WithArgs(tt.args...).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			_, err := NewBulkOperationRepository(db).ClaimNext(tt.node, staleBefore)
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Fatalf("ClaimNext error = %v, want gorm.ErrRecordNotFound", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type campaignRepository struct {
+	db *gorm.DB
+}
+
+func NewCampaignRepository(db *gorm.DB) domain.CampaignRepository {
+	return &campaignRepository{db: db}
+}
+
+func (r *campaignRepository) Store(c *domain.Campaign) error {
+	return r.db.Create(c).Error
+}
+
+func (r *campaignRepository) FindByID(id uuid.UUID) (*domain.Campaign, error) {
+	var c domain.Campaign
+	err := r.db.Where("id = ?", id).First(&c).Error
+	return &c, err
+}
+
+func (r *campaignRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*domain.Campaign, error) {
+	var c domain.Campaign
+	err := r.db.Where("user_id = ? AND lower(name) = lower(?)", userID, name).First(&c).Error
+	return &c, err
+}
+
+func (r *campaignRepository) FindAllByUserID(userID uuid.UUID) ([]domain.Campaign, error) {
+	var campaigns []domain.Campaign
+	err := r.db.Where("user_id = ?", userID).Order("created_at desc").Find(&campaigns).Error
+	return campaigns, err
+}
+
+func (r *campaignRepository) Update(c *domain.Campaign) error {
+	return r.db.Save(c).Error
+}
+
+func (r *campaignRepository) Delete(c *domain.Campaign) error {
+	return r.db.Delete(c).Error
+}
@@ -1,13 +1,20 @@
 package postgres
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// clickInsertChunk bounds the rows per INSERT. A click has about 25 columns,
+// so this stays far below Postgres's limit of 65535 bind parameters per
+// statement whatever clicks.batchsize is set to.
+const clickInsertChunk = 500
+
 type clickRepository struct {
 	db *gorm.DB
 }
@@ -20,62 +27,301 @@ func (r *clickRepository) Store(click *domain.Click) error {
 	return r.db.Create(click).Error
 }
 
-func (r *clickRepository) getAggregatedStats(urlID uuid.UUID, since time.Time, limit int, column string) ([]domain.GroupedResult, error) {
-	var results []domain.GroupedResult
+func (r *clickRepository) StoreBatch(clicks []domain.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(clicks, clickInsertChunk).Error
+}
+
+func (r *clickRepository) FindSeenVisitors(keys []domain.VisitorKey, since time.Time) ([]domain.VisitorKey, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	pairs := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		pairs[i] = []interface{}{key.URLID, key.VisitorHash}
+	}
+
+	var seen []domain.VisitorKey
 	err := r.db.Model(&domain.Click{}).
-		Select(column+" as value, COUNT(*) as count").
-		Where("url_id = ? AND clicked_at >= ?", urlID, since).
+		Distinct("url_id", "visitor_hash").
+		Where("clicked_at >= ?", since).
+		Where("(url_id, visitor_hash) IN ?", pairs).
+		Find(&seen).Error
+	return seen, err
+}
+
+// clickDimensionColumns maps each filterable dimension to its column. Only
+// these names are ever spliced into analytics queries.
+var clickDimensionColumns = map[string]string{
+	domain.ClickDimensionCountry:          "clicks.country",
+	domain.ClickDimensionLanguage:         "clicks.language",
+	domain.ClickDimensionRegion:           "clicks.region",
+	domain.ClickDimensionCity:             "clicks.city",
+	domain.ClickDimensionDevice:           "clicks.device_type",
+	domain.ClickDimensionBrowser:          "clicks.browser",
+	domain.ClickDimensionOS:               "clicks.os",
+	domain.ClickDimensionReferrer:         "clicks.referer",
+	domain.ClickDimensionReferrerDomain:   "clicks.referrer_domain",
+	domain.ClickDimensionReferrerCategory: "clicks.referrer_category",
+	domain.ClickDimensionUTMSource:        "clicks.utm_source",
+	domain.ClickDimensionUTMMedium:        "clicks.utm_medium",
+	domain.ClickDimensionUTMCampaign:      "clicks.utm_campaign",
+	domain.ClickDimensionUTMTerm:          "clicks.utm_term",
+	domain.ClickDimensionUTMContent:       "clicks.utm_content",
+	domain.ClickDimensionVariant:          "clicks.variant",
+}
+
+// filterClicks scopes a query on clicks to the ones matching filter.
+// Dimensions are applied in a fixed order so equal filters produce equal SQL.
+func filterClicks(filter domain.ClickFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.URLID != nil {
+			db = db.Where("clicks.url_id = ?", *filter.URLID)
+		}
+		if filter.CampaignID != nil {
+			db = db.Where("clicks.url_id IN (SELECT id FROM urls WHERE campaign_id = ? AND deleted_at IS NULL)", *filter.CampaignID)
+		}
+		if filter.TagID != nil {
+			db = db.Where("clicks.url_id IN (SELECT urls.id FROM urls JOIN url_tags ON url_tags.url_id = urls.id WHERE url_tags.tag_id = ? AND urls.deleted_at IS NULL)", *filter.TagID)
+		}
+		if filter.FolderID != nil {
+			db = db.Where("clicks.url_id IN (SELECT id FROM urls WHERE folder_id = ? AND deleted_at IS NULL)", *filter.FolderID)
+		}
+		if filter.UserID != nil {
+			db = db.Where("clicks.url_id IN (SELECT id FROM urls WHERE user_id = ? AND deleted_at IS NULL)", *filter.UserID)
+		}
+		if !filter.Range.From.IsZero() {
+			db = db.Where("clicks.clicked_at >= ?", filter.Range.From)
+		}
+		if !filter.Range.To.IsZero() {
+			db = db.Where("clicks.clicked_at < ?", filter.Range.To)
+		}
+
+		dimensions := make([]string, 0, len(filter.Dimensions))
+		for dimension := range filter.Dimensions {
+			dimensions = append(dimensions, dimension)
+		}
+		sort.Strings(dimensions)
+		for _, dimension := range dimensions {
+			column, ok := clickDimensionColumns[dimension]
+			if !ok {
+				db.AddError(fmt.Errorf("unknown click dimension %q", dimension))
+				return db
+			}
+			db = db.Where(column+" IN ?", filter.Dimensions[dimension])
+		}
+		return db
+	}
+}
+
+func (r *clickRepository) clicks(ctx context.Context, filter domain.ClickFilter) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&domain.Click{}).Scopes(filterClicks(filter))
+}
+
+func (r *clickRepository) getAggregatedStats(ctx context.Context, filter domain.ClickFilter, limit int, dimension string) ([]domain.GroupedResult, error) {
+	column, ok := clickDimensionColumns[dimension]
+	if !ok {
+		return nil, fmt.Errorf("unknown click dimension %q", dimension)
+	}
+	var results []domain.GroupedResult
+	err := r.clicks(ctx, filter).
+		Select(column + " as value, COUNT(*) as count, COUNT(*) FILTER (WHERE is_unique) as unique_count").
 		Where(column + " IS NOT NULL AND " + column + " != ''").
-		Group(column).
+		Group("value").
 		Order("count DESC").
 		Limit(limit).
 		Find(&results).Error
 	return results, err
 }
 
-func (r *clickRepository) GetTotalClicks(urlID uuid.UUID, since time.Time) (int64, error) {
+func (r *clickRepository) GetTotalClicks(ctx context.Context, filter domain.ClickFilter) (int64, error) {
+	var total int64
+	err := r.clicks(ctx, filter).Count(&total).Error
+	return total, err
+}
+
+func (r *clickRepository) GetUniqueClicks(ctx context.Context, filter domain.ClickFilter) (int64, error) {
 	var total int64
-	err := r.db.Model(&domain.Click{}).Where("url_id = ? AND clicked_at >= ?", urlID, since).Count(&total).Error
+	err := r.clicks(ctx, filter).Where("is_unique").Count(&total).Error
 	return total, err
 }
 
-func (r *clickRepository) GetTopReferrer(urlID uuid.UUID, since time.Time) (string, error) {
-	var result domain.GroupedResult
-	err := r.db.Model(&domain.Click{}).Select("referer as value, COUNT(*) as count").
-		Where("url_id = ? AND clicked_at >= ? AND referer IS NOT NULL AND referer != ''", urlID, since).
-		Group("referer").Order("count DESC").First(&result).Error
-	return result.Value, err
+func (r *clickRepository) GetTopReferrer(ctx context.Context, filter domain.ClickFilter) (string, error) {
+	return r.topValue(ctx, filter, domain.ClickDimensionReferrerDomain)
+}
+
+func (r *clickRepository) GetTopCountry(ctx context.Context, filter domain.ClickFilter) (string, error) {
+	return r.topValue(ctx, filter, domain.ClickDimensionCountry)
+}
+
+func (r *clickRepository) topValue(ctx context.Context, filter domain.ClickFilter, dimension string) (string, error) {
+	results, err := r.getAggregatedStats(ctx, filter, 1, dimension)
+	if err != nil || len(results) == 0 {
+		return "", err
+	}
+	return results[0].Value, nil
 }
-func (r *clickRepository) GetTopCountry(urlID uuid.UUID, since time.Time) (string, error) {
-	var result domain.GroupedResult
-	err := r.db.Model(&domain.Click{}).Select("country as value, COUNT(*) as count").
-		Where("url_id = ? AND clicked_at >= ? AND country IS NOT NULL AND country != ''", urlID, since).
-		Group("country").Order("count DESC").First(&result).Error
-	return result.Value, err
+
+// granularityIntervals maps each bucket size to the step of its series.
+// Only these literals ever reach the query.
+var granularityIntervals = map[string]string{
+	domain.GranularityMinute: "1 minute",
+	domain.GranularityHour:   "1 hour",
+	domain.GranularityDay:    "1 day",
+	domain.GranularityWeek:   "1 week",
+	domain.GranularityMonth:  "1 month",
 }
-func (r *clickRepository) GetClicksOverTime(urlID uuid.UUID, since time.Time) ([]domain.TimeSeriesResult, error) {
+
+// clicksOverTimeQuery buckets clicks by wall-clock time in the requested
+// zone, so days and months follow the zone's DST changes, and joins the
+// counts onto a generated series of every bucket in the range so that empty
+// buckets come back as zeros.
+const clicksOverTimeQuery = `
+WITH buckets AS (
+	SELECT generate_series(
+		date_trunc(CAST(@granularity AS text), CAST(@from AS timestamptz) AT TIME ZONE CAST(@tz AS text)),
+		(CAST(@to AS timestamptz) - interval '1 microsecond') AT TIME ZONE CAST(@tz AS text),
+		CAST(@step AS interval)
+	) AS bucket
+), counts AS (@counts)
+SELECT buckets.bucket, COALESCE(counts.count, 0) AS count, COALESCE(counts.unique_count, 0) AS unique_count
+FROM buckets
+LEFT JOIN counts ON counts.bucket = buckets.bucket
+ORDER BY buckets.bucket`
+
+func (r *clickRepository) GetClicksOverTime(ctx context.Context, filter domain.ClickFilter, granularity, timeZone string) ([]domain.TimeSeriesResult, error) {
+	step, ok := granularityIntervals[granularity]
+	if !ok {
+		return nil, fmt.Errorf("unknown granularity %q", granularity)
+	}
+	counts := r.clicks(ctx, filter).
+		Select("date_trunc(CAST(? AS text), clicks.clicked_at AT TIME ZONE CAST(? AS text)) AS bucket, COUNT(*) AS count, COUNT(*) FILTER (WHERE is_unique) AS unique_count", granularity, timeZone).
+		Group("bucket")
+
 	var results []domain.TimeSeriesResult
-	err := r.db.Model(&domain.Click{}).
-		Select("DATE(clicked_at) as date, COUNT(*) as count").
-		Where("url_id = ? AND clicked_at >= ?", urlID, since).
-		Group("DATE(clicked_at)").
-		Order("date ASC").
+	err := r.db.WithContext(ctx).Raw(clicksOverTimeQuery, map[string]interface{}{
+		"from":        filter.Range.From,
+		"to":          filter.Range.To,
+		"granularity": granularity,
+		"step":        step,
+		"tz":          timeZone,
+		"counts":      counts,
+	}).Scan(&results).Error
+	return results, err
+}
+
+func (r *clickRepository) GetTopCountries(ctx context.Context, filter domain.ClickFilter, limit int) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, limit, domain.ClickDimensionCountry)
+}
+func (r *clickRepository) GetTopReferrers(ctx context.Context, filter domain.ClickFilter, limit int) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, limit, domain.ClickDimensionReferrerDomain)
+}
+func (r *clickRepository) GetDeviceStats(ctx context.Context, filter domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, 10, domain.ClickDimensionDevice)
+}
+func (r *clickRepository) GetBrowserStats(ctx context.Context, filter domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, 10, domain.ClickDimensionBrowser)
+}
+func (r *clickRepository) GetOSStats(ctx context.Context, filter domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, 10, domain.ClickDimensionOS)
+}
+
+func (r *clickRepository) GetLanguageStats(ctx context.Context, filter domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, 10, domain.ClickDimensionLanguage)
+}
+
+func (r *clickRepository) GetDimensionStats(ctx context.Context, filter domain.ClickFilter, dimension string, limit int) ([]domain.GroupedResult, error) {
+	return r.getAggregatedStats(ctx, filter, limit, dimension)
+}
+
+// GetTopRegions groups by country as well as region, so regions of the same
+// name in different countries stay apart.
+func (r *clickRepository) GetTopRegions(ctx context.Context, filter domain.ClickFilter, limit int) ([]domain.LocationResult, error) {
+	var results []domain.LocationResult
+	err := r.clicks(ctx, filter).
+		Select("country, region, COUNT(*) as count, COUNT(*) FILTER (WHERE is_unique) as unique_count").
+		Where("region IS NOT NULL AND region != ''").
+		Group("country, region").
+		Order("count DESC").
+		Limit(limit).
 		Find(&results).Error
 	return results, err
 }
 
-func (r *clickRepository) GetTopCountries(urlID uuid.UUID, since time.Time, limit int) ([]domain.GroupedResult, error) {
-	return r.getAggregatedStats(urlID, since, limit, "country")
+func (r *clickRepository) GetTopCities(ctx context.Context, filter domain.ClickFilter, limit int) ([]domain.LocationResult, error) {
+	var results []domain.LocationResult
+	err := r.clicks(ctx, filter).
+		Select("country, region, city, COUNT(*) as count, COUNT(*) FILTER (WHERE is_unique) as unique_count").
+		Where("city IS NOT NULL AND city != ''").
+		Group("country, region, city").
+		Order("count DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
 }
-func (r *clickRepository) GetTopReferrers(urlID uuid.UUID, since time.Time, limit int) ([]domain.GroupedResult, error) {
-	return r.getAggregatedStats(urlID, since, limit, "referer")
+
+func (r *clickRepository) GetTopURLs(ctx context.Context, filter domain.ClickFilter, limit int) ([]domain.TopURLResult, error) {
+	var results []domain.TopURLResult
+	err := r.clicks(ctx, filter).
+		Joins("JOIN urls ON urls.id = clicks.url_id").
+		Select("urls.id as url_id, urls.short_code, urls.title, COUNT(*) as count, COUNT(*) FILTER (WHERE clicks.is_unique) as unique_count").
+		Group("urls.id").
+		Order("count DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
 }
-func (r *clickRepository) GetDeviceStats(urlID uuid.UUID, since time.Time) ([]domain.GroupedResult, error) {
-	return r.getAggregatedStats(urlID, since, 10, "device_type")
+
+func (r *clickRepository) GetLinkGroupStats(ctx context.Context, filter domain.ClickFilter, group string, limit int) ([]domain.LinkGroupResult, error) {
+	joins, ok := linkGroupJoins[group]
+	if !ok {
+		return nil, fmt.Errorf("unknown link group %q", group)
+	}
+	var results []domain.LinkGroupResult
+	err := r.clicks(ctx, filter).
+		Joins("JOIN urls ON urls.id = clicks.url_id " + joins).
+		Select("link_group.id, link_group.name, COUNT(*) as count, COUNT(*) FILTER (WHERE clicks.is_unique) as unique_count").
+		Group("link_group.id, link_group.name").
+		Order("count DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
 }
-func (r *clickRepository) GetBrowserStats(urlID uuid.UUID, since time.Time) ([]domain.GroupedResult, error) {
-	return r.getAggregatedStats(urlID, since, 10, "browser")
+
+func (r *clickRepository) clickLog(ctx context.Context, filter domain.ClickFilter) *gorm.DB {
+	return r.clicks(ctx, filter).
+		Joins("JOIN urls ON urls.id = clicks.url_id").
+		Select("clicks.*, urls.short_code")
 }
-func (r *clickRepository) GetOSStats(urlID uuid.UUID, since time.Time) ([]domain.GroupedResult, error) {
-	return r.getAggregatedStats(urlID, since, 10, "os")
+
+func (r *clickRepository) FindLog(ctx context.Context, filter domain.ClickFilter, after *domain.ClickCursor, limit int) ([]domain.ClickLogEntry, error) {
+	query := r.clickLog(ctx, filter)
+	if after != nil {
+		query = query.Where("(clicks.clicked_at, clicks.id) < (?, ?)", after.ClickedAt, after.ID)
+	}
+
+	var entries []domain.ClickLogEntry
+	err := query.Order("clicks.clicked_at DESC, clicks.id DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+func (r *clickRepository) StreamLog(ctx context.Context, filter domain.ClickFilter, fn func(*domain.ClickLogEntry) error) error {
+	rows, err := r.clickLog(ctx, filter).Order("clicks.clicked_at, clicks.id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.ClickLogEntry
+		if err := r.db.ScanRows(rows, &entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
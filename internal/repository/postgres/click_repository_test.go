@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+var bindParam = regexp.MustCompile(`\$(\d+)`)
+
+func TestClickRepositoryStoreBatchChunksInserts(t *testing.T) {
+	var statements []string
+	db, mock := newMockDB(t, sqlmock.QueryMatcherFunc(func(expected, actual string) error {
+		statements = append(statements, actual)
+		return sqlmock.QueryMatcherRegexp.Match(expected, actual)
+	}))
+
+	clicks := make([]domain.Click, 2*clickInsertChunk+1)
+	urlID := uuid.New()
+	for i := range clicks {
+		clicks[i].URLID = urlID
+	}
+
+	mock.ExpectBegin()
+	for _, rows := range []int{clickInsertChunk, clickInsertChunk, 1} {
+		returned := sqlmock.NewRows([]string{"id"})
+		for i := 0; i < rows; i++ {
+			returned.AddRow(uuid.New())
+		}
+		mock.ExpectQuery(`INSERT INTO "clicks"`).WillReturnRows(returned)
+	}
+	mock.ExpectCommit()
+
+	if err := NewClickRepository(db).StoreBatch(clicks); err != nil {
+		t.Fatalf("StoreBatch: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, statement := range statements {
+		params := 0
+		for _, match := range bindParam.FindAllStringSubmatch(statement, -1) {
+			if n, _ := strconv.Atoi(match[1]); n > params {
+				params = n
+			}
+		}
+		if params > 65535 {
+			t.Errorf("INSERT uses %d bind parameters, over the Postgres limit", params)
+		}
+		if rows := strings.Count(statement, "),(") + 1; rows > clickInsertChunk {
+			t.Errorf("INSERT writes %d rows, want at most %d", rows, clickInsertChunk)
+		}
+	}
+}
+
+func TestClickRepositoryGetClicksOverTimeBindsAWhitelistedInterval(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+	mock.ExpectQuery(`date_trunc\(CAST\(\$\d+ AS text\).*CAST\(\$\d+ AS interval\)`).
+		WithArgs("week", sqlmock.AnyArg(), "UTC", sqlmock.AnyArg(), "UTC", "1 week", "week", "UTC", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count", "unique_count"}))
+
+	filter := domain.ClickFilter{Range: domain.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()}}
+	if _, err := NewClickRepository(db).GetClicksOverTime(context.Background(), filter, domain.GranularityWeek, "UTC"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClickRepositoryGetClicksOverTimeRejectsUnknownGranularity(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+
+	filter := domain.ClickFilter{Range: domain.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()}}
+	if _, err := NewClickRepository(db).GetClicksOverTime(context.Background(), filter, "1 day'; --", "UTC"); err == nil {
+		t.Fatal("unknown granularity was accepted")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestClickRepositoryGetClicksOverTimeOnPostgres runs the time series against
+// a real server, which resolves the parameter types sqlmock cannot check.
+func TestClickRepositoryGetClicksOverTimeOnPostgres(t *testing.T) {
+	db := openTestDatabase(t)
+	if err := db.Exec(`CREATE TEMP TABLE clicks (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		url_id UUID NOT NULL,
+		is_unique BOOLEAN DEFAULT false,
+		clicked_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Exec("DROP TABLE pg_temp.clicks") })
+
+	urlID := uuid.New()
+	for _, clickedAt := range []string{
+		"2025-03-08T23:30:00-05:00", // Saturday night in New York, Sunday in UTC
+		"2025-03-09T01:30:00-05:00",
+		"2025-03-09T03:30:00-04:00", // after the switch to DST
+		"2025-03-10T12:00:00-04:00",
+	} {
+		if err := db.Exec("INSERT INTO clicks (url_id, is_unique, clicked_at) VALUES (?, true, ?)", urlID, clickedAt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewClickRepository(db)
+	series := func(from, to, granularity string) []string {
+		t.Helper()
+		filter := domain.ClickFilter{URLID: &urlID, Range: domain.TimeRange{From: mustParse(t, from), To: mustParse(t, to)}}
+		results, err := repo.GetClicksOverTime(context.Background(), filter, granularity, "America/New_York")
+		if err != nil {
+			t.Fatalf("%s series: %v", granularity, err)
+		}
+		got := make([]string, len(results))
+		for i, r := range results {
+			got[i] = r.Bucket.Format("2006-01-02 15:04") + "=" + strconv.FormatInt(r.Count, 10)
+		}
+		return got
+	}
+
+	days := series("2025-03-08T00:00:00-05:00", "2025-03-11T00:00:00-04:00", domain.GranularityDay)
+	wantDays := []string{"2025-03-08 00:00=1", "2025-03-09 00:00=2", "2025-03-10 00:00=1"}
+	if strings.Join(days, " ") != strings.Join(wantDays, " ") {
+		t.Errorf("days = %v, want %v", days, wantDays)
+	}
+
+	hours := series("2025-03-09T00:00:00-05:00", "2025-03-09T04:00:00-04:00", domain.GranularityHour)
+	wantHours := []string{"2025-03-09 00:00=0", "2025-03-09 01:00=1", "2025-03-09 02:00=0", "2025-03-09 03:00=1"}
+	if strings.Join(hours, " ") != strings.Join(wantHours, " ") {
+		t.Errorf("hours = %v, want %v", hours, wantHours)
+	}
+}
+
+func mustParse(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestClickRepositoryFiltersByDimensions(t *testing.T) {
+	db, mock := newMockDB(t, sqlmock.QueryMatcherEqual)
+	userID := uuid.New()
+	mock.ExpectQuery(`SELECT count(*) FROM "clicks" WHERE (clicks.url_id IN (SELECT id FROM urls WHERE user_id = $1 AND deleted_at IS NULL)) AND clicks.country IN ($2,$3) AND clicks.referrer_domain IN ($4)`).
+		WithArgs(userID, "ID", "SG", "google.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	filter := domain.ClickFilter{
+		UserID: &userID,
+		Dimensions: map[string][]string{
+			domain.ClickDimensionReferrerDomain: {"google.com"},
+			domain.ClickDimensionCountry:        {"ID", "SG"},
+		},
+	}
+	total, err := NewClickRepository(db).GetTotalClicks(context.Background(), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 7 {
+		t.Errorf("total = %d, want 7", total)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClickRepositoryRejectsUnknownDimensions(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+	urlID := uuid.New()
+	filter := domain.ClickFilter{URLID: &urlID, Dimensions: map[string][]string{"1=1) OR (true": {"x"}}}
+
+	if _, err := NewClickRepository(db).GetUniqueClicks(context.Background(), filter); err == nil {
+		t.Fatal("unknown dimension was queried")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type domainRepository struct {
+	db *gorm.DB
+}
+
+func NewDomainRepository(db *gorm.DB) domain.DomainRepository {
+	return &domainRepository{db: db}
+}
+
+func (r *domainRepository) Store(d *domain.Domain) error {
+	return translateUniqueViolation(r.db.Create(d).Error)
+}
+
+func (r *domainRepository) FindByID(id uuid.UUID) (*domain.Domain, error) {
+	var d domain.Domain
+	err := r.db.Where("id = ?", id).First(&d).Error
+	return &d, err
+}
+
+func (r *domainRepository) FindVerifiedByDomainName(name string) (*domain.Domain, error) {
+	var d domain.Domain
+	err := r.db.Where("domain_name = ? AND is_verified", name).First(&d).Error
+	return &d, err
+}
+
+func (r *domainRepository) FindByUserIDAndDomainName(userID uuid.UUID, name string) (*domain.Domain, error) {
+	var d domain.Domain
+	err := r.db.Where("user_id = ? AND domain_name = ?", userID, name).First(&d).Error
+	return &d, err
+}
+
+func (r *domainRepository) FindAllByUserID(userID uuid.UUID) ([]domain.Domain, error) {
+	var domains []domain.Domain
+	err := r.db.Where("user_id = ?", userID).Order("created_at desc").Find(&domains).Error
+	return domains, err
+}
+
+func (r *domainRepository) Update(d *domain.Domain) error {
+	return translateUniqueViolation(r.db.Save(d).Error)
+}
+
+func (r *domainRepository) Delete(d *domain.Domain) error {
+	return r.db.Delete(d).Error
+}
+
+func (r *domainRepository) DeleteExpiredClaims(cutoff time.Time) (int64, error) {
+	result := r.db.Where("NOT is_verified AND created_at < ?", cutoff).Delete(&domain.Domain{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestDomainRepositoryTranslatesUniqueViolation(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+	violation := &pgconn.PgError{Code: uniqueViolation, ConstraintName: "idx_domains_verified_name"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "domains"`).WillReturnError(violation)
+	mock.ExpectRollback()
+
+	d := &domain.Domain{ID: uuid.New(), UserID: uuid.New(), DomainName: "links.example.com", IsVerified: true}
+	err := NewDomainRepository(db).Update(d)
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Update error = %v, want gorm.ErrDuplicatedKey", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDomainRepositoryFindVerifiedByDomainNameIgnoresPendingClaims(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+	mock.ExpectQuery(`SELECT \* FROM "domains" WHERE domain_name = \$1 AND is_verified`).
+		WithArgs("links.example.com", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	if _, err := NewDomainRepository(db).FindVerifiedByDomainName("links.example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type folderRepository struct {
+	db *gorm.DB
+}
+
+func NewFolderRepository(db *gorm.DB) domain.FolderRepository {
+	return &folderRepository{db: db}
+}
+
+func (r *folderRepository) Store(f *domain.Folder) error {
+	return r.db.Create(f).Error
+}
+
+func (r *folderRepository) FindByID(id uuid.UUID) (*domain.Folder, error) {
+	var f domain.Folder
+	err := r.db.Where("id = ?", id).First(&f).Error
+	return &f, err
+}
+
+func (r *folderRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*domain.Folder, error) {
+	var f domain.Folder
+	err := r.db.Where("user_id = ? AND lower(name) = lower(?)", userID, name).First(&f).Error
+	return &f, err
+}
+
+func (r *folderRepository) FindAllByUserID(userID uuid.UUID) ([]domain.Folder, error) {
+	var folders []domain.Folder
+	err := r.db.Where("user_id = ?", userID).Order("name").Find(&folders).Error
+	return folders, err
+}
+
+func (r *folderRepository) Update(f *domain.Folder) error {
+	return r.db.Save(f).Error
+}
+
+func (r *folderRepository) Delete(f *domain.Folder) error {
+	return r.db.Delete(f).Error
+}
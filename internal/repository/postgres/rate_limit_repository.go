@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"gorm.io/gorm"
+)
+
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+func NewRateLimitRepository(db *gorm.DB) domain.RateLimitRepository {
+	return &rateLimitRepository{db: db}
+}
+
+func (r *rateLimitRepository) Hit(counter *domain.RateLimit, previousWindowStart time.Time) (int, error) {
+	var counts struct {
+		Current  int
+		Previous int
+	}
+	err := r.db.Raw(`
+		WITH hit AS (
+			INSERT INTO rate_limits (limit_key, user_id, api_key, ip_address, endpoint, request_count, window_start)
+			VALUES (?, ?, ?, NULLIF(?, '')::inet, ?, 1, ?)
+			ON CONFLICT (limit_key, window_start) DO UPDATE SET request_count = rate_limits.request_count + 1
+			RETURNING request_count
+		)
+		SELECT
+			(SELECT request_count FROM hit) AS current,
+			COALESCE((SELECT request_count FROM rate_limits WHERE limit_key = ? AND window_start = ?), 0) AS previous`,
+		counter.LimitKey, counter.UserID, counter.APIKey, counter.IPAddress, counter.Endpoint, counter.WindowStart,
+		counter.LimitKey, previousWindowStart,
+	).Scan(&counts).Error
+	if err != nil {
+		return 0, err
+	}
+
+	counter.RequestCount = counts.Current
+	return counts.Previous, nil
+}
+
+func (r *rateLimitRepository) DeleteBefore(cutoff time.Time) error {
+	return r.db.Where("window_start < ?", cutoff).Delete(&domain.RateLimit{}).Error
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestRefreshTokenRepositoryRotateConsumesOnlyUnusedTokens(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+	usedID := uuid.New()
+	at := time.Now()
+
+	// A concurrent rotation already set used_at, so the conditional UPDATE
+	// matches nothing and no successor may be stored.
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "refresh_tokens" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL AND expires_at > \$3`).
+		WithArgs(at, usedID, at).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	next := &domain.RefreshToken{ID: uuid.New(), ParentID: &usedID, ExpiresAt: at.Add(time.Hour)}
+	err := NewRefreshTokenRepository(db).Rotate(usedID, next, at)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Rotate error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
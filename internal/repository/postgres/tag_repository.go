@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) domain.TagRepository {
+	return &tagRepository{db: db}
+}
+
+// urlTag is one row of the url_tags join table.
+type urlTag struct {
+	URLID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (urlTag) TableName() string {
+	return "url_tags"
+}
+
+func (r *tagRepository) Store(t *domain.Tag) error {
+	return r.db.Create(t).Error
+}
+
+func (r *tagRepository) FindByID(id uuid.UUID) (*domain.Tag, error) {
+	var t domain.Tag
+	err := r.db.Where("id = ?", id).First(&t).Error
+	return &t, err
+}
+
+func (r *tagRepository) FindByIDs(userID uuid.UUID, ids []uuid.UUID) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("user_id = ? AND id IN ?", userID, ids).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*domain.Tag, error) {
+	var t domain.Tag
+	err := r.db.Where("user_id = ? AND lower(name) = lower(?)", userID, name).First(&t).Error
+	return &t, err
+}
+
+func (r *tagRepository) FindAllByUserID(userID uuid.UUID) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	err := r.db.Where("user_id = ?", userID).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) Update(t *domain.Tag) error {
+	return r.db.Save(t).Error
+}
+
+func (r *tagRepository) Delete(t *domain.Tag) error {
+	return r.db.Delete(t).Error
+}
+
+func (r *tagRepository) ReplaceURLTags(urlID uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("url_id = ?", urlID).Delete(&urlTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		rows := make([]urlTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			rows[i] = urlTag{URLID: urlID, TagID: tagID}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+}
+
+func (r *tagRepository) AddToURLs(userID uuid.UUID, urlIDs, tagIDs []uuid.UUID) (int64, error) {
+	result := r.db.Exec(
+		`INSERT INTO url_tags (url_id, tag_id)
+		SELECT urls.id, tags.id FROM urls CROSS JOIN tags
+		WHERE urls.id IN ? AND urls.user_id = ? AND urls.deleted_at IS NULL AND tags.id IN ? AND tags.user_id = ?
+		ON CONFLICT DO NOTHING`,
+		urlIDs, userID, tagIDs, userID,
+	)
+	return result.RowsAffected, result.Error
+}
+
+func (r *tagRepository) RemoveFromURLs(userID uuid.UUID, urlIDs, tagIDs []uuid.UUID) (int64, error) {
+	result := r.db.
+		Where("tag_id IN ? AND url_id IN (SELECT id FROM urls WHERE id IN ? AND user_id = ?)", tagIDs, urlIDs, userID).
+		Delete(&urlTag{})
+	return result.RowsAffected, result.Error
+}
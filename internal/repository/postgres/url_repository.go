@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,96 +19,333 @@ func NewURLRepository(db *gorm.DB) domain.URLRepository {
 	return &urlRepository{db: db}
 }
 
+// Store also records the link's tags, but never creates or changes the
+// tags themselves.
 func (r *urlRepository) Store(url *domain.URL) error {
-	return r.db.Create(url).Error
+	return translateUniqueViolation(r.db.Omit("Domain", "Tags.*").Create(url).Error)
 }
 
-func (r *urlRepository) FindByShortCode(shortCode string) (*domain.URL, error) {
+// scopeDomain restricts a query to links on the given custom domain, or to
+// links on the default base URL when domainID is nil.
+func scopeDomain(domainID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if domainID == nil {
+			return db.Where("domain_id IS NULL")
+		}
+		return db.Where("domain_id = ?", *domainID)
+	}
+}
+
+func (r *urlRepository) FindByShortCode(domainID *uuid.UUID, shortCode string) (*domain.URL, error) {
 	var url domain.URL
-	err := r.db.Where("short_code = ?", shortCode).First(&url).Error
+	err := r.db.Preload("Domain").Scopes(scopeDomain(domainID)).Where("short_code = ?", shortCode).First(&url).Error
 	return &url, err
 }
 
-func (r *urlRepository) FindByCustomAlias(customAlias string) (*domain.URL, error) {
+func (r *urlRepository) FindByCustomAlias(domainID *uuid.UUID, customAlias string) (*domain.URL, error) {
 	var url domain.URL
-	err := r.db.Where("custom_alias = ?", customAlias).First(&url).Error
+	err := r.db.Scopes(scopeDomain(domainID)).Where("custom_alias = ?", customAlias).First(&url).Error
 	return &url, err
 }
 
 func (r *urlRepository) FindByID(id uuid.UUID) (*domain.URL, error) {
 	var url domain.URL
-	err := r.db.Where("id = ?", id).First(&url).Error
+	err := r.db.Preload("Domain").Preload("Tags", orderTags).Where("id = ?", id).First(&url).Error
 	return &url, err
 }
 
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
+
+// urlSortColumn is the ORDER BY expression of a sort field and the SQL type
+// cursor values are cast to. Nullable columns are coalesced the same way as
+// domain.URL.SortValue, so keyset comparisons never meet NULL.
+type urlSortColumn struct {
+	expr    string
+	sqlType string
+}
+
+var urlSortColumns = map[string]urlSortColumn{
+	domain.URLSortCreatedAt:        {expr: "created_at", sqlType: "timestamptz"},
+	domain.URLSortClickCount:       {expr: "click_count", sqlType: "bigint"},
+	domain.URLSortUniqueClickCount: {expr: "unique_click_count", sqlType: "bigint"},
+	domain.URLSortTitle:            {expr: "COALESCE(title, '')", sqlType: "text"},
+	domain.URLSortLastClickedAt:    {expr: "COALESCE(last_clicked_at, 'epoch'::timestamptz)", sqlType: "timestamptz"},
+}
+
+// filterURLs applies every filter of options except the cursor position.
+func filterURLs(options *domain.FindAllOptions) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if options.Search != "" {
+			searchQuery := fmt.Sprintf("%%%s%%", strings.ToLower(options.Search))
+			db = db.Where("LOWER(title) LIKE ? OR LOWER(original_url) LIKE ?", searchQuery, searchQuery)
+		}
+		if options.IsActive != nil {
+			db = db.Where("is_active = ?", *options.IsActive)
+		}
+		if options.Expired != nil {
+			if *options.Expired {
+				db = db.Where("expires_at IS NOT NULL AND expires_at <= NOW()")
+			} else {
+				db = db.Where("expires_at IS NULL OR expires_at > NOW()")
+			}
+		}
+		if options.PasswordProtected != nil {
+			if *options.PasswordProtected {
+				db = db.Where("password_hash IS NOT NULL")
+			} else {
+				db = db.Where("password_hash IS NULL")
+			}
+		}
+		if options.CreatedFrom != nil {
+			db = db.Where("created_at >= ?", *options.CreatedFrom)
+		}
+		if options.CreatedTo != nil {
+			db = db.Where("created_at < ?", *options.CreatedTo)
+		}
+		if options.ClickedFrom != nil {
+			db = db.Where("last_clicked_at >= ?", *options.ClickedFrom)
+		}
+		if options.ClickedTo != nil {
+			db = db.Where("last_clicked_at < ?", *options.ClickedTo)
+		}
+		if options.DomainID != nil {
+			db = db.Where("domain_id = ?", *options.DomainID)
+		} else if options.DefaultDomainOnly {
+			db = db.Where("domain_id IS NULL")
+		}
+		if options.CampaignID != nil {
+			db = db.Where("campaign_id = ?", *options.CampaignID)
+		}
+		if len(options.TagIDs) > 0 {
+			db = db.Where("id IN (SELECT url_id FROM url_tags WHERE tag_id IN ?)", options.TagIDs)
+		}
+		if options.FolderID != nil {
+			db = db.Where("folder_id = ?", *options.FolderID)
+		} else if options.NoFolderOnly {
+			db = db.Where("folder_id IS NULL")
+		}
+		return db
+	}
+}
+
 func (r *urlRepository) FindAllByUserID(userID uuid.UUID, options *domain.FindAllOptions) ([]domain.URL, int64, error) {
 	var urls []domain.URL
 	var total int64
 
-	query := r.db.Model(&domain.URL{}).Where("user_id = ?", userID)
-
-	if options.Search != "" {
-		searchQuery := fmt.Sprintf("%%%s%%", strings.ToLower(options.Search))
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(original_url) LIKE ?", searchQuery, searchQuery)
-	}
+	query := r.db.Model(&domain.URL{}).Where("user_id = ?", userID).Scopes(filterURLs(options))
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if options.SortBy != "" && options.Order != "" {
-		query = query.Order(fmt.Sprintf("%s %s", options.SortBy, options.Order))
+	column, ok := urlSortColumns[options.SortBy]
+	if !ok {
+		column = urlSortColumns[domain.URLSortCreatedAt]
+	}
+	direction, comparison := "DESC", "<"
+	if options.Order == "asc" {
+		direction, comparison = "ASC", ">"
+	}
+
+	if options.After != nil {
+		query = query.Where(
+			fmt.Sprintf("(%s, id) %s (CAST(? AS %s), ?)", column.expr, comparison, column.sqlType),
+			options.After.SortValue, options.After.ID,
+		)
 	} else {
-		query = query.Order("created_at desc")
+		query = query.Offset(options.Offset)
 	}
 
-	query = query.Limit(options.Limit).Offset(options.Offset)
+	query = query.Order(fmt.Sprintf("%s %s, id %s", column.expr, direction, direction)).Limit(options.Limit)
 
-	if err := query.Find(&urls).Error; err != nil {
+	if err := query.Preload("Domain").Preload("Tags", orderTags).Find(&urls).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return urls, total, nil
 }
 
+// editableURLColumns are the columns Update writes: the settings an owner
+// can change. Counters and LastClickedAt are kept by redirects and the click
+// flusher while the link is being edited, so they are never written back
+// from a loaded copy.
+var editableURLColumns = []string{
+	"Title", "Description", "StartsAt", "ExpiresAt", "Schedule",
+	"ExpiredRedirectURL", "ExpiredMessage", "IsActive", "MaxClicks",
+	"CapFallbackURL", "FolderID", "GeoRules", "DeviceRules",
+	"SplitDestinations", "UpdatedAt",
+}
+
+// Update saves the link's editable settings, including those cleared to
+// nil. Tags are changed through the TagRepository.
 func (r *urlRepository) Update(url *domain.URL) error {
-	return r.db.Save(url).Error
+	return r.db.Model(url).Select(editableURLColumns).Updates(url).Error
+}
+
+func (r *urlRepository) ConsumeClick(url *domain.URL) (bool, error) {
+	var rows []struct {
+		RedirectCount int
+	}
+	err := r.db.Raw(`
+		UPDATE urls SET
+			redirect_count = redirect_count + 1,
+			is_active = CASE WHEN redirect_count + 1 >= max_clicks AND cap_fallback_url IS NULL THEN false ELSE is_active END
+		WHERE id = ? AND deleted_at IS NULL AND is_active AND redirect_count < max_clicks
+		RETURNING redirect_count`,
+		url.ID,
+	).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return false, err
+	}
+
+	url.RedirectCount = rows[0].RedirectCount
+	return true, nil
 }
 
 func (r *urlRepository) Delete(url *domain.URL) error {
 	return r.db.Delete(url).Error
 }
 
-func (r *urlRepository) IncrementClickCount(urlID uuid.UUID) error {
-	return r.db.Model(&domain.URL{}).Where("id = ?", urlID).Updates(map[string]interface{}{
-		"click_count":     gorm.Expr("click_count + 1"),
-		"last_clicked_at": time.Now(),
-	}).Error
+func (r *urlRepository) IsShortCodeTaken(domainID *uuid.UUID, shortCode string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.URL{}).Scopes(scopeDomain(domainID)).Where("short_code = ?", shortCode).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *urlRepository) FindDeletedByID(id uuid.UUID) (*domain.URL, error) {
+	var url domain.URL
+	err := r.db.Unscoped().Preload("Domain").Where("id = ? AND deleted_at IS NOT NULL", id).First(&url).Error
+	return &url, err
+}
+
+func (r *urlRepository) FindDeletedByUserID(userID uuid.UUID, limit, offset int) ([]domain.URL, int64, error) {
+	var urls []domain.URL
+	var total int64
+
+	query := r.db.Unscoped().Model(&domain.URL{}).Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Domain").Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&urls).Error
+	return urls, total, err
+}
+
+func (r *urlRepository) Restore(url *domain.URL) error {
+	return r.db.Unscoped().Model(url).Update("deleted_at", nil).Error
+}
+
+func (r *urlRepository) PurgeDeletedBefore(cutoff time.Time, limit int) (int64, error) {
+	result := r.db.Exec(
+		"DELETE FROM urls WHERE id IN (SELECT id FROM urls WHERE deleted_at < ? ORDER BY deleted_at LIMIT ?)",
+		cutoff, limit,
+	)
+	return result.RowsAffected, result.Error
 }
 
-func (r *urlRepository) GetDashboardSummary(userID uuid.UUID) (*domain.DashboardSummaryResult, error) {
+func (r *urlRepository) PurgeDeletedByDomainID(domainID uuid.UUID) error {
+	return r.db.Unscoped().Where("domain_id = ? AND deleted_at IS NOT NULL", domainID).Delete(&domain.URL{}).Error
+}
+
+func (r *urlRepository) CountByDomainID(domainID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&domain.URL{}).Where("domain_id = ?", domainID).Count(&total).Error
+	return total, err
+}
+
+func (r *urlRepository) CountByCampaignID(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&domain.URL{}).Where("campaign_id = ?", campaignID).Count(&total).Error
+	return total, err
+}
+
+func (r *urlRepository) CountByTagID(ctx context.Context, tagID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&domain.URL{}).Where("id IN (SELECT url_id FROM url_tags WHERE tag_id = ?)", tagID).Count(&total).Error
+	return total, err
+}
+
+func (r *urlRepository) CountByFolderID(ctx context.Context, folderID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&domain.URL{}).Where("folder_id = ?", folderID).Count(&total).Error
+	return total, err
+}
+
+func (r *urlRepository) CountCreatedByUserSince(userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	// Trashed links still count, or deleting links would refill the quota.
+	err := r.db.Unscoped().Model(&domain.URL{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&count).Error
+	return count, err
+}
+
+func (r *urlRepository) IncrementClickCounts(deltas []domain.ClickCountDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, delta := range deltas {
+			err := tx.Model(&domain.URL{}).Where("id = ?", delta.URLID).Updates(map[string]interface{}{
+				"click_count":        gorm.Expr("click_count + ?", delta.Clicks),
+				"unique_click_count": gorm.Expr("unique_click_count + ?", delta.UniqueClicks),
+				"last_clicked_at":    gorm.Expr("GREATEST(COALESCE(last_clicked_at, ?), ?)", delta.LastClickedAt, delta.LastClickedAt),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *urlRepository) GetDashboardSummary(ctx context.Context, userID uuid.UUID) (*domain.DashboardSummaryResult, error) {
 	var result domain.DashboardSummaryResult
-	err := r.db.Model(&domain.URL{}).
-		Select("COUNT(*) as total_urls, COALESCE(SUM(click_count), 0) as total_clicks, COUNT(CASE WHEN is_active = true AND (expires_at IS NULL OR expires_at > NOW()) THEN 1 END) as active_urls").
+	err := r.db.WithContext(ctx).Model(&domain.URL{}).
+		Select("COUNT(*) as total_urls, COALESCE(SUM(click_count), 0) as total_clicks, COALESCE(SUM(unique_click_count), 0) as total_unique_clicks, COUNT(CASE WHEN is_active = true AND (expires_at IS NULL OR expires_at > NOW()) THEN 1 END) as active_urls").
 		Where("user_id = ?", userID).
 		Scan(&result).Error
 	return &result, err
 }
 
-func (r *urlRepository) GetTopPerformingURLs(userID uuid.UUID, limit int) ([]domain.URL, error) {
+func (r *urlRepository) GetTopPerformingURLs(ctx context.Context, userID uuid.UUID, limit int) ([]domain.URL, error) {
 	var urls []domain.URL
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
 		Order("click_count DESC").
 		Limit(limit).
 		Find(&urls).Error
 	return urls, err
 }
 
-func (r *urlRepository) GetRecentActivity(userID uuid.UUID, limit int) ([]domain.URL, error) {
+func (r *urlRepository) GetRecentActivity(ctx context.Context, userID uuid.UUID, limit int) ([]domain.URL, error) {
 	var urls []domain.URL
-	err := r.db.Where("user_id = ? AND last_clicked_at IS NOT NULL", userID).
+	err := r.db.WithContext(ctx).Where("user_id = ? AND last_clicked_at IS NOT NULL", userID).
 		Order("last_clicked_at DESC").
 		Limit(limit).
 		Find(&urls).Error
 	return urls, err
 }
+
+// linkGroupJoins joins each kind of link group onto urls. The group's table
+// is aliased link_group.
+var linkGroupJoins = map[string]string{
+	domain.LinkGroupTag:    "JOIN url_tags ON url_tags.url_id = urls.id JOIN tags link_group ON link_group.id = url_tags.tag_id",
+	domain.LinkGroupFolder: "JOIN folders link_group ON link_group.id = urls.folder_id",
+}
+
+func (r *urlRepository) GetLinkGroupTotals(ctx context.Context, userID uuid.UUID, group string, limit int) ([]domain.LinkGroupResult, error) {
+	joins, ok := linkGroupJoins[group]
+	if !ok {
+		return nil, fmt.Errorf("unknown link group %q", group)
+	}
+	var results []domain.LinkGroupResult
+	err := r.db.WithContext(ctx).Model(&domain.URL{}).
+		Joins(joins).
+		Select("link_group.id, link_group.name, COALESCE(SUM(urls.click_count), 0) as count, COALESCE(SUM(urls.unique_click_count), 0) as unique_count").
+		Where("urls.user_id = ?", userID).
+		Group("link_group.id, link_group.name").
+		Order("count DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
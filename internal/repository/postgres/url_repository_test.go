@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestURLRepositoryUpdateWritesOnlyEditableColumns(t *testing.T) {
+	db, mock := newMockDB(t, sqlmock.QueryMatcherEqual)
+	url := &domain.URL{ID: uuid.New(), IsActive: true, ClickCount: 40, UniqueClickCount: 12, RedirectCount: 40}
+
+	// MaxClicks was cleared, so max_clicks is written as NULL; the counters
+	// and last_clicked_at are not written at all.
+	args := make([]driver.Value, 16)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[12] = nil
+	args[15] = url.ID
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "urls" SET "folder_id"=$1,"geo_rules"=$2,"device_rules"=$3,"split_destinations"=$4,"title"=$5,"description"=$6,"is_active"=$7,"starts_at"=$8,"expires_at"=$9,"schedule"=$10,"expired_redirect_url"=$11,"expired_message"=$12,"max_clicks"=$13,"cap_fallback_url"=$14,"updated_at"=$15 WHERE "urls"."deleted_at" IS NULL AND "id" = $16`).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewURLRepository(db).Update(url); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestURLRepositoryUpdateKeepsClicksCountedMeanwhileOnPostgres(t *testing.T) {
+	db := openTestDatabase(t)
+	if err := db.Exec(`CREATE TEMP TABLE urls (
+		id uuid PRIMARY KEY,
+		user_id uuid,
+		original_url text NOT NULL,
+		short_code text NOT NULL,
+		custom_alias text,
+		domain_id uuid,
+		campaign_id uuid,
+		folder_id uuid,
+		geo_rules jsonb,
+		device_rules jsonb,
+		split_destinations jsonb,
+		title text,
+		description text,
+		password_hash text,
+		is_active boolean DEFAULT true,
+		click_count integer DEFAULT 0,
+		unique_click_count integer DEFAULT 0,
+		starts_at timestamptz,
+		expires_at timestamptz,
+		schedule jsonb,
+		expired_redirect_url text,
+		expired_message text,
+		max_clicks integer,
+		redirect_count integer DEFAULT 0,
+		cap_fallback_url text,
+		created_at timestamptz,
+		updated_at timestamptz,
+		last_clicked_at timestamptz,
+		deleted_at timestamptz
+	)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	// FindByID preloads the link's tags.
+	for _, ddl := range []string{
+		`CREATE TEMP TABLE tags (id uuid PRIMARY KEY, user_id uuid NOT NULL, name text NOT NULL, created_at timestamptz)`,
+		`CREATE TEMP TABLE url_tags (url_id uuid, tag_id uuid, PRIMARY KEY (url_id, tag_id))`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewURLRepository(db)
+	maxClicks := 100
+	stored := &domain.URL{ID: uuid.New(), OriginalURL: "https://example.com", ShortCode: "edit1", IsActive: true, MaxClicks: &maxClicks}
+	if err := repo.Store(stored); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := repo.FindByID(stored.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Redirects and the click flusher count clicks while the owner edits.
+	clickedAt := time.Now().UTC().Truncate(time.Microsecond)
+	if err := db.Exec(`UPDATE urls SET click_count = click_count + 5, unique_click_count = unique_click_count + 3,
+		redirect_count = redirect_count + 5, last_clicked_at = ? WHERE id = ?`, clickedAt, stored.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	title := "Renamed"
+	loaded.Title = &title
+	loaded.MaxClicks = nil
+	if err := repo.Update(loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.FindByID(stored.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ClickCount != 5 || got.UniqueClickCount != 3 || got.RedirectCount != 5 {
+		t.Errorf("counters = %d/%d/%d, want 5/3/5", got.ClickCount, got.UniqueClickCount, got.RedirectCount)
+	}
+	if got.LastClickedAt == nil || !got.LastClickedAt.Equal(clickedAt) {
+		t.Errorf("LastClickedAt = %v, want %v", got.LastClickedAt, clickedAt)
+	}
+	if got.Title == nil || *got.Title != title {
+		t.Errorf("Title = %v, want %q", got.Title, title)
+	}
+	if got.MaxClicks != nil {
+		t.Errorf("MaxClicks = %d, want cleared", *got.MaxClicks)
+	}
+}
+
+func TestURLRepositoryFindAllByUserIDOrdersOnlyByWhitelistedColumns(t *testing.T) {
+	tests := []struct {
+		sortBy, order string
+		keyset        string
+		orderBy       string
+	}{
+		{domain.URLSortTitle, "asc", `(COALESCE(title, ''), id) > (CAST($2 AS text), $3)`, `ORDER BY COALESCE(title, '') ASC, id ASC`},
+		{domain.URLSortClickCount, "desc", `(click_count, id) < (CAST($2 AS bigint), $3)`, `ORDER BY click_count DESC, id DESC`},
+		{"title; DROP TABLE urls", "asc", `(created_at, id) > (CAST($2 AS timestamptz), $3)`, `ORDER BY created_at ASC, id ASC`},
+	}
+	for _, tt := range tests {
+		db, mock := newMockDB(t, nil)
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "urls"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(regexp.QuoteMeta(`AND ` + tt.keyset + ` ` + tt.orderBy + ` LIMIT $4`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		after := &domain.URLCursor{SortValue: "0", ID: uuid.New()}
+		_, _, err := NewURLRepository(db).FindAllByUserID(uuid.New(), &domain.FindAllOptions{SortBy: tt.sortBy, Order: tt.order, Limit: 5, After: after})
+		if err != nil {
+			t.Errorf("sort %q: %v", tt.sortBy, err)
+			continue
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("sort %q: %v", tt.sortBy, err)
+		}
+	}
+}
+
+func TestURLRepositoryIsShortCodeTakenCountsTrashedLinks(t *testing.T) {
+	db, mock := newMockDB(t, sqlmock.QueryMatcherEqual)
+	mock.ExpectQuery(`SELECT count(*) FROM "urls" WHERE short_code = $1 AND domain_id IS NULL`).
+		WithArgs("sale").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	taken, err := NewURLRepository(db).IsShortCodeTaken(nil, "sale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !taken {
+		t.Error("code of a trashed link reported free")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestURLRepositoryStoreTranslatesUniqueViolation(t *testing.T) {
+	db, mock := newMockDB(t, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "urls"`).WillReturnError(&pgconn.PgError{Code: uniqueViolation, ConstraintName: "idx_urls_domain_short_code"})
+	mock.ExpectRollback()
+
+	alias := "sale"
+	err := NewURLRepository(db).Store(&domain.URL{OriginalURL: "https://example.com", ShortCode: alias, CustomAlias: &alias})
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Store error = %v, want gorm.ErrDuplicatedKey", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
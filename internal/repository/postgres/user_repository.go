@@ -29,11 +29,6 @@ func (r *userRepository) FindByID(id uuid.UUID) (*domain.User, error) {
 	err := r.db.Where("id = ?", id).First(&user).Error
 	return &user, err
 }
-func (r *userRepository) FindByAPIKey(apiKey string) (*domain.User, error) {
-	var user domain.User
-	err := r.db.Where("api_key = ?", apiKey).First(&user).Error
-	return &user, err
-}
 func (r *userRepository) Update(user *domain.User) error {
 	return r.db.Save(user).Error
 }
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"golang.org/x/sync/errgroup"
+)
+
+// analyticsFanout runs the queries behind one analytics response
+// concurrently. A failing required query cancels the others and fails the
+// whole request. A failing optional query only leaves its section empty and
+// is reported as a warning next to the data that did load.
+type analyticsFanout struct {
+	group  *errgroup.Group
+	ctx    context.Context
+	parent context.Context
+
+	mu       sync.Mutex
+	warnings []response.AnalyticsWarning
+}
+
+func newAnalyticsFanout(parent context.Context) *analyticsFanout {
+	group, ctx := errgroup.WithContext(parent)
+	return &analyticsFanout{group: group, ctx: ctx, parent: parent}
+}
+
+func (f *analyticsFanout) required(section string, query func(ctx context.Context) error) {
+	f.group.Go(func() error {
+		if err := query(f.ctx); err != nil {
+			return fmt.Errorf("%s: %w", section, err)
+		}
+		return nil
+	})
+}
+
+func (f *analyticsFanout) optional(section string, query func(ctx context.Context) error) {
+	f.group.Go(func() error {
+		if err := query(f.ctx); err != nil {
+			log.Printf("Analytics section %s failed: %v", section, err)
+			f.mu.Lock()
+			f.warnings = append(f.warnings, response.AnalyticsWarning{Section: section, Message: "This section could not be loaded"})
+			f.mu.Unlock()
+		}
+		return nil
+	})
+}
+
+// wait returns the warnings of the optional sections, or the error of the
+// first required section that failed. Running out of time is reported as
+// ANALYTICS_TIMEOUT so callers can tell it apart from a broken query.
+func (f *analyticsFanout) wait() ([]response.AnalyticsWarning, error) {
+	if err := f.group.Wait(); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(f.parent.Err(), context.DeadlineExceeded) {
+			return nil, errors.New("ANALYTICS_TIMEOUT")
+		}
+		return nil, err
+	}
+	sort.Slice(f.warnings, func(i, j int) bool { return f.warnings[i].Section < f.warnings[j].Section })
+	return f.warnings, nil
+}
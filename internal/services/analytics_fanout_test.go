@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+)
+
+// analyticsClickRepo answers every analytics query with one row. Queries
+// named in fail return that error; queries named in block wait until their
+// context ends.
+type analyticsClickRepo struct {
+	domain.ClickRepository
+	fail  map[string]error
+	block map[string]bool
+}
+
+func (r *analyticsClickRepo) query(ctx context.Context, name string) error {
+	if r.block[name] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return r.fail[name]
+}
+
+func (r *analyticsClickRepo) grouped(ctx context.Context, name string) ([]domain.GroupedResult, error) {
+	if err := r.query(ctx, name); err != nil {
+		return nil, err
+	}
+	return []domain.GroupedResult{{Value: name, Count: 1, UniqueCount: 1}}, nil
+}
+
+func (r *analyticsClickRepo) located(ctx context.Context, name string) ([]domain.LocationResult, error) {
+	if err := r.query(ctx, name); err != nil {
+		return nil, err
+	}
+	return []domain.LocationResult{{Country: "ID", Region: "Jakarta", Count: 1}}, nil
+}
+
+func (r *analyticsClickRepo) GetTotalClicks(ctx context.Context, _ domain.ClickFilter) (int64, error) {
+	return 42, r.query(ctx, "GetTotalClicks")
+}
+
+func (r *analyticsClickRepo) GetUniqueClicks(ctx context.Context, _ domain.ClickFilter) (int64, error) {
+	return 40, r.query(ctx, "GetUniqueClicks")
+}
+
+func (r *analyticsClickRepo) GetTopReferrer(ctx context.Context, _ domain.ClickFilter) (string, error) {
+	return "google.com", r.query(ctx, "GetTopReferrer")
+}
+
+func (r *analyticsClickRepo) GetTopCountry(ctx context.Context, _ domain.ClickFilter) (string, error) {
+	return "ID", r.query(ctx, "GetTopCountry")
+}
+
+func (r *analyticsClickRepo) GetClicksOverTime(ctx context.Context, filter domain.ClickFilter, _, _ string) ([]domain.TimeSeriesResult, error) {
+	if err := r.query(ctx, "GetClicksOverTime"); err != nil {
+		return nil, err
+	}
+	return []domain.TimeSeriesResult{{Bucket: filter.Range.From.Truncate(24 * time.Hour), Count: 42}}, nil
+}
+
+func (r *analyticsClickRepo) GetTopCountries(ctx context.Context, _ domain.ClickFilter, _ int) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetTopCountries")
+}
+
+func (r *analyticsClickRepo) GetTopReferrers(ctx context.Context, _ domain.ClickFilter, _ int) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetTopReferrers")
+}
+
+func (r *analyticsClickRepo) GetDeviceStats(ctx context.Context, _ domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetDeviceStats")
+}
+
+func (r *analyticsClickRepo) GetBrowserStats(ctx context.Context, _ domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetBrowserStats")
+}
+
+func (r *analyticsClickRepo) GetOSStats(ctx context.Context, _ domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetOSStats")
+}
+
+func (r *analyticsClickRepo) GetLanguageStats(ctx context.Context, _ domain.ClickFilter) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetLanguageStats")
+}
+
+func (r *analyticsClickRepo) GetDimensionStats(ctx context.Context, _ domain.ClickFilter, dimension string, _ int) ([]domain.GroupedResult, error) {
+	return r.grouped(ctx, "GetDimensionStats:"+dimension)
+}
+
+func (r *analyticsClickRepo) GetTopRegions(ctx context.Context, _ domain.ClickFilter, _ int) ([]domain.LocationResult, error) {
+	return r.located(ctx, "GetTopRegions")
+}
+
+func (r *analyticsClickRepo) GetTopCities(ctx context.Context, _ domain.ClickFilter, _ int) ([]domain.LocationResult, error) {
+	return r.located(ctx, "GetTopCities")
+}
+
+func newAnalyticsFixture(clicks *analyticsClickRepo, timeout string) (AnalyticsService, uuid.UUID, uuid.UUID) {
+	userID := uuid.New()
+	url := &domain.URL{ID: uuid.New(), UserID: &userID, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)}
+	cfg := configs.Config{Analytics: configs.AnalyticsConfig{QueryTimeout: timeout}}
+	return NewAnalyticsService(newFakeURLRepo(url), clicks, nil, nil, nil, cfg), url.ID, userID
+}
+
+var weekInUTC = request.URLAnalyticsRequest{Period: "7d", TZ: "UTC"}
+
+func TestURLAnalyticsRequiredSectionFailure(t *testing.T) {
+	broken := errors.New("relation \"clicks\" does not exist")
+	clicks := &analyticsClickRepo{
+		fail: map[string]error{"GetClicksOverTime": broken},
+		// A failing required section cancels the queries still running.
+		block: map[string]bool{"GetTopCities": true},
+	}
+	svc, urlID, userID := newAnalyticsFixture(clicks, "10s")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.GetURLAnalytics(context.Background(), urlID, userID, weekInUTC)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, broken) {
+			t.Fatalf("err = %v, want the clicks_over_time failure", err)
+		}
+		if err.Error() != "clicks_over_time: "+broken.Error() {
+			t.Errorf("err = %q, want it to name the section", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a failing required section did not cancel the others")
+	}
+}
+
+func TestURLAnalyticsOptionalSectionFailure(t *testing.T) {
+	clicks := &analyticsClickRepo{fail: map[string]error{
+		"GetDeviceStats": errors.New("statement timeout"),
+		"GetDimensionStats:" + domain.ClickDimensionVariant: errors.New("statement timeout"),
+	}}
+	svc, urlID, userID := newAnalyticsFixture(clicks, "10s")
+
+	data, err := svc.GetURLAnalytics(context.Background(), urlID, userID, weekInUTC)
+	if err != nil {
+		t.Fatalf("optional failure failed the request: %v", err)
+	}
+
+	if len(data.Warnings) != 2 || data.Warnings[0].Section != "devices" || data.Warnings[1].Section != "variants" {
+		t.Fatalf("warnings = %+v, want devices and variants", data.Warnings)
+	}
+	if len(data.Devices) != 0 || len(data.Variants) != 0 {
+		t.Errorf("failed sections are not empty: devices %v, variants %v", data.Devices, data.Variants)
+	}
+	if data.Overview.TotalClicks != 42 || data.Overview.UniqueClicks != 40 || data.Overview.TopCountry != "ID" {
+		t.Errorf("overview = %+v", data.Overview)
+	}
+	if len(data.ClicksOverTime) != 1 || len(data.Browsers) != 1 || len(data.Cities) != 1 {
+		t.Errorf("other sections missing: series %v, browsers %v, cities %v", data.ClicksOverTime, data.Browsers, data.Cities)
+	}
+}
+
+func TestURLAnalyticsTimeout(t *testing.T) {
+	clicks := &analyticsClickRepo{block: map[string]bool{"GetTotalClicks": true}}
+	svc, urlID, userID := newAnalyticsFixture(clicks, "20ms")
+
+	start := time.Now()
+	_, err := svc.GetURLAnalytics(context.Background(), urlID, userID, weekInUTC)
+	if err == nil || err.Error() != "ANALYTICS_TIMEOUT" {
+		t.Fatalf("err = %v, want ANALYTICS_TIMEOUT", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request took %s past a 20ms budget", elapsed)
+	}
+}
+
+func TestURLAnalyticsSlowOptionalSectionIsAWarning(t *testing.T) {
+	clicks := &analyticsClickRepo{block: map[string]bool{"GetTopRegions": true}}
+	svc, urlID, userID := newAnalyticsFixture(clicks, "20ms")
+
+	data, err := svc.GetURLAnalytics(context.Background(), urlID, userID, weekInUTC)
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if len(data.Warnings) != 1 || data.Warnings[0].Section != "regions" {
+		t.Fatalf("warnings = %+v, want regions", data.Warnings)
+	}
+}
+
+func TestURLAnalyticsCallerGoneIsNotATimeout(t *testing.T) {
+	clicks := &analyticsClickRepo{block: map[string]bool{"GetUniqueClicks": true}}
+	svc, urlID, userID := newAnalyticsFixture(clicks, "10s")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := svc.GetURLAnalytics(ctx, urlID, userID, weekInUTC)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
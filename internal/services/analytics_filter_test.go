@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+)
+
+// filterRecordingClickRepo records the filter of every click count and
+// ranks a single link by the matching clicks.
+type filterRecordingClickRepo struct {
+	*analyticsClickRepo
+	mu      sync.Mutex
+	filters []domain.ClickFilter
+}
+
+func (r *filterRecordingClickRepo) record(filter domain.ClickFilter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filters = append(r.filters, filter)
+}
+
+func (r *filterRecordingClickRepo) GetTotalClicks(ctx context.Context, filter domain.ClickFilter) (int64, error) {
+	r.record(filter)
+	return r.analyticsClickRepo.GetTotalClicks(ctx, filter)
+}
+
+func (r *filterRecordingClickRepo) GetUniqueClicks(ctx context.Context, filter domain.ClickFilter) (int64, error) {
+	r.record(filter)
+	return r.analyticsClickRepo.GetUniqueClicks(ctx, filter)
+}
+
+func (r *filterRecordingClickRepo) GetTopURLs(_ context.Context, filter domain.ClickFilter, _ int) ([]domain.TopURLResult, error) {
+	r.record(filter)
+	return []domain.TopURLResult{{URLID: uuid.New(), ShortCode: "filtered", Count: 3, UniqueCount: 2}}, nil
+}
+
+func (r *filterRecordingClickRepo) GetLinkGroupStats(context.Context, domain.ClickFilter, string, int) ([]domain.LinkGroupResult, error) {
+	return nil, nil
+}
+
+// dashboardURLRepo serves the lifetime counters of the user's links.
+type dashboardURLRepo struct {
+	*fakeURLRepo
+}
+
+func (r dashboardURLRepo) GetDashboardSummary(context.Context, uuid.UUID) (*domain.DashboardSummaryResult, error) {
+	return &domain.DashboardSummaryResult{TotalURLs: 3, TotalClicks: 1000, TotalUniqueClicks: 900, ActiveURLs: 2}, nil
+}
+
+func (r dashboardURLRepo) GetTopPerformingURLs(context.Context, uuid.UUID, int) ([]domain.URL, error) {
+	return []domain.URL{{ID: uuid.New(), ShortCode: "lifetime", ClickCount: 1000, UniqueClickCount: 900}}, nil
+}
+
+func (r dashboardURLRepo) GetLinkGroupTotals(context.Context, uuid.UUID, string, int) ([]domain.LinkGroupResult, error) {
+	return nil, nil
+}
+
+func (r dashboardURLRepo) GetRecentActivity(context.Context, uuid.UUID, int) ([]domain.URL, error) {
+	return nil, nil
+}
+
+func TestURLAnalyticsAppliesDimensionFilters(t *testing.T) {
+	userID := uuid.New()
+	link := &domain.URL{UserID: &userID, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)}
+	clicks := &filterRecordingClickRepo{analyticsClickRepo: &analyticsClickRepo{}}
+	svc := NewAnalyticsService(newFakeURLRepo(link), clicks, nil, nil, nil, configs.Config{})
+	urlID := link.ID
+
+	req := weekInUTC
+	req.Country = []string{"ID", "SG"}
+	req.Device = []string{"mobile"}
+	data, err := svc.GetURLAnalytics(context.Background(), urlID, userID, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{domain.ClickDimensionCountry: {"ID", "SG"}, domain.ClickDimensionDevice: {"mobile"}}
+	if !reflect.DeepEqual(data.Filters, want) {
+		t.Errorf("Filters = %v, want %v", data.Filters, want)
+	}
+	if len(clicks.filters) != 2 {
+		t.Fatalf("%d click counts, want 2", len(clicks.filters))
+	}
+	for _, filter := range clicks.filters {
+		if filter.URLID == nil || *filter.URLID != urlID || filter.UserID != nil {
+			t.Errorf("filter scoped to url %v, user %v; want url %s", filter.URLID, filter.UserID, urlID)
+		}
+		if !reflect.DeepEqual(filter.Dimensions, want) {
+			t.Errorf("Dimensions = %v, want %v", filter.Dimensions, want)
+		}
+		if filter.Range.From.IsZero() || filter.Range.To.IsZero() {
+			t.Errorf("Range = %+v, want the 7d period", filter.Range)
+		}
+	}
+}
+
+func TestDashboardCountsMatchingClicksOnlyWhenFiltered(t *testing.T) {
+	userID := uuid.New()
+
+	clicks := &filterRecordingClickRepo{analyticsClickRepo: &analyticsClickRepo{}}
+	svc := NewAnalyticsService(dashboardURLRepo{newFakeURLRepo()}, clicks, nil, nil, nil, configs.Config{})
+	data, err := svc.GetUserDashboard(context.Background(), userID, request.DashboardRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Summary.TotalClicks != 1000 || data.Summary.TotalUniqueClicks != 900 || data.Filters != nil {
+		t.Errorf("unfiltered summary = %+v, filters %v; want the lifetime counters", data.Summary, data.Filters)
+	}
+	if len(data.TopPerformingURLs) != 1 || data.TopPerformingURLs[0].ShortCode != "lifetime" {
+		t.Errorf("unfiltered top links = %+v", data.TopPerformingURLs)
+	}
+	if len(clicks.filters) != 0 {
+		t.Errorf("unfiltered dashboard queried clicks with %+v", clicks.filters)
+	}
+
+	var req request.DashboardRequest
+	req.Browser = []string{"Firefox"}
+	data, err = svc.GetUserDashboard(context.Background(), userID, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Summary.TotalClicks != 42 || data.Summary.TotalUniqueClicks != 40 {
+		t.Errorf("filtered clicks = %d/%d, want 42/40 from the matching clicks", data.Summary.TotalClicks, data.Summary.TotalUniqueClicks)
+	}
+	if data.Summary.TotalURLs != 3 || data.Summary.ActiveURLs != 2 {
+		t.Errorf("filtered link counts = %+v, want them unchanged", data.Summary)
+	}
+	if len(data.TopPerformingURLs) != 1 || data.TopPerformingURLs[0].ShortCode != "filtered" || data.TopPerformingURLs[0].ClickCount != 3 {
+		t.Errorf("filtered top links = %+v", data.TopPerformingURLs)
+	}
+	if len(clicks.filters) != 3 {
+		t.Fatalf("%d click queries, want totals, unique totals and top links", len(clicks.filters))
+	}
+	for _, filter := range clicks.filters {
+		if filter.UserID == nil || *filter.UserID != userID || filter.URLID != nil {
+			t.Errorf("filter scoped to user %v, url %v; want user %s", filter.UserID, filter.URLID, userID)
+		}
+		if !reflect.DeepEqual(filter.Dimensions, map[string][]string{domain.ClickDimensionBrowser: {"Firefox"}}) {
+			t.Errorf("Dimensions = %v", filter.Dimensions)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"time"
+	_ "time/tzdata" // zone data for the tz parameter on hosts without it
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+)
+
+// maxTimeSeriesBuckets caps the length of a click time series, which also
+// bounds the rows the database has to generate for a single request.
+const maxTimeSeriesBuckets = 1000
+
+// granularityStep is the shortest length of each bucket size. Months are
+// counted as 28 days so the bucket estimate never comes out too low.
+var granularityStep = map[string]time.Duration{
+	domain.GranularityMinute: time.Minute,
+	domain.GranularityHour:   time.Hour,
+	domain.GranularityDay:    24 * time.Hour,
+	domain.GranularityWeek:   7 * 24 * time.Hour,
+	domain.GranularityMonth:  28 * 24 * time.Hour,
+}
+
+// analyticsRange is a validated analytics query.
+type analyticsRange struct {
+	domain.TimeRange
+	Granularity string
+	Location    *time.Location
+}
+
+// resolveAnalyticsRange turns the request into a concrete range. The "all"
+// period starts at createdAt, the creation time of the link.
+func resolveAnalyticsRange(req request.URLAnalyticsRequest, createdAt, now time.Time) (*analyticsRange, error) {
+	// "Local" would resolve to the server's zone, which the database does
+	// not know by that name.
+	if req.TZ == "" || req.TZ == "Local" {
+		return nil, errors.New("ANALYTICS_INVALID_TIMEZONE")
+	}
+	loc, err := time.LoadLocation(req.TZ)
+	if err != nil {
+		return nil, errors.New("ANALYTICS_INVALID_TIMEZONE")
+	}
+
+	to := now
+	if req.To != nil {
+		to = *req.To
+	}
+
+	var from time.Time
+	switch {
+	case req.From != nil:
+		from = *req.From
+	case req.Period == "24h":
+		from = to.Add(-24 * time.Hour)
+	case req.Period == "7d":
+		from = to.AddDate(0, 0, -7)
+	case req.Period == "30d":
+		from = to.AddDate(0, 0, -30)
+	default:
+		from = createdAt
+	}
+	if !from.Before(to) {
+		return nil, errors.New("ANALYTICS_INVALID_RANGE")
+	}
+
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = defaultGranularity(to.Sub(from))
+	}
+	step, ok := granularityStep[granularity]
+	if !ok {
+		return nil, errors.New("ANALYTICS_INVALID_GRANULARITY")
+	}
+	// One extra bucket for a range that starts part way into the first one.
+	if int64(to.Sub(from)/step)+1 > maxTimeSeriesBuckets {
+		return nil, errors.New("ANALYTICS_TOO_MANY_BUCKETS")
+	}
+
+	return &analyticsRange{
+		TimeRange:   domain.TimeRange{From: from.UTC(), To: to.UTC()},
+		Granularity: granularity,
+		Location:    loc,
+	}, nil
+}
+
+func defaultGranularity(span time.Duration) string {
+	switch {
+	case span <= 48*time.Hour:
+		return domain.GranularityHour
+	case span <= 180*24*time.Hour:
+		return domain.GranularityDay
+	case span <= 3*365*24*time.Hour:
+		return domain.GranularityWeek
+	default:
+		return domain.GranularityMonth
+	}
+}
+
+// bucketStart places a bucket's wall-clock start in the requested zone.
+func (r *analyticsRange) bucketStart(bucket time.Time) time.Time {
+	return time.Date(bucket.Year(), bucket.Month(), bucket.Day(), bucket.Hour(), bucket.Minute(), 0, 0, r.Location)
+}
+
+// toResponse echoes the range in the requested zone.
+func (r *analyticsRange) toResponse() response.AnalyticsRange {
+	return response.AnalyticsRange{
+		From:        r.From.In(r.Location),
+		To:          r.To.In(r.Location),
+		Granularity: r.Granularity,
+		TimeZone:    r.Location.String(),
+	}
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+)
+
+func at(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestResolveAnalyticsRange(t *testing.T) {
+	now := *at("2025-03-15T12:00:00Z")
+	createdAt := *at("2025-01-01T00:00:00Z")
+
+	tests := []struct {
+		name        string
+		req         request.URLAnalyticsRequest
+		wantErr     string
+		from, to    time.Time
+		granularity string
+	}{
+		{
+			name:        "default period",
+			req:         request.URLAnalyticsRequest{Period: "7d", TZ: "UTC"},
+			from:        *at("2025-03-08T12:00:00Z"),
+			to:          now,
+			granularity: domain.GranularityDay,
+		},
+		{
+			name:        "24h defaults to hours",
+			req:         request.URLAnalyticsRequest{Period: "24h", TZ: "UTC"},
+			from:        *at("2025-03-14T12:00:00Z"),
+			to:          now,
+			granularity: domain.GranularityHour,
+		},
+		{
+			name:        "all starts at creation",
+			req:         request.URLAnalyticsRequest{Period: "all", TZ: "UTC"},
+			from:        createdAt,
+			to:          now,
+			granularity: domain.GranularityDay,
+		},
+		{
+			name:        "custom range wins over period",
+			req:         request.URLAnalyticsRequest{Period: "30d", From: at("2025-03-01T00:00:00+07:00"), To: at("2025-03-02T00:00:00+07:00"), TZ: "Asia/Jakarta"},
+			from:        *at("2025-02-28T17:00:00Z"),
+			to:          *at("2025-03-01T17:00:00Z"),
+			granularity: domain.GranularityHour,
+		},
+		{
+			name:        "7d across the start of DST is seven calendar days",
+			req:         request.URLAnalyticsRequest{Period: "7d", To: at("2025-03-12T00:00:00-04:00"), TZ: "America/New_York"},
+			from:        *at("2025-03-05T04:00:00Z"),
+			to:          *at("2025-03-12T04:00:00Z"),
+			granularity: domain.GranularityDay,
+		},
+		{
+			name:        "long range defaults to weeks",
+			req:         request.URLAnalyticsRequest{From: at("2024-01-01T00:00:00Z"), TZ: "UTC"},
+			from:        *at("2024-01-01T00:00:00Z"),
+			to:          now,
+			granularity: domain.GranularityWeek,
+		},
+		{
+			name:        "very long range defaults to months",
+			req:         request.URLAnalyticsRequest{From: at("2020-01-01T00:00:00Z"), TZ: "UTC"},
+			from:        *at("2020-01-01T00:00:00Z"),
+			to:          now,
+			granularity: domain.GranularityMonth,
+		},
+		{
+			name:        "1000 minute buckets",
+			req:         request.URLAnalyticsRequest{From: at("2025-03-15T00:00:00Z"), To: at("2025-03-15T16:39:00Z"), Granularity: domain.GranularityMinute, TZ: "UTC"},
+			from:        *at("2025-03-15T00:00:00Z"),
+			to:          *at("2025-03-15T16:39:00Z"),
+			granularity: domain.GranularityMinute,
+		},
+		{
+			name:    "1001 minute buckets",
+			req:     request.URLAnalyticsRequest{From: at("2025-03-15T00:00:00Z"), To: at("2025-03-15T16:40:00Z"), Granularity: domain.GranularityMinute, TZ: "UTC"},
+			wantErr: "ANALYTICS_TOO_MANY_BUCKETS",
+		},
+		{
+			name:    "hours over years",
+			req:     request.URLAnalyticsRequest{Period: "all", Granularity: domain.GranularityHour, TZ: "UTC"},
+			wantErr: "ANALYTICS_TOO_MANY_BUCKETS",
+		},
+		{
+			name:    "unknown granularity",
+			req:     request.URLAnalyticsRequest{Period: "7d", Granularity: "fortnight", TZ: "UTC"},
+			wantErr: "ANALYTICS_INVALID_GRANULARITY",
+		},
+		{
+			name:    "injected granularity",
+			req:     request.URLAnalyticsRequest{Period: "7d", Granularity: "day' || pg_sleep(1) || '", TZ: "UTC"},
+			wantErr: "ANALYTICS_INVALID_GRANULARITY",
+		},
+		{
+			name:    "empty range",
+			req:     request.URLAnalyticsRequest{From: at("2025-03-15T00:00:00Z"), To: at("2025-03-15T00:00:00Z"), TZ: "UTC"},
+			wantErr: "ANALYTICS_INVALID_RANGE",
+		},
+		{
+			name:    "reversed range",
+			req:     request.URLAnalyticsRequest{From: at("2025-03-16T00:00:00Z"), TZ: "UTC"},
+			wantErr: "ANALYTICS_INVALID_RANGE",
+		},
+		{
+			name:    "unknown zone",
+			req:     request.URLAnalyticsRequest{Period: "7d", TZ: "Mars/Olympus_Mons"},
+			wantErr: "ANALYTICS_INVALID_TIMEZONE",
+		},
+		{
+			name:    "server zone",
+			req:     request.URLAnalyticsRequest{Period: "7d", TZ: "Local"},
+			wantErr: "ANALYTICS_INVALID_TIMEZONE",
+		},
+		{
+			name:    "no zone",
+			req:     request.URLAnalyticsRequest{Period: "7d"},
+			wantErr: "ANALYTICS_INVALID_TIMEZONE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng, err := resolveAnalyticsRange(tt.req, createdAt, now)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rng.From.Equal(tt.from) || !rng.To.Equal(tt.to) {
+				t.Errorf("range = %s – %s, want %s – %s", rng.From, rng.To, tt.from, tt.to)
+			}
+			if rng.From.Location() != time.UTC || rng.To.Location() != time.UTC {
+				t.Error("range is not in UTC")
+			}
+			if rng.Granularity != tt.granularity {
+				t.Errorf("granularity = %s, want %s", rng.Granularity, tt.granularity)
+			}
+		})
+	}
+}
+
+// wallClock is how the database returns a bucket: its local start as a
+// timestamp without zone, read as UTC.
+func wallClock(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestMapTimeSeries(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		granularity string
+		buckets     []domain.TimeSeriesResult
+		want        []response.TimeSeriesStat
+	}{
+		{
+			name:        "days keep empty buckets",
+			granularity: domain.GranularityDay,
+			buckets: []domain.TimeSeriesResult{
+				{Bucket: wallClock("2025-03-08 00:00"), Count: 3, UniqueCount: 2},
+				{Bucket: wallClock("2025-03-09 00:00")},
+				{Bucket: wallClock("2025-03-10 00:00"), Count: 1, UniqueCount: 1},
+			},
+			want: []response.TimeSeriesStat{
+				{Date: "2025-03-08", Clicks: 3, UniqueClicks: 2},
+				{Date: "2025-03-09"},
+				{Date: "2025-03-10", Clicks: 1, UniqueClicks: 1},
+			},
+		},
+		{
+			name:        "hours carry the offset in effect",
+			granularity: domain.GranularityHour,
+			buckets: []domain.TimeSeriesResult{
+				{Bucket: wallClock("2025-03-09 01:00"), Count: 1},
+				// 02:00 does not exist on the day DST starts.
+				{Bucket: wallClock("2025-03-09 02:00")},
+				{Bucket: wallClock("2025-03-09 03:00"), Count: 2},
+			},
+			want: []response.TimeSeriesStat{
+				{Date: "2025-03-09T01:00:00-05:00", Clicks: 1},
+				{Date: "2025-03-09T03:00:00-04:00", Clicks: 2},
+			},
+		},
+		{
+			name:        "hours when DST ends",
+			granularity: domain.GranularityHour,
+			buckets: []domain.TimeSeriesResult{
+				{Bucket: wallClock("2025-11-02 00:00")},
+				{Bucket: wallClock("2025-11-02 01:00"), Count: 4},
+				{Bucket: wallClock("2025-11-02 02:00"), Count: 1},
+			},
+			want: []response.TimeSeriesStat{
+				{Date: "2025-11-02T00:00:00-04:00"},
+				{Date: "2025-11-02T01:00:00-04:00", Clicks: 4},
+				{Date: "2025-11-02T02:00:00-05:00", Clicks: 1},
+			},
+		},
+		{
+			name:        "weeks and months are labelled by date",
+			granularity: domain.GranularityMonth,
+			buckets: []domain.TimeSeriesResult{
+				{Bucket: wallClock("2025-03-01 00:00"), Count: 5},
+				{Bucket: wallClock("2025-04-01 00:00")},
+			},
+			want: []response.TimeSeriesStat{
+				{Date: "2025-03-01", Clicks: 5},
+				{Date: "2025-04-01"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := &analyticsRange{Granularity: tt.granularity, Location: newYork}
+			got := mapTimeSeries(tt.buckets, rng)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d buckets, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bucket %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClickDimensions(t *testing.T) {
+	if got := clickDimensions(request.ClickFilterRequest{Country: []string{""}}); got != nil {
+		t.Errorf("blank filters = %v, want nil", got)
+	}
+
+	got := clickDimensions(request.ClickFilterRequest{
+		OS:       []string{"iOS", "", "Android"},
+		Language: []string{"id"},
+		City:     []string{"Bandung"},
+	})
+	want := map[string][]string{
+		domain.ClickDimensionOS:       {"iOS", "Android"},
+		domain.ClickDimensionLanguage: {"id"},
+		domain.ClickDimensionCity:     {"Bandung"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dimensions = %v, want %v", got, want)
+	}
+}
@@ -1,153 +1,440 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"sync"
 	"time"
 
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AnalyticsService interface {
-	GetURLAnalytics(urlID, userID uuid.UUID, period string) (*response.URLAnalyticsResponse, error)
-	GetUserDashboard(userID uuid.UUID) (*response.UserDashboardResponse, error)
+	GetURLAnalytics(ctx context.Context, urlID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.URLAnalyticsResponse, error)
+	GetCampaignAnalytics(ctx context.Context, campaignID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.CampaignAnalyticsResponse, error)
+	GetTagAnalytics(ctx context.Context, tagID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.TagAnalyticsResponse, error)
+	GetFolderAnalytics(ctx context.Context, folderID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.FolderAnalyticsResponse, error)
+	GetUserDashboard(ctx context.Context, userID uuid.UUID, req request.DashboardRequest) (*response.UserDashboardResponse, error)
 }
 
 type analyticsService struct {
-	urlRepo   domain.URLRepository
-	clickRepo domain.ClickRepository
+	urlRepo      domain.URLRepository
+	clickRepo    domain.ClickRepository
+	campaignRepo domain.CampaignRepository
+	tagRepo      domain.TagRepository
+	folderRepo   domain.FolderRepository
+	cfg          configs.Config
 }
 
-func NewAnalyticsService(urlRepo domain.URLRepository, clickRepo domain.ClickRepository) AnalyticsService {
-	return &analyticsService{urlRepo: urlRepo, clickRepo: clickRepo}
+func NewAnalyticsService(urlRepo domain.URLRepository, clickRepo domain.ClickRepository, campaignRepo domain.CampaignRepository, tagRepo domain.TagRepository, folderRepo domain.FolderRepository, cfg configs.Config) AnalyticsService {
+	return &analyticsService{urlRepo: urlRepo, clickRepo: clickRepo, campaignRepo: campaignRepo, tagRepo: tagRepo, folderRepo: folderRepo, cfg: cfg}
 }
 
-func (s *analyticsService) GetURLAnalytics(urlID, userID uuid.UUID, period string) (*response.URLAnalyticsResponse, error) {
+// GetURLAnalytics fails when the overview or the time series cannot be
+// loaded. The other breakdowns are optional and reported as warnings.
+func (s *analyticsService) GetURLAnalytics(ctx context.Context, urlID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.URLAnalyticsResponse, error) {
 	url, err := s.urlRepo.FindByID(urlID)
 	if err != nil {
-		return nil, errors.New("URL_NOT_FOUND")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("URL_NOT_FOUND")
+		}
+		return nil, err
 	}
 	if url.UserID == nil || *url.UserID != userID {
 		return nil, errors.New("URL_FORBIDDEN")
 	}
 
-	since := time.Now()
-	switch period {
-	case "24h":
-		since = since.Add(-24 * time.Hour)
-	case "7d":
-		since = since.AddDate(0, 0, -7)
-	case "30d":
-		since = since.AddDate(0, 0, -30)
-	default:
-		since = time.Time{}
-	}
-
-	var wg sync.WaitGroup
-	var analyticsData response.URLAnalyticsResponse
-	var errs = make(chan error, 10)
-
-	wg.Add(3)
-	go func() {
-		defer wg.Done()
-		analyticsData.Overview.TotalClicks, _ = s.clickRepo.GetTotalClicks(urlID, since)
-	}()
-	go func() {
-		defer wg.Done()
-		analyticsData.Overview.TopReferrer, _ = s.clickRepo.GetTopReferrer(urlID, since)
-	}()
-	go func() {
-		defer wg.Done()
-		analyticsData.Overview.TopCountry, _ = s.clickRepo.GetTopCountry(urlID, since)
-	}()
-
-	wg.Add(5)
-	go func() {
-		defer wg.Done()
-		res, _ := s.clickRepo.GetClicksOverTime(urlID, since)
-		analyticsData.ClicksOverTime = mapTimeSeries(res)
-	}()
-	go func() {
-		defer wg.Done()
-		res, _ := s.clickRepo.GetTopReferrers(urlID, since, 10)
+	rng, err := resolveAnalyticsRange(req, url.CreatedAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter := domain.ClickFilter{URLID: &urlID, Range: rng.TimeRange, Dimensions: clickDimensions(req.ClickFilterRequest)}
+
+	analyticsData := response.URLAnalyticsResponse{Range: rng.toResponse(), Filters: filter.Dimensions}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Analytics.Timeout())
+	defer cancel()
+	fanout := newAnalyticsFanout(ctx)
+	s.addClickSections(fanout, filter, rng, &analyticsData)
+
+	if analyticsData.Warnings, err = fanout.wait(); err != nil {
+		return nil, err
+	}
+	return &analyticsData, nil
+}
+
+// GetCampaignAnalytics aggregates the clicks of every link in the campaign
+// that is not in the trash.
+func (s *analyticsService) GetCampaignAnalytics(ctx context.Context, campaignID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.CampaignAnalyticsResponse, error) {
+	campaign, err := s.campaignRepo.FindByID(campaignID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("CAMPAIGN_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if campaign.UserID != userID {
+		return nil, errors.New("CAMPAIGN_FORBIDDEN")
+	}
+
+	group, err := s.getLinkGroupAnalytics(ctx, domain.ClickFilter{CampaignID: &campaignID}, campaign.CreatedAt, req, func(ctx context.Context) (int64, error) {
+		return s.urlRepo.CountByCampaignID(ctx, campaignID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response.CampaignAnalyticsResponse{CampaignID: campaign.ID, Name: campaign.Name, LinkGroupAnalyticsResponse: *group}, nil
+}
+
+// GetTagAnalytics aggregates the clicks of every link with the tag that is
+// not in the trash.
+func (s *analyticsService) GetTagAnalytics(ctx context.Context, tagID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.TagAnalyticsResponse, error) {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("TAG_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, errors.New("TAG_FORBIDDEN")
+	}
+
+	group, err := s.getLinkGroupAnalytics(ctx, domain.ClickFilter{TagID: &tagID}, tag.CreatedAt, req, func(ctx context.Context) (int64, error) {
+		return s.urlRepo.CountByTagID(ctx, tagID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response.TagAnalyticsResponse{TagID: tag.ID, Name: tag.Name, LinkGroupAnalyticsResponse: *group}, nil
+}
+
+// GetFolderAnalytics aggregates the clicks of every link in the folder that
+// is not in the trash.
+func (s *analyticsService) GetFolderAnalytics(ctx context.Context, folderID, userID uuid.UUID, req request.URLAnalyticsRequest) (*response.FolderAnalyticsResponse, error) {
+	folder, err := s.folderRepo.FindByID(folderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("FOLDER_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if folder.UserID != userID {
+		return nil, errors.New("FOLDER_FORBIDDEN")
+	}
+
+	group, err := s.getLinkGroupAnalytics(ctx, domain.ClickFilter{FolderID: &folderID}, folder.CreatedAt, req, func(ctx context.Context) (int64, error) {
+		return s.urlRepo.CountByFolderID(ctx, folderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response.FolderAnalyticsResponse{FolderID: folder.ID, Name: folder.Name, LinkGroupAnalyticsResponse: *group}, nil
+}
+
+// getLinkGroupAnalytics computes the same sections as GetURLAnalytics over
+// the clicks selected by filter, plus the group's link count and top links.
+// The range defaults to starting when the group was created.
+func (s *analyticsService) getLinkGroupAnalytics(ctx context.Context, filter domain.ClickFilter, createdAt time.Time, req request.URLAnalyticsRequest, countURLs func(context.Context) (int64, error)) (*response.LinkGroupAnalyticsResponse, error) {
+	rng, err := resolveAnalyticsRange(req, createdAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter.Range = rng.TimeRange
+	filter.Dimensions = clickDimensions(req.ClickFilterRequest)
+
+	analyticsData := response.LinkGroupAnalyticsResponse{
+		URLAnalyticsResponse: response.URLAnalyticsResponse{Range: rng.toResponse(), Filters: filter.Dimensions},
+		TopURLs:              []response.DashboardTopURL{},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Analytics.Timeout())
+	defer cancel()
+	fanout := newAnalyticsFanout(ctx)
+	s.addClickSections(fanout, filter, rng, &analyticsData.URLAnalyticsResponse)
+
+	fanout.optional("total_urls", func(ctx context.Context) (err error) {
+		analyticsData.TotalURLs, err = countURLs(ctx)
+		return err
+	})
+	fanout.optional("top_urls", func(ctx context.Context) error {
+		topURLs, err := s.clickRepo.GetTopURLs(ctx, filter, 10)
+		for _, u := range topURLs {
+			analyticsData.TopURLs = append(analyticsData.TopURLs, response.DashboardTopURL{
+				URLID: u.URLID, ShortCode: u.ShortCode, Title: u.Title, ClickCount: int(u.Count), UniqueClickCount: int(u.UniqueCount),
+			})
+		}
+		return err
+	})
+
+	if analyticsData.Warnings, err = fanout.wait(); err != nil {
+		return nil, err
+	}
+	return &analyticsData, nil
+}
+
+// addClickSections schedules every section of a click analytics response.
+// The overview and the time series are required, the breakdowns optional.
+func (s *analyticsService) addClickSections(fanout *analyticsFanout, filter domain.ClickFilter, rng *analyticsRange, analyticsData *response.URLAnalyticsResponse) {
+	fanout.required("total_clicks", func(ctx context.Context) (err error) {
+		analyticsData.Overview.TotalClicks, err = s.clickRepo.GetTotalClicks(ctx, filter)
+		return err
+	})
+	fanout.required("unique_clicks", func(ctx context.Context) (err error) {
+		analyticsData.Overview.UniqueClicks, err = s.clickRepo.GetUniqueClicks(ctx, filter)
+		return err
+	})
+	fanout.required("clicks_over_time", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetClicksOverTime(ctx, filter, rng.Granularity, rng.Location.String())
+		analyticsData.ClicksOverTime = mapTimeSeries(res, rng)
+		return err
+	})
+	fanout.optional("top_referrer", func(ctx context.Context) (err error) {
+		analyticsData.Overview.TopReferrer, err = s.clickRepo.GetTopReferrer(ctx, filter)
+		return err
+	})
+	fanout.optional("top_country", func(ctx context.Context) (err error) {
+		analyticsData.Overview.TopCountry, err = s.clickRepo.GetTopCountry(ctx, filter)
+		return err
+	})
+	fanout.optional("referrers", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetTopReferrers(ctx, filter, 10)
 		analyticsData.Referrers = mapGrouped(res)
-	}()
-	go func() {
-		defer wg.Done()
-		res, _ := s.clickRepo.GetTopCountries(urlID, since, 10)
+		return err
+	})
+	fanout.optional("referrer_categories", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetDimensionStats(ctx, filter, domain.ClickDimensionReferrerCategory, 10)
+		analyticsData.ReferrerCategories = mapGrouped(res)
+		return err
+	})
+	utmSections := []struct {
+		section   string
+		dimension string
+		stats     *[]response.GroupedStat
+	}{
+		{"utm_sources", domain.ClickDimensionUTMSource, &analyticsData.UTM.Sources},
+		{"utm_mediums", domain.ClickDimensionUTMMedium, &analyticsData.UTM.Mediums},
+		{"utm_campaigns", domain.ClickDimensionUTMCampaign, &analyticsData.UTM.Campaigns},
+		{"utm_terms", domain.ClickDimensionUTMTerm, &analyticsData.UTM.Terms},
+		{"utm_contents", domain.ClickDimensionUTMContent, &analyticsData.UTM.Contents},
+	}
+	for _, u := range utmSections {
+		fanout.optional(u.section, func(ctx context.Context) error {
+			res, err := s.clickRepo.GetDimensionStats(ctx, filter, u.dimension, 10)
+			*u.stats = mapGrouped(res)
+			return err
+		})
+	}
+	fanout.optional("variants", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetDimensionStats(ctx, filter, domain.ClickDimensionVariant, 10)
+		analyticsData.Variants = mapGrouped(res)
+		return err
+	})
+	fanout.optional("countries", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetTopCountries(ctx, filter, 10)
 		analyticsData.Countries = mapGrouped(res)
-	}()
-	go func() {
-		defer wg.Done()
-		res, _ := s.clickRepo.GetDeviceStats(urlID, since)
+		return err
+	})
+	fanout.optional("regions", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetTopRegions(ctx, filter, 10)
+		analyticsData.Regions = mapLocations(res)
+		return err
+	})
+	fanout.optional("cities", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetTopCities(ctx, filter, 10)
+		analyticsData.Cities = mapLocations(res)
+		return err
+	})
+	fanout.optional("devices", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetDeviceStats(ctx, filter)
 		analyticsData.Devices = mapGrouped(res)
-	}()
-	go func() {
-		defer wg.Done()
-		res, _ := s.clickRepo.GetBrowserStats(urlID, since)
+		return err
+	})
+	fanout.optional("browsers", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetBrowserStats(ctx, filter)
 		analyticsData.Browsers = mapGrouped(res)
-	}()
-
-	wg.Wait()
-	close(errs)
-
-	return &analyticsData, nil
+		return err
+	})
+	fanout.optional("operating_systems", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetOSStats(ctx, filter)
+		analyticsData.OperatingSystems = mapGrouped(res)
+		return err
+	})
+	fanout.optional("languages", func(ctx context.Context) error {
+		res, err := s.clickRepo.GetLanguageStats(ctx, filter)
+		analyticsData.Languages = mapGrouped(res)
+		return err
+	})
 }
 
-func mapTimeSeries(res []domain.TimeSeriesResult) []response.TimeSeriesStat {
-	stats := make([]response.TimeSeriesStat, len(res))
-	for i, r := range res {
-		stats[i] = response.TimeSeriesStat{Date: r.Date.Format("2006-01-02"), Clicks: r.Count}
+// mapTimeSeries labels day, week and month buckets with their local date and
+// shorter buckets with their RFC 3339 start time in the requested zone.
+// Minute and hour buckets a DST change skips are dropped: no click falls
+// into them, and their start would be labelled as the next bucket's.
+func mapTimeSeries(res []domain.TimeSeriesResult, rng *analyticsRange) []response.TimeSeriesStat {
+	layout := time.RFC3339
+	switch rng.Granularity {
+	case domain.GranularityDay, domain.GranularityWeek, domain.GranularityMonth:
+		layout = "2006-01-02"
+	}
+
+	stats := make([]response.TimeSeriesStat, 0, len(res))
+	for _, r := range res {
+		start := rng.bucketStart(r.Bucket)
+		if layout == time.RFC3339 && (start.Hour() != r.Bucket.Hour() || start.Minute() != r.Bucket.Minute()) {
+			continue
+		}
+		stats = append(stats, response.TimeSeriesStat{Date: start.Format(layout), Clicks: r.Count, UniqueClicks: r.UniqueCount})
 	}
 	return stats
 }
 func mapGrouped(res []domain.GroupedResult) []response.GroupedStat {
 	stats := make([]response.GroupedStat, len(res))
 	for i, r := range res {
-		stats[i] = response.GroupedStat{Value: r.Value, Count: r.Count}
+		stats[i] = response.GroupedStat{Value: r.Value, Count: r.Count, UniqueCount: r.UniqueCount}
 	}
 	return stats
 }
 
-func (s *analyticsService) GetUserDashboard(userID uuid.UUID) (*response.UserDashboardResponse, error) {
-	var wg sync.WaitGroup
-	dashboardData := &response.UserDashboardResponse{}
+func mapLocations(res []domain.LocationResult) []response.LocationStat {
+	stats := make([]response.LocationStat, len(res))
+	for i, r := range res {
+		stats[i] = response.LocationStat{Country: r.Country, Region: r.Region, City: r.City, Count: r.Count, UniqueCount: r.UniqueCount}
+	}
+	return stats
+}
 
-	wg.Add(3)
+// GetUserDashboard summarises the user's links. With dimension filters the
+// click totals and the top links, tags and folders are computed from the
+// matching clicks instead of the links' counters. The summary is required;
+// the other sections are optional and reported as warnings.
+func (s *analyticsService) GetUserDashboard(ctx context.Context, userID uuid.UUID, req request.DashboardRequest) (*response.UserDashboardResponse, error) {
+	filter := domain.ClickFilter{UserID: &userID, Dimensions: clickDimensions(req.ClickFilterRequest)}
+	filtered := len(filter.Dimensions) > 0
+	dashboardData := &response.UserDashboardResponse{
+		Filters:           filter.Dimensions,
+		TopPerformingURLs: []response.DashboardTopURL{},
+		TopTags:           []response.DashboardLinkGroup{},
+		TopFolders:        []response.DashboardLinkGroup{},
+		RecentActivity:    []response.DashboardActivityItem{},
+	}
 
-	go func() {
-		defer wg.Done()
-		summary, _ := s.urlRepo.GetDashboardSummary(userID)
-		if summary != nil {
-			dashboardData.Summary = response.DashboardSummary(*summary)
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Analytics.Timeout())
+	defer cancel()
+	fanout := newAnalyticsFanout(ctx)
+
+	fanout.required("summary", func(ctx context.Context) error {
+		summary, err := s.urlRepo.GetDashboardSummary(ctx, userID)
+		if err != nil {
+			return err
 		}
-	}()
-
-	go func() {
-		defer wg.Done()
-		topURLs, _ := s.urlRepo.GetTopPerformingURLs(userID, 5)
-		dashboardData.TopPerformingURLs = make([]response.DashboardTopURL, len(topURLs))
-		for i, u := range topURLs {
-			dashboardData.TopPerformingURLs[i] = response.DashboardTopURL{
-				URLID: u.ID, ShortCode: u.ShortCode, Title: u.Title, ClickCount: u.ClickCount,
-			}
+		dashboardData.Summary = response.DashboardSummary(*summary)
+		if !filtered {
+			return nil
 		}
-	}()
-
-	go func() {
-		defer wg.Done()
-		recentURLs, _ := s.urlRepo.GetRecentActivity(userID, 5)
-		dashboardData.RecentActivity = make([]response.DashboardActivityItem, len(recentURLs))
-		for i, u := range recentURLs {
-			dashboardData.RecentActivity[i] = response.DashboardActivityItem{
-				URLID: u.ID, ShortCode: u.ShortCode, Title: u.Title, LastClickedAt: u.LastClickedAt,
+		if dashboardData.Summary.TotalClicks, err = s.clickRepo.GetTotalClicks(ctx, filter); err != nil {
+			return err
+		}
+		dashboardData.Summary.TotalUniqueClicks, err = s.clickRepo.GetUniqueClicks(ctx, filter)
+		return err
+	})
+
+	fanout.optional("top_performing_urls", func(ctx context.Context) error {
+		if filtered {
+			topURLs, err := s.clickRepo.GetTopURLs(ctx, filter, 5)
+			for _, u := range topURLs {
+				dashboardData.TopPerformingURLs = append(dashboardData.TopPerformingURLs, response.DashboardTopURL{
+					URLID: u.URLID, ShortCode: u.ShortCode, Title: u.Title, ClickCount: int(u.Count), UniqueClickCount: int(u.UniqueCount),
+				})
 			}
+			return err
 		}
-	}()
+		topURLs, err := s.urlRepo.GetTopPerformingURLs(ctx, userID, 5)
+		for _, u := range topURLs {
+			dashboardData.TopPerformingURLs = append(dashboardData.TopPerformingURLs, response.DashboardTopURL{
+				URLID: u.ID, ShortCode: u.ShortCode, Title: u.Title, ClickCount: u.ClickCount, UniqueClickCount: u.UniqueClickCount,
+			})
+		}
+		return err
+	})
+
+	for _, section := range []struct {
+		name  string
+		group string
+		dest  *[]response.DashboardLinkGroup
+	}{
+		{"top_tags", domain.LinkGroupTag, &dashboardData.TopTags},
+		{"top_folders", domain.LinkGroupFolder, &dashboardData.TopFolders},
+	} {
+		fanout.optional(section.name, func(ctx context.Context) error {
+			var groups []domain.LinkGroupResult
+			var err error
+			if filtered {
+				groups, err = s.clickRepo.GetLinkGroupStats(ctx, filter, section.group, 5)
+			} else {
+				groups, err = s.urlRepo.GetLinkGroupTotals(ctx, userID, section.group, 5)
+			}
+			for _, g := range groups {
+				*section.dest = append(*section.dest, response.DashboardLinkGroup{
+					ID: g.ID, Name: g.Name, ClickCount: g.Count, UniqueClickCount: g.UniqueCount,
+				})
+			}
+			return err
+		})
+	}
 
-	wg.Wait()
+	fanout.optional("recent_activity", func(ctx context.Context) error {
+		recentURLs, err := s.urlRepo.GetRecentActivity(ctx, userID, 5)
+		for _, u := range recentURLs {
+			dashboardData.RecentActivity = append(dashboardData.RecentActivity, response.DashboardActivityItem{
+				URLID: u.ID, ShortCode: u.ShortCode, Title: u.Title, LastClickedAt: u.LastClickedAt,
+			})
+		}
+		return err
+	})
 
+	var err error
+	if dashboardData.Warnings, err = fanout.wait(); err != nil {
+		return nil, err
+	}
 	return dashboardData, nil
 }
+
+// clickDimensions collects the non-empty filter values by dimension, or nil
+// when no filter is set.
+func clickDimensions(req request.ClickFilterRequest) map[string][]string {
+	var dimensions map[string][]string
+	add := func(dimension string, values []string) {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if dimensions == nil {
+				dimensions = make(map[string][]string)
+			}
+			dimensions[dimension] = append(dimensions[dimension], value)
+		}
+	}
+
+	add(domain.ClickDimensionCountry, req.Country)
+	add(domain.ClickDimensionRegion, req.Region)
+	add(domain.ClickDimensionCity, req.City)
+	add(domain.ClickDimensionDevice, req.Device)
+	add(domain.ClickDimensionBrowser, req.Browser)
+	add(domain.ClickDimensionOS, req.OS)
+	add(domain.ClickDimensionLanguage, req.Language)
+	add(domain.ClickDimensionReferrer, req.Referrer)
+	add(domain.ClickDimensionReferrerDomain, req.ReferrerDomain)
+	add(domain.ClickDimensionReferrerCategory, req.ReferrerCategory)
+	add(domain.ClickDimensionUTMSource, req.UTMSource)
+	add(domain.ClickDimensionUTMMedium, req.UTMMedium)
+	add(domain.ClickDimensionUTMCampaign, req.UTMCampaign)
+	add(domain.ClickDimensionUTMTerm, req.UTMTerm)
+	add(domain.ClickDimensionUTMContent, req.UTMContent)
+	add(domain.ClickDimensionVariant, req.Variant)
+	return dimensions
+}
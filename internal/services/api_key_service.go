@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyDisplayLength is how much of a key is kept in clear, including
+	// the "usk_" prefix, so the owner can recognise it in listings.
+	apiKeyDisplayLength = 12
+	maxAPIKeysPerUser   = 50
+)
+
+type APIKeyService interface {
+	// CreateKey stores a new key and returns it together with its secret.
+	// The secret is not stored and cannot be retrieved again.
+	CreateKey(userID uuid.UUID, req request.CreateAPIKeyRequest) (*domain.APIKey, string, error)
+	ListKeys(userID uuid.UUID) ([]domain.APIKey, error)
+	RevokeKey(keyID, userID uuid.UUID) error
+}
+
+type apiKeyService struct {
+	apiKeyRepo domain.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo domain.APIKeyRepository) APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo}
+}
+
+func (s *apiKeyService) CreateKey(userID uuid.UUID, req request.CreateAPIKeyRequest) (*domain.APIKey, string, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", errors.New("API_KEY_INVALID_EXPIRY")
+	}
+
+	existing, err := s.apiKeyRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, "", errors.New("API_KEY_LIMIT_REACHED")
+	}
+
+	secret, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &domain.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    secret[:apiKeyDisplayLength],
+		KeyHash:   utils.HashAPIKey(secret),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Store(key); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// normalizeScopes rejects unknown scopes and drops duplicates, keeping the
+// order in which scopes were requested.
+func normalizeScopes(requested []string) ([]string, error) {
+	scopes := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, scope := range requested {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !isKnownScope(scope) {
+			return nil, errors.New("API_KEY_INVALID_SCOPE")
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range domain.APIKeyScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *apiKeyService) ListKeys(userID uuid.UUID) ([]domain.APIKey, error) {
+	return s.apiKeyRepo.FindByUserID(userID)
+}
+
+func (s *apiKeyService) RevokeKey(keyID, userID uuid.UUID) error {
+	key, err := s.apiKeyRepo.FindByID(keyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("API_KEY_NOT_FOUND")
+		}
+		return err
+	}
+	if key.UserID != userID {
+		return errors.New("API_KEY_FORBIDDEN")
+	}
+	if key.RevokedAt != nil {
+		return errors.New("API_KEY_NOT_FOUND")
+	}
+	return s.apiKeyRepo.Revoke(keyID)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeAPIKeyRepo struct {
+	domain.APIKeyRepository
+	keys map[uuid.UUID]domain.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Store(key *domain.APIKey) error {
+	key.ID = uuid.New()
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) FindByID(id uuid.UUID) (*domain.APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &key, nil
+}
+
+func (r *fakeAPIKeyRepo) FindByUserID(userID uuid.UUID) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	for _, key := range r.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(id uuid.UUID) error {
+	key := r.keys[id]
+	now := time.Now()
+	key.RevokedAt = &now
+	r.keys[id] = key
+	return nil
+}
+
+func TestNormalizeScopes(t *testing.T) {
+	got, err := normalizeScopes([]string{" URLs:Read ", "analytics:read", "urls:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "urls:read,analytics:read" {
+		t.Errorf("scopes = %v, want [urls:read analytics:read]", got)
+	}
+
+	if _, err := normalizeScopes([]string{"urls:read", "admin"}); err == nil || err.Error() != "API_KEY_INVALID_SCOPE" {
+		t.Errorf("unknown scope: error = %v, want API_KEY_INVALID_SCOPE", err)
+	}
+}
+
+func TestCreateKeyStoresOnlyTheHash(t *testing.T) {
+	repo := &fakeAPIKeyRepo{keys: map[uuid.UUID]domain.APIKey{}}
+	svc := NewAPIKeyService(repo)
+	userID := uuid.New()
+
+	key, secret, err := svc.CreateKey(userID, request.CreateAPIKeyRequest{Name: " CI ", Scopes: []string{"urls:read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := repo.keys[key.ID]
+	if stored.KeyHash != utils.HashAPIKey(secret) || strings.Contains(stored.KeyHash, secret) {
+		t.Error("stored key is not the hash of the secret")
+	}
+	if !strings.HasPrefix(secret, stored.Prefix) || len(stored.Prefix) != apiKeyDisplayLength {
+		t.Errorf("prefix %q does not start the secret", stored.Prefix)
+	}
+	if stored.Name != "CI" || stored.UserID != userID {
+		t.Errorf("stored key = %+v", stored)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	if _, _, err := svc.CreateKey(userID, request.CreateAPIKeyRequest{Name: "old", ExpiresAt: &past}); err == nil || err.Error() != "API_KEY_INVALID_EXPIRY" {
+		t.Errorf("expired key: error = %v, want API_KEY_INVALID_EXPIRY", err)
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	repo := &fakeAPIKeyRepo{keys: map[uuid.UUID]domain.APIKey{}}
+	svc := NewAPIKeyService(repo)
+	owner := uuid.New()
+	key, _, err := svc.CreateKey(owner, request.CreateAPIKeyRequest{Name: "CI"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.RevokeKey(key.ID, uuid.New()); err == nil || err.Error() != "API_KEY_FORBIDDEN" {
+		t.Errorf("revoke by another user: error = %v, want API_KEY_FORBIDDEN", err)
+	}
+	if err := svc.RevokeKey(key.ID, owner); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RevokeKey(key.ID, owner); err == nil || err.Error() != "API_KEY_NOT_FOUND" {
+		t.Errorf("second revoke: error = %v, want API_KEY_NOT_FOUND", err)
+	}
+}
@@ -2,12 +2,14 @@ package services
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,20 +19,28 @@ type LoginResult struct {
 	RefreshToken string
 }
 
+type RefreshResult struct {
+	AccessToken  string
+	RefreshToken string
+}
+
 type AuthService interface {
 	Register(req request.RegisterRequest) (*domain.User, error)
-	Login(req request.LoginRequest) (*LoginResult, error)
-	RefreshToken(refreshToken string) (string, error)
-	Logout(accessToken string) error
+	Login(req request.LoginRequest, userAgent, ipAddress string) (*LoginResult, error)
+	RefreshToken(refreshToken string) (*RefreshResult, error)
+	Logout(sessionID uuid.UUID) error
+	LogoutAll(userID uuid.UUID) error
 }
 
 type authService struct {
-	userRepo domain.UserRepository
-	cfg      configs.Config
+	userRepo         domain.UserRepository
+	sessionRepo      domain.SessionRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	cfg              configs.Config
 }
 
-func NewAuthService(userRepo domain.UserRepository, cfg configs.Config) AuthService {
-	return &authService{userRepo: userRepo, cfg: cfg}
+func NewAuthService(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, refreshTokenRepo domain.RefreshTokenRepository, cfg configs.Config) AuthService {
+	return &authService{userRepo: userRepo, sessionRepo: sessionRepo, refreshTokenRepo: refreshTokenRepo, cfg: cfg}
 }
 
 func (s *authService) Register(req request.RegisterRequest) (*domain.User, error) {
@@ -47,15 +57,9 @@ func (s *authService) Register(req request.RegisterRequest) (*domain.User, error
 		return nil, err
 	}
 
-	apiKey, err := utils.GenerateAPIKey()
-	if err != nil {
-		return nil, err
-	}
-
 	newUser := &domain.User{
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
-		APIKey:       apiKey,
 		FirstName:    &req.FirstName,
 		LastName:     &req.LastName,
 		PlanType:     "free",
@@ -68,7 +72,7 @@ func (s *authService) Register(req request.RegisterRequest) (*domain.User, error
 	return newUser, nil
 }
 
-func (s *authService) Login(req request.LoginRequest) (*LoginResult, error) {
+func (s *authService) Login(req request.LoginRequest, userAgent, ipAddress string) (*LoginResult, error) {
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -81,14 +85,25 @@ func (s *authService) Login(req request.LoginRequest) (*LoginResult, error) {
 		return nil, errors.New("AUTH_INVALID_CREDENTIALS")
 	}
 
-	accessExpiresIn, _ := time.ParseDuration(s.cfg.JWT.ExpiresIn)
-	accessToken, err := utils.GenerateToken(user.ID, s.cfg.JWT.SecretKey, accessExpiresIn)
+	now := time.Now()
+	session := &domain.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.cfg.JWT.RefreshTTL()),
+	}
+	if err := s.sessionRepo.Store(session); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(user.ID, session.ID, s.cfg.JWT.SecretKey, s.cfg.JWT.AccessTTL())
 	if err != nil {
 		return nil, err
 	}
 
-	refreshExpiresIn, _ := time.ParseDuration(s.cfg.JWT.RefreshExpiresIn)
-	refreshToken, err := utils.GenerateToken(user.ID, s.cfg.JWT.RefreshSecretKey, refreshExpiresIn)
+	refreshToken, err := s.issueRefreshToken(session, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -100,26 +115,121 @@ func (s *authService) Login(req request.LoginRequest) (*LoginResult, error) {
 	}, nil
 }
 
-func (s *authService) RefreshToken(refreshToken string) (string, error) {
+// RefreshToken exchanges a refresh token for a new access and refresh token
+// pair. The presented token is consumed; presenting it again is treated as
+// token theft and revokes the whole session, since either the legitimate
+// client or an attacker is holding a copy. The exception is a token rotated
+// within the refresh grace period whose successor is still unused: that is
+// a client refreshing from several tabs or retrying at once, and it receives
+// the same successor.
+func (s *authService) RefreshToken(refreshToken string) (*RefreshResult, error) {
 	claims, err := utils.ValidateToken(refreshToken, s.cfg.JWT.RefreshSecretKey)
 	if err != nil {
-		return "", errors.New("AUTH_INVALID_REFRESH_TOKEN")
+		return nil, errors.New("AUTH_INVALID_REFRESH_TOKEN")
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, errors.New("AUTH_INVALID_REFRESH_TOKEN")
+	}
+
+	session, err := s.sessionRepo.FindByID(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID || !session.IsActive(time.Now()) {
+		return nil, errors.New("AUTH_INVALID_REFRESH_TOKEN")
 	}
 
 	_, err = s.userRepo.FindByID(claims.UserID)
 	if err != nil {
-		return "", errors.New("AUTH_USER_NOT_FOUND")
+		return nil, errors.New("AUTH_USER_NOT_FOUND")
+	}
+
+	newRefreshToken, err := s.issueRefreshToken(session, &tokenID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		newRefreshToken, err = s.reissueSuccessor(tokenID, session)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	accessExpiresIn, _ := time.ParseDuration(s.cfg.JWT.ExpiresIn)
-	newAccessToken, err := utils.GenerateToken(claims.UserID, s.cfg.JWT.SecretKey, accessExpiresIn)
+	newAccessToken, err := utils.GenerateToken(claims.UserID, session.ID, s.cfg.JWT.SecretKey, s.cfg.JWT.AccessTTL())
 	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Touch(session.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &RefreshResult{
+		AccessToken:  newAccessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// issueRefreshToken records and signs a refresh token for the session. With
+// a parent, the parent is consumed in the same step; refresh tokens never
+// outlive their session, so rotation does not extend a login.
+func (s *authService) issueRefreshToken(session *domain.Session, parentID *uuid.UUID) (string, error) {
+	now := time.Now()
+	next := &domain.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		ParentID:  parentID,
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	var err error
+	if parentID == nil {
+		err = s.refreshTokenRepo.Store(next)
+	} else {
+		err = s.refreshTokenRepo.Rotate(*parentID, next, now)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return s.signRefreshToken(next, session, now)
+}
+
+func (s *authService) signRefreshToken(token *domain.RefreshToken, session *domain.Session, now time.Time) (string, error) {
+	return utils.GenerateTokenWithID(token.ID, session.UserID, session.ID, s.cfg.JWT.RefreshSecretKey, session.ExpiresAt.Sub(now))
+}
+
+// reissueSuccessor handles a refresh token that could not be consumed. A
+// token rotated within the grace period whose successor is still unused
+// gets that successor signed again; any other used token revokes the
+// session.
+func (s *authService) reissueSuccessor(tokenID uuid.UUID, session *domain.Session) (string, error) {
+	token, err := s.refreshTokenRepo.FindByID(tokenID)
+	if err != nil || token.SessionID != session.ID || token.UsedAt == nil {
+		return "", errors.New("AUTH_INVALID_REFRESH_TOKEN")
+	}
+
+	now := time.Now()
+	if now.Sub(*token.UsedAt) <= s.cfg.JWT.RefreshGracePeriod() {
+		successor, err := s.refreshTokenRepo.FindByParentID(tokenID)
+		if err == nil && successor.UsedAt == nil && now.Before(successor.ExpiresAt) {
+			return s.signRefreshToken(successor, session, now)
+		}
+	}
+
+	log.Printf("Refresh token %s of session %s was reused; revoking the session", tokenID, session.ID)
+	if err := s.sessionRepo.Revoke(session.ID); err != nil {
 		return "", err
 	}
+	return "", errors.New("AUTH_REFRESH_TOKEN_REUSED")
+}
 
-	return newAccessToken, nil
+// Logout revokes the session of the current token, which invalidates both
+// its access and refresh token.
+func (s *authService) Logout(sessionID uuid.UUID) error {
+	return s.sessionRepo.Revoke(sessionID)
 }
 
-func (s *authService) Logout(accessToken string) error {
-	return nil
+// LogoutAll revokes every session of the user, on every device.
+func (s *authService) LogoutAll(userID uuid.UUID) error {
+	return s.sessionRepo.RevokeAllByUserID(userID)
 }
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type refreshFixture struct {
+	service  *authService
+	sessions *fakeSessionRepo
+	tokens   *fakeRefreshTokenRepo
+	session  *domain.Session
+}
+
+// newRefreshFixture logs a user in and returns their first refresh token.
+func newRefreshFixture(t *testing.T) (*refreshFixture, string) {
+	t.Helper()
+	user := &domain.User{ID: uuid.New()}
+	session := &domain.Session{ID: uuid.New(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	f := &refreshFixture{
+		sessions: newFakeSessionRepo(session),
+		tokens:   newFakeRefreshTokenRepo(),
+		session:  session,
+	}
+	f.service = &authService{
+		userRepo:         fakeUserRepo{users: map[uuid.UUID]*domain.User{user.ID: user}},
+		sessionRepo:      f.sessions,
+		refreshTokenRepo: f.tokens,
+		cfg: configs.Config{JWT: configs.JWTConfig{
+			SecretKey:        "access-secret",
+			RefreshSecretKey: "refresh-secret",
+			RefreshGrace:     "10s",
+		}},
+	}
+	token, err := f.service.issueRefreshToken(session, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, token
+}
+
+func (f *refreshFixture) revoked(t *testing.T) bool {
+	t.Helper()
+	session, err := f.sessions.FindByID(f.session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session.RevokedAt != nil
+}
+
+func tokenID(t *testing.T, token string) uuid.UUID {
+	t.Helper()
+	claims, err := utils.ValidateToken(token, "refresh-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuid.MustParse(claims.ID)
+}
+
+func TestRefreshTokenConcurrentRefreshesShareOneSuccessor(t *testing.T) {
+	f, token := newRefreshFixture(t)
+
+	const clients = 8
+	results := make([]*RefreshResult, clients)
+	errs := make([]error, clients)
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = f.service.RefreshToken(token)
+		}(i)
+	}
+	wg.Wait()
+
+	var successor uuid.UUID
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("refresh %d: %v", i, err)
+		}
+		id := tokenID(t, results[i].RefreshToken)
+		if successor == uuid.Nil {
+			successor = id
+		} else if id != successor {
+			t.Fatalf("refresh %d got successor %s, others got %s", i, id, successor)
+		}
+	}
+	if f.revoked(t) {
+		t.Fatal("concurrent refreshes revoked the session")
+	}
+	if _, err := f.service.RefreshToken(results[0].RefreshToken); err != nil {
+		t.Fatalf("refreshing with the shared successor: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseAfterGracePeriodRevokesSession(t *testing.T) {
+	f, token := newRefreshFixture(t)
+	if _, err := f.service.RefreshToken(token); err != nil {
+		t.Fatal(err)
+	}
+	f.tokens.backdateUse(tokenID(t, token), time.Minute)
+
+	_, err := f.service.RefreshToken(token)
+	if err == nil || err.Error() != "AUTH_REFRESH_TOKEN_REUSED" {
+		t.Fatalf("reuse after the grace period: err = %v, want AUTH_REFRESH_TOKEN_REUSED", err)
+	}
+	if !f.revoked(t) {
+		t.Fatal("session still active after reuse")
+	}
+}
+
+func TestRefreshTokenReuseAfterSuccessorRotatedRevokesSession(t *testing.T) {
+	f, token := newRefreshFixture(t)
+	first, err := f.service.RefreshToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.service.RefreshToken(first.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	// Within the grace period, but the chain has already moved on: only a
+	// stolen copy can still be holding the first token.
+	_, err = f.service.RefreshToken(token)
+	if err == nil || err.Error() != "AUTH_REFRESH_TOKEN_REUSED" {
+		t.Fatalf("err = %v, want AUTH_REFRESH_TOKEN_REUSED", err)
+	}
+	if !f.revoked(t) {
+		t.Fatal("session still active after reuse")
+	}
+}
+
+func TestRefreshTokenWithoutGracePeriod(t *testing.T) {
+	f, token := newRefreshFixture(t)
+	f.service.cfg.JWT.RefreshGrace = "0s"
+	if _, err := f.service.RefreshToken(token); err != nil {
+		t.Fatal(err)
+	}
+	f.tokens.backdateUse(tokenID(t, token), time.Millisecond)
+
+	if _, err := f.service.RefreshToken(token); err == nil {
+		t.Fatal("second refresh succeeded with the grace period disabled")
+	}
+	if !f.revoked(t) {
+		t.Fatal("session still active after reuse")
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+const (
+	BulkFormatCSV  = "csv"
+	BulkFormatJSON = "json"
+)
+
+// BulkURLRow is one line of a bulk upload. CSV files use the JSON field names
+// as their header row; blank CSV cells are treated as absent. Create rows need
+// original_url; the other operations identify the link by id, or by
+// short_code plus an optional domain.
+type BulkURLRow struct {
+	ID          *string    `json:"id,omitempty"`
+	ShortCode   *string    `json:"short_code,omitempty"`
+	Domain      *string    `json:"domain,omitempty"`
+	CampaignID  *string    `json:"campaign_id,omitempty"`
+	OriginalURL *string    `json:"original_url,omitempty"`
+	CustomAlias *string    `json:"custom_alias,omitempty"`
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Password    *string    `json:"password,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	IsActive    *bool      `json:"is_active,omitempty"`
+}
+
+// bulkRowError marks a problem confined to a single row. The reader stays
+// usable and the row is reported as failed in the result file.
+type bulkRowError struct {
+	code string
+}
+
+func (e *bulkRowError) Error() string {
+	return e.code
+}
+
+// bulkRowReader streams rows out of an upload so that large files are never
+// held in memory. Next returns io.EOF after the last row.
+type bulkRowReader interface {
+	Next() (BulkURLRow, error)
+}
+
+func newBulkRowReader(format string, r io.Reader) (bulkRowReader, error) {
+	switch format {
+	case BulkFormatCSV:
+		return newCSVRowReader(r)
+	case BulkFormatJSON:
+		return newJSONRowReader(r)
+	default:
+		return nil, errors.New("BULK_UNSUPPORTED_FORMAT")
+	}
+}
+
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("BULK_EMPTY_FILE")
+		}
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name != "" {
+			columns[name] = i
+		}
+	}
+	return &csvRowReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvRowReader) Next() (BulkURLRow, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return BulkURLRow{}, err
+	}
+
+	cell := func(name string) *string {
+		i, ok := r.columns[name]
+		if !ok || i >= len(record) {
+			return nil
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	row := BulkURLRow{
+		ID:          cell("id"),
+		ShortCode:   cell("short_code"),
+		Domain:      cell("domain"),
+		CampaignID:  cell("campaign_id"),
+		OriginalURL: cell("original_url"),
+		CustomAlias: cell("custom_alias"),
+		Title:       cell("title"),
+		Description: cell("description"),
+		Password:    cell("password"),
+	}
+
+	if value := cell("expires_at"); value != nil {
+		expiresAt, err := parseBulkTime(*value)
+		if err != nil {
+			return row, &bulkRowError{code: "ROW_INVALID_EXPIRES_AT"}
+		}
+		row.ExpiresAt = &expiresAt
+	}
+	if value := cell("is_active"); value != nil {
+		isActive, err := strconv.ParseBool(*value)
+		if err != nil {
+			return row, &bulkRowError{code: "ROW_INVALID_IS_ACTIVE"}
+		}
+		row.IsActive = &isActive
+	}
+	return row, nil
+}
+
+// parseBulkTime accepts RFC 3339 timestamps and plain dates, which is what
+// spreadsheet exports usually produce. Plain dates are read as UTC midnight.
+func parseBulkTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+type jsonRowReader struct {
+	decoder *json.Decoder
+}
+
+func newJSONRowReader(r io.Reader) (*jsonRowReader, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("BULK_EMPTY_FILE")
+		}
+		return nil, fmt.Errorf("reading JSON: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("JSON upload must be an array of objects")
+	}
+	return &jsonRowReader{decoder: decoder}, nil
+}
+
+func (r *jsonRowReader) Next() (BulkURLRow, error) {
+	if !r.decoder.More() {
+		if _, err := r.decoder.Token(); err != nil {
+			return BulkURLRow{}, fmt.Errorf("reading JSON: %w", err)
+		}
+		return BulkURLRow{}, io.EOF
+	}
+
+	var row BulkURLRow
+	if err := r.decoder.Decode(&row); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return row, &bulkRowError{code: "ROW_INVALID_" + strings.ToUpper(typeErr.Field)}
+		}
+		var timeErr *time.ParseError
+		if errors.As(err, &timeErr) {
+			return row, &bulkRowError{code: "ROW_INVALID_EXPIRES_AT"}
+		}
+		return row, fmt.Errorf("reading JSON: %w", err)
+	}
+	return row, nil
+}
+
+// requireColumns rejects a CSV upload whose header cannot satisfy the
+// operation, so a wrong template fails at upload instead of on every row.
+func (r *csvRowReader) requireColumns(operation string) error {
+	if operation == domain.BulkOperationCreate {
+		if _, ok := r.columns["original_url"]; !ok {
+			return &BulkFileError{Reason: "missing column: original_url"}
+		}
+		return nil
+	}
+	_, hasID := r.columns["id"]
+	_, hasShortCode := r.columns["short_code"]
+	if !hasID && !hasShortCode {
+		return &BulkFileError{Reason: "missing column: id or short_code"}
+	}
+	return nil
+}
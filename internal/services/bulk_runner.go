@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// bulkCheckpointRows and bulkCheckpointInterval bound how much work is
+	// replayed if the process dies mid-job: progress is saved, and the job's
+	// heartbeat refreshed, whenever either limit is reached.
+	bulkCheckpointRows     = 100
+	bulkCheckpointInterval = 5 * time.Second
+
+	// bulkStaleAfter is how long a processing job may go without a checkpoint
+	// before another runner treats it as abandoned and picks it up.
+	bulkStaleAfter = 5 * time.Minute
+)
+
+var bulkErrorCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+var bulkResultHeader = []string{"row", "status", "url_id", "short_code", "short_url", "error"}
+
+type BulkRunner interface {
+	Start()
+	Notify()
+	Shutdown(ctx context.Context) error
+}
+
+// bulkRunner applies queued bulk operations one at a time. Jobs are claimed
+// from the database rather than handed over in memory, so uploads survive a
+// restart. Unless uploads are on shared storage, each instance only claims
+// the jobs it accepted.
+type bulkRunner struct {
+	bulkRepo     domain.BulkOperationRepository
+	urls         *urlService
+	cfg          configs.Config
+	node         string
+	pollInterval time.Duration
+
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type bulkRowResult struct {
+	url      *domain.URL
+	shortURL string
+	err      error
+}
+
+func NewBulkRunner(bulkRepo domain.BulkOperationRepository, urlRepo domain.URLRepository, domainRepo domain.DomainRepository, userRepo domain.UserRepository, campaignRepo domain.CampaignRepository, tagRepo domain.TagRepository, folderRepo domain.FolderRepository, cfg configs.Config) BulkRunner {
+	pollInterval, err := time.ParseDuration(cfg.Bulk.PollInterval)
+	if err != nil || pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	return &bulkRunner{
+		bulkRepo:     bulkRepo,
+		urls:         newURLService(urlRepo, domainRepo, userRepo, campaignRepo, tagRepo, folderRepo, cfg),
+		cfg:          cfg,
+		node:         cfg.Bulk.Node(),
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (r *bulkRunner) Start() {
+	go r.run()
+}
+
+// Notify wakes the runner early after a new upload instead of waiting for
+// the next poll.
+func (r *bulkRunner) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Shutdown stops the runner between rows. A job that is interrupted is
+// checkpointed and put back in the queue, so it resumes where it stopped.
+func (r *bulkRunner) Shutdown(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.stop) })
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *bulkRunner) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for !r.stopping() {
+			op, err := r.bulkRepo.ClaimNext(r.node, time.Now().Add(-bulkStaleAfter))
+			if err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Printf("Error claiming bulk operation: %v", err)
+				}
+				break
+			}
+			r.process(op)
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *bulkRunner) stopping() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *bulkRunner) process(op *domain.BulkOperation) {
+	if op.FilePath == nil {
+		r.fail(op, "input file is missing")
+		return
+	}
+
+	in, err := os.Open(*op.FilePath)
+	if err != nil {
+		r.fail(op, "input file could not be opened")
+		log.Printf("Error opening input of bulk operation %s: %v", op.ID, err)
+		return
+	}
+	defer in.Close()
+
+	reader, err := newBulkRowReader(op.FileFormat, in)
+	if err != nil {
+		r.fail(op, err.Error())
+		return
+	}
+
+	// Rows before ProcessedCount were applied by an earlier run of this job.
+	for i := 0; i < op.ProcessedCount; i++ {
+		if _, err := reader.Next(); err != nil && !isBulkRowError(err) {
+			r.fail(op, fmt.Sprintf("input file changed while resuming: %v", err))
+			return
+		}
+	}
+
+	resultPath := filepath.Join(filepath.Dir(*op.FilePath), "result.csv")
+	out, err := openBulkResult(resultPath, op.ProcessedCount > 0, op.ResultFileSize)
+	if err != nil {
+		r.fail(op, "result file could not be opened")
+		log.Printf("Error opening result of bulk operation %s: %v", op.ID, err)
+		return
+	}
+	defer out.Close()
+	op.ResultFilePath = &resultPath
+
+	writer := csv.NewWriter(out)
+	if op.ProcessedCount == 0 {
+		writer.Write(bulkResultHeader)
+	}
+	result := &bulkResultFile{file: out, writer: writer}
+
+	lastCheckpoint := time.Now()
+	pending := 0
+	for {
+		if r.stopping() {
+			op.Status = domain.BulkStatusPending
+			r.checkpoint(op, result)
+			return
+		}
+
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !isBulkRowError(err) {
+			r.checkpoint(op, result)
+			r.fail(op, fmt.Sprintf("row %d: %v", op.ProcessedCount+1, err))
+			return
+		}
+
+		rowResult := bulkRowResult{err: err}
+		if err == nil {
+			rowResult = r.applyRow(op, row)
+		}
+		writer.Write(r.resultRecord(op.ProcessedCount+1, row, rowResult))
+
+		op.ProcessedCount++
+		if rowResult.err != nil {
+			op.FailedCount++
+		} else {
+			op.SuccessCount++
+		}
+
+		pending++
+		if pending >= bulkCheckpointRows || time.Since(lastCheckpoint) >= bulkCheckpointInterval {
+			if err := r.checkpoint(op, result); err != nil {
+				log.Printf("Error saving progress of bulk operation %s: %v", op.ID, err)
+			}
+			pending = 0
+			lastCheckpoint = time.Now()
+		}
+	}
+
+	now := time.Now()
+	op.Status = domain.BulkStatusCompleted
+	op.CompletedAt = &now
+	if op.FailedCount > 0 {
+		details := fmt.Sprintf("%d of %d rows failed; see the result file for details", op.FailedCount, op.ProcessedCount)
+		op.ErrorDetails = &details
+	}
+	input := op.FilePath
+	op.FilePath = nil
+	if err := r.checkpoint(op, result); err != nil {
+		log.Printf("Error completing bulk operation %s: %v", op.ID, err)
+		return
+	}
+	removeBulkInput(op, input)
+}
+
+// bulkResultFile is the result CSV of the job being processed.
+type bulkResultFile struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// openBulkResult opens the result file of a job. A resumed job truncates it
+// to its size at the last checkpoint: the csv.Writer may have flushed rows
+// after that, and those rows are about to be processed and written again.
+func openBulkResult(path string, resume bool, checkpointSize int64) (*os.File, error) {
+	if !resume {
+		return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	info, err := out.Stat()
+	if err == nil && info.Size() < checkpointSize {
+		err = fmt.Errorf("result file has %d bytes, %d were checkpointed", info.Size(), checkpointSize)
+	}
+	if err == nil {
+		err = out.Truncate(checkpointSize)
+	}
+	if err == nil {
+		_, err = out.Seek(checkpointSize, io.SeekStart)
+	}
+	if err != nil {
+		out.Close()
+		return nil, err
+	}
+	return out, nil
+}
+
+// checkpoint flushes the result rows written so far and then saves the
+// counters together with the file size, so the file never lags behind the
+// saved progress and a resumed job knows where the saved rows end.
+func (r *bulkRunner) checkpoint(op *domain.BulkOperation, result *bulkResultFile) error {
+	result.writer.Flush()
+	if err := result.writer.Error(); err != nil {
+		return err
+	}
+	size, err := result.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	op.ResultFileSize = size
+	return r.bulkRepo.Update(op)
+}
+
+func (r *bulkRunner) fail(op *domain.BulkOperation, reason string) {
+	now := time.Now()
+	op.Status = domain.BulkStatusFailed
+	op.ErrorDetails = &reason
+	op.CompletedAt = &now
+	input := op.FilePath
+	op.FilePath = nil
+	if err := r.bulkRepo.Update(op); err != nil {
+		log.Printf("Error marking bulk operation %s as failed: %v", op.ID, err)
+		return
+	}
+	removeBulkInput(op, input)
+}
+
+// removeBulkInput deletes the upload of a job that has ended. It is removed
+// only once the job is saved as ended, so that a crash in between leaves a
+// stray file rather than a job that cannot resume.
+func removeBulkInput(op *domain.BulkOperation, input *string) {
+	if input == nil {
+		return
+	}
+	if err := os.Remove(*input); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Error removing input of bulk operation %s: %v", op.ID, err)
+	}
+}
+
+func (r *bulkRunner) applyRow(op *domain.BulkOperation, row BulkURLRow) bulkRowResult {
+	if op.OperationType == domain.BulkOperationCreate {
+		return r.createRow(op.UserID, row)
+	}
+
+	url, err := r.findRowTarget(op.UserID, row)
+	if err != nil {
+		return bulkRowResult{err: err}
+	}
+
+	switch op.OperationType {
+	case domain.BulkOperationUpdate:
+		url, err = r.urls.UpdateURL(url.ID, op.UserID, request.UpdateURLRequest{
+			Title:       row.Title,
+			Description: row.Description,
+			ExpiresAt:   row.ExpiresAt,
+			IsActive:    row.IsActive,
+		})
+	case domain.BulkOperationDeactivate:
+		isActive := false
+		url, err = r.urls.UpdateURL(url.ID, op.UserID, request.UpdateURLRequest{IsActive: &isActive})
+	case domain.BulkOperationDelete:
+		err = r.urls.DeleteURL(url.ID, op.UserID)
+	default:
+		err = errors.New("BULK_INVALID_OPERATION")
+	}
+	if err != nil {
+		return bulkRowResult{err: err}
+	}
+	return bulkRowResult{url: url, shortURL: utils.BuildShortURL(r.cfg.Server.BaseURL, url.DomainName(), url.ShortCode)}
+}
+
+func (r *bulkRunner) createRow(userID uuid.UUID, row BulkURLRow) bulkRowResult {
+	if row.OriginalURL == nil {
+		return bulkRowResult{err: errors.New("ROW_MISSING_ORIGINAL_URL")}
+	}
+	if !utils.IsValidURL(*row.OriginalURL) {
+		return bulkRowResult{err: errors.New("ROW_INVALID_ORIGINAL_URL")}
+	}
+
+	var campaignID *uuid.UUID
+	if row.CampaignID != nil {
+		id, err := uuid.Parse(*row.CampaignID)
+		if err != nil {
+			return bulkRowResult{err: errors.New("ROW_INVALID_CAMPAIGN_ID")}
+		}
+		campaignID = &id
+	}
+
+	url, shortURL, err := r.urls.createURL(userID, request.CreateURLRequest{
+		OriginalURL: *row.OriginalURL,
+		CustomAlias: row.CustomAlias,
+		Domain:      row.Domain,
+		CampaignID:  campaignID,
+		Title:       row.Title,
+		Description: row.Description,
+		ExpiresAt:   row.ExpiresAt,
+		Password:    row.Password,
+	})
+	if err != nil {
+		return bulkRowResult{err: err}
+	}
+	return bulkRowResult{url: url, shortURL: shortURL}
+}
+
+// findRowTarget resolves the link a non-create row refers to, by id or by
+// short code on the default or one of the user's domains.
+func (r *bulkRunner) findRowTarget(userID uuid.UUID, row BulkURLRow) (*domain.URL, error) {
+	var url *domain.URL
+	switch {
+	case row.ID != nil:
+		urlID, err := uuid.Parse(*row.ID)
+		if err != nil {
+			return nil, errors.New("ROW_INVALID_ID")
+		}
+		url, err = r.urls.urlRepo.FindByID(urlID)
+		if err != nil {
+			return nil, notFoundAs(err, "URL_NOT_FOUND")
+		}
+	case row.ShortCode != nil:
+		var domainID *uuid.UUID
+		if row.Domain != nil {
+			d, err := r.urls.domainRepo.FindByUserIDAndDomainName(userID, NormalizeDomainName(*row.Domain))
+			if err != nil {
+				return nil, notFoundAs(err, "URL_DOMAIN_NOT_FOUND")
+			}
+			domainID = &d.ID
+		}
+		found, err := r.urls.urlRepo.FindByShortCode(domainID, *row.ShortCode)
+		if err != nil {
+			return nil, notFoundAs(err, "URL_NOT_FOUND")
+		}
+		// The lookup may be answered by the cache, whose copies leave out
+		// the password hash; edits start from the stored row.
+		url, err = r.urls.urlRepo.FindByID(found.ID)
+		if err != nil {
+			return nil, notFoundAs(err, "URL_NOT_FOUND")
+		}
+	default:
+		return nil, errors.New("ROW_MISSING_ID_OR_SHORT_CODE")
+	}
+
+	if url.UserID == nil || *url.UserID != userID {
+		return nil, errors.New("URL_FORBIDDEN")
+	}
+	return url, nil
+}
+
+// resultRecord returns the result row of one input row. Short codes come
+// from the uploaded file, so text cells are escaped as in click exports.
+func (r *bulkRunner) resultRecord(rowNumber int, row BulkURLRow, result bulkRowResult) []string {
+	record := make([]string, len(bulkResultHeader))
+	record[0] = strconv.Itoa(rowNumber)
+	if result.err != nil {
+		record[1] = "failed"
+		record[5] = csvText(bulkErrorCode(result.err))
+		if row.ShortCode != nil {
+			record[3] = csvText(*row.ShortCode)
+		}
+		return record
+	}
+
+	record[1] = "ok"
+	record[2] = result.url.ID.String()
+	record[3] = csvText(result.url.ShortCode)
+	record[4] = csvText(result.shortURL)
+	return record
+}
+
+// bulkErrorCode keeps the service's error codes and hides anything else,
+// such as driver errors, behind a generic code.
+func bulkErrorCode(err error) string {
+	if bulkErrorCodePattern.MatchString(err.Error()) {
+		return err.Error()
+	}
+	log.Printf("Bulk row failed: %v", err)
+	return "INTERNAL_ERROR"
+}
+
+func notFoundAs(err error, code string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(code)
+	}
+	return err
+}
+
+func isBulkRowError(err error) bool {
+	var rowErr *bulkRowError
+	return errors.As(err, &rowErr)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+func newTestBulkRunner(bulkRepo domain.BulkOperationRepository, urlRepo domain.URLRepository, userRepo domain.UserRepository) *bulkRunner {
+	cfg := configs.Config{}
+	cfg.Server.BaseURL = "https://sho.rt"
+	return NewBulkRunner(bulkRepo, urlRepo, newFakeDomainRepo(), userRepo, nil, nil, nil, cfg).(*bulkRunner)
+}
+
+func writeBulkInput(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readResultRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("result file is not valid CSV: %v\n%s", err, data)
+	}
+	return rows
+}
+
+func TestBulkRunnerResumeDropsRowsWrittenAfterCheckpoint(t *testing.T) {
+	userID := uuid.New()
+	codes := []string{"aaa", "bbb", "ccc", "ddd"}
+	links := make([]*domain.URL, len(codes))
+	for i, code := range codes {
+		links[i] = &domain.URL{UserID: &userID, ShortCode: code, IsActive: true}
+	}
+	urlRepo := newFakeURLRepo(links...)
+	bulkRepo := newFakeBulkRepo()
+	runner := newTestBulkRunner(bulkRepo, urlRepo, nil)
+
+	input := writeBulkInput(t, "input.csv", "short_code\n"+strings.Join(codes, "\n")+"\n")
+	resultPath := filepath.Join(filepath.Dir(input), "result.csv")
+
+	// The first run checkpointed two rows, then the csv.Writer flushed row
+	// 3 and half of row 4 on its own before the process died.
+	var checkpointed bytes.Buffer
+	w := csv.NewWriter(&checkpointed)
+	w.Write(bulkResultHeader)
+	w.Write([]string{"1", "ok", links[0].ID.String(), "aaa", "https://sho.rt/aaa", ""})
+	w.Write([]string{"2", "ok", links[1].ID.String(), "bbb", "https://sho.rt/bbb", ""})
+	w.Flush()
+	stale := checkpointed.String() + "3,ok," + links[2].ID.String() + ",ccc,https://sho.rt/ccc,\n4,o"
+	if err := os.WriteFile(resultPath, []byte(stale), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	op := &domain.BulkOperation{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OperationType:  domain.BulkOperationDeactivate,
+		FileFormat:     BulkFormatCSV,
+		TotalCount:     4,
+		ProcessedCount: 2,
+		SuccessCount:   2,
+		Status:         domain.BulkStatusProcessing,
+		FilePath:       &input,
+		ResultFileSize: int64(checkpointed.Len()),
+	}
+	runner.process(op)
+
+	rows := readResultRows(t, resultPath)
+	if len(rows) != 5 {
+		t.Fatalf("result has %d lines, want header and 4 rows:\n%v", len(rows), rows)
+	}
+	for i, row := range rows[1:] {
+		if row[0] != []string{"1", "2", "3", "4"}[i] || row[1] != "ok" || row[3] != codes[i] {
+			t.Errorf("result row %d = %v", i+1, row)
+		}
+	}
+
+	saved, _ := bulkRepo.FindByID(op.ID)
+	if saved.Status != domain.BulkStatusCompleted || saved.ProcessedCount != 4 || saved.SuccessCount != 4 {
+		t.Errorf("saved job = %s, %d processed, %d succeeded", saved.Status, saved.ProcessedCount, saved.SuccessCount)
+	}
+	info, _ := os.Stat(resultPath)
+	if saved.ResultFileSize != info.Size() {
+		t.Errorf("checkpointed size %d, file has %d bytes", saved.ResultFileSize, info.Size())
+	}
+	if urlRepo.get(links[2].ID).IsActive || urlRepo.get(links[3].ID).IsActive {
+		t.Error("rows after the checkpoint were not applied")
+	}
+}
+
+func TestBulkRunnerRemovesInputWhenJobEnds(t *testing.T) {
+	userID := uuid.New()
+	urlRepo := newFakeURLRepo()
+	users := fakeUserRepo{users: map[uuid.UUID]*domain.User{userID: {ID: userID, PlanType: "free"}}}
+	bulkRepo := newFakeBulkRepo()
+	runner := newTestBulkRunner(bulkRepo, urlRepo, users)
+
+	input := writeBulkInput(t, "input.csv", "original_url,password\nhttps://example.com/a,hunter22\nnot a url,\n")
+	op := &domain.BulkOperation{
+		ID:            uuid.New(),
+		UserID:        userID,
+		OperationType: domain.BulkOperationCreate,
+		FileFormat:    BulkFormatCSV,
+		TotalCount:    2,
+		Status:        domain.BulkStatusProcessing,
+		FilePath:      &input,
+	}
+	runner.process(op)
+
+	if _, err := os.Stat(input); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("input with plain-text passwords still on disk: %v", err)
+	}
+	saved, _ := bulkRepo.FindByID(op.ID)
+	if saved.FilePath != nil {
+		t.Errorf("saved job still points at its input %q", *saved.FilePath)
+	}
+	if saved.Status != domain.BulkStatusCompleted || saved.SuccessCount != 1 || saved.FailedCount != 1 {
+		t.Errorf("saved job = %s, %d succeeded, %d failed", saved.Status, saved.SuccessCount, saved.FailedCount)
+	}
+
+	rows := readResultRows(t, *saved.ResultFilePath)
+	if len(rows) != 3 || rows[1][1] != "ok" || rows[2][5] != "ROW_INVALID_ORIGINAL_URL" {
+		t.Errorf("result rows = %v", rows)
+	}
+	created := urlRepo.get(uuid.MustParse(rows[1][2]))
+	if created == nil || created.PasswordHash == nil || *created.PasswordHash == "hunter22" {
+		t.Error("created link is missing its hashed password")
+	}
+}
+
+func TestBulkRunnerRemovesInputOfFailedJob(t *testing.T) {
+	bulkRepo := newFakeBulkRepo()
+	runner := newTestBulkRunner(bulkRepo, newFakeURLRepo(), nil)
+
+	input := writeBulkInput(t, "input.json", `{"not": "an array"}`)
+	op := &domain.BulkOperation{ID: uuid.New(), OperationType: domain.BulkOperationDelete, FileFormat: BulkFormatJSON, FilePath: &input}
+	runner.process(op)
+
+	saved, _ := bulkRepo.FindByID(op.ID)
+	if saved.Status != domain.BulkStatusFailed {
+		t.Fatalf("status = %s, want failed", saved.Status)
+	}
+	if _, err := os.Stat(input); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("input of failed job still on disk: %v", err)
+	}
+}
+
+func TestBulkConfigNodePinsJobsUnlessStorageIsShared(t *testing.T) {
+	if node := (configs.BulkConfig{NodeID: "worker-1"}).Node(); node != "worker-1" {
+		t.Errorf("Node() = %q, want worker-1", node)
+	}
+	if node := (configs.BulkConfig{}).Node(); node == "" {
+		t.Error("Node() without NodeID should fall back to the host name")
+	}
+	if node := (configs.BulkConfig{NodeID: "worker-1", SharedStorage: true}).Node(); node != "" {
+		t.Errorf("Node() with shared storage = %q, want any node", node)
+	}
+}
+
+func TestBulkResultEscapesFormulaCells(t *testing.T) {
+	runner := newTestBulkRunner(newFakeBulkRepo(), newFakeURLRepo(), fakeUserRepo{})
+	formula := `=HYPERLINK("https://evil.example","open")`
+
+	failed := runner.resultRecord(3, BulkURLRow{ShortCode: &formula}, bulkRowResult{err: errors.New("URL_NOT_FOUND")})
+	if failed[3] != "'"+formula {
+		t.Errorf("short code of a failed row = %q, want it escaped", failed[3])
+	}
+	if failed[5] != "URL_NOT_FOUND" {
+		t.Errorf("error = %q", failed[5])
+	}
+
+	url := &domain.URL{ID: uuid.New(), ShortCode: "-sale"}
+	ok := runner.resultRecord(4, BulkURLRow{}, bulkRowResult{url: url, shortURL: "https://sho.rt/-sale"})
+	if ok[3] != "'-sale" || ok[4] != "https://sho.rt/-sale" {
+		t.Errorf("record = %q", ok)
+	}
+}
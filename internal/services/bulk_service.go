@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BulkFileError reports an upload that cannot be read at all, as opposed to
+// individual bad rows, which are reported in the result file.
+type BulkFileError struct {
+	Reason string
+}
+
+func (e *BulkFileError) Error() string {
+	return "BULK_INVALID_FILE"
+}
+
+type BulkService interface {
+	SubmitURLOperation(userID uuid.UUID, operation, format string, file io.Reader) (*domain.BulkOperation, error)
+	GetOperation(operationID, userID uuid.UUID) (*domain.BulkOperation, error)
+	GetResultFile(operationID, userID uuid.UUID) (string, error)
+}
+
+type bulkService struct {
+	bulkRepo domain.BulkOperationRepository
+	runner   BulkRunner
+	cfg      configs.Config
+}
+
+func NewBulkService(bulkRepo domain.BulkOperationRepository, runner BulkRunner, cfg configs.Config) BulkService {
+	return &bulkService{bulkRepo: bulkRepo, runner: runner, cfg: cfg}
+}
+
+// SubmitURLOperation stores the upload, checks that it parses and is within
+// the row limit, and queues it for the runner. Rows are not applied here.
+// The upload may hold link passwords in plain text, so the runner deletes it
+// as soon as the job ends.
+func (s *bulkService) SubmitURLOperation(userID uuid.UUID, operation, format string, file io.Reader) (*domain.BulkOperation, error) {
+	switch operation {
+	case domain.BulkOperationCreate, domain.BulkOperationUpdate, domain.BulkOperationDeactivate, domain.BulkOperationDelete:
+	default:
+		return nil, errors.New("BULK_INVALID_OPERATION")
+	}
+	if format != BulkFormatCSV && format != BulkFormatJSON {
+		return nil, errors.New("BULK_UNSUPPORTED_FORMAT")
+	}
+
+	operationID := uuid.New()
+	dir := filepath.Join(s.cfg.Bulk.StorageDir, operationID.String())
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	inputPath := filepath.Join(dir, "input."+format)
+	totalCount, err := s.saveUpload(inputPath, operation, format, file)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	op := &domain.BulkOperation{
+		ID:            operationID,
+		UserID:        userID,
+		OperationType: operation,
+		FileFormat:    format,
+		TotalCount:    totalCount,
+		Status:        domain.BulkStatusPending,
+		FilePath:      &inputPath,
+	}
+	if node := s.cfg.Bulk.Node(); node != "" {
+		op.NodeID = &node
+	}
+	if err := s.bulkRepo.Store(op); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	s.runner.Notify()
+	return op, nil
+}
+
+func (s *bulkService) saveUpload(path, operation, format string, file io.Reader) (int, error) {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(out, io.LimitReader(file, s.cfg.Bulk.MaxFileSize+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if written > s.cfg.Bulk.MaxFileSize {
+		return 0, errors.New("BULK_FILE_TOO_LARGE")
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	reader, err := newBulkRowReader(format, in)
+	if err != nil {
+		return 0, asBulkFileError(err)
+	}
+	if csvReader, ok := reader.(*csvRowReader); ok {
+		if err := csvReader.requireColumns(operation); err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	for {
+		_, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !isBulkRowError(err) {
+			return 0, asBulkFileError(fmt.Errorf("row %d: %w", count+1, err))
+		}
+		count++
+		if count > s.cfg.Bulk.MaxRows {
+			return 0, errors.New("BULK_TOO_MANY_ROWS")
+		}
+	}
+	if count == 0 {
+		return 0, errors.New("BULK_EMPTY_FILE")
+	}
+	return count, nil
+}
+
+func asBulkFileError(err error) error {
+	var fileErr *BulkFileError
+	if errors.As(err, &fileErr) || err.Error() == "BULK_EMPTY_FILE" {
+		return err
+	}
+	return &BulkFileError{Reason: err.Error()}
+}
+
+func (s *bulkService) GetOperation(operationID, userID uuid.UUID) (*domain.BulkOperation, error) {
+	op, err := s.bulkRepo.FindByID(operationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("BULK_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if op.UserID != userID {
+		return nil, errors.New("BULK_FORBIDDEN")
+	}
+	return op, nil
+}
+
+// GetResultFile returns the path of the per-row result CSV once the job has
+// finished. Partial results of a running job are not served.
+func (s *bulkService) GetResultFile(operationID, userID uuid.UUID) (string, error) {
+	op, err := s.GetOperation(operationID, userID)
+	if err != nil {
+		return "", err
+	}
+	if op.ResultFilePath == nil || (op.Status != domain.BulkStatusCompleted && op.Status != domain.BulkStatusFailed) {
+		return "", errors.New("BULK_RESULT_NOT_READY")
+	}
+	return *op.ResultFilePath, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CampaignService interface {
+	CreateCampaign(userID uuid.UUID, req request.CreateCampaignRequest) (*domain.Campaign, error)
+	GetUserCampaigns(userID uuid.UUID) ([]domain.Campaign, error)
+	GetCampaign(campaignID, userID uuid.UUID) (*domain.Campaign, error)
+	UpdateCampaign(campaignID, userID uuid.UUID, req request.UpdateCampaignRequest) (*domain.Campaign, error)
+	DeleteCampaign(campaignID, userID uuid.UUID) error
+}
+
+type campaignService struct {
+	campaignRepo domain.CampaignRepository
+}
+
+func NewCampaignService(campaignRepo domain.CampaignRepository) CampaignService {
+	return &campaignService{campaignRepo: campaignRepo}
+}
+
+func (s *campaignService) CreateCampaign(userID uuid.UUID, req request.CreateCampaignRequest) (*domain.Campaign, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkNameAvailable(userID, name, nil); err != nil {
+		return nil, err
+	}
+
+	campaign := &domain.Campaign{
+		UserID:      userID,
+		Name:        name,
+		Description: req.Description,
+		UTM:         campaignUTM(name, req.UTM),
+	}
+	if err := s.campaignRepo.Store(campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (s *campaignService) GetUserCampaigns(userID uuid.UUID) ([]domain.Campaign, error) {
+	return s.campaignRepo.FindAllByUserID(userID)
+}
+
+func (s *campaignService) GetCampaign(campaignID, userID uuid.UUID) (*domain.Campaign, error) {
+	campaign, err := s.campaignRepo.FindByID(campaignID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("CAMPAIGN_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if campaign.UserID != userID {
+		return nil, errors.New("CAMPAIGN_FORBIDDEN")
+	}
+	return campaign, nil
+}
+
+// UpdateCampaign changes the campaign only. Links created in it keep the UTM
+// parameters they were created with.
+func (s *campaignService) UpdateCampaign(campaignID, userID uuid.UUID, req request.UpdateCampaignRequest) (*domain.Campaign, error) {
+	campaign, err := s.GetCampaign(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := s.checkNameAvailable(userID, name, &campaign.ID); err != nil {
+			return nil, err
+		}
+		campaign.Name = name
+	}
+	if req.Description != nil {
+		campaign.Description = req.Description
+	}
+	if req.UTM != nil {
+		campaign.UTM = campaignUTM(campaign.Name, *req.UTM)
+	}
+
+	if err := s.campaignRepo.Update(campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// DeleteCampaign removes the campaign. Its links stay, tagged as they were,
+// but are no longer grouped.
+func (s *campaignService) DeleteCampaign(campaignID, userID uuid.UUID) error {
+	campaign, err := s.GetCampaign(campaignID, userID)
+	if err != nil {
+		return err
+	}
+	return s.campaignRepo.Delete(campaign)
+}
+
+// checkNameAvailable reports CAMPAIGN_ALREADY_EXISTS when another of the
+// user's campaigns has the name, ignoring case.
+func (s *campaignService) checkNameAvailable(userID uuid.UUID, name string, self *uuid.UUID) error {
+	if name == "" {
+		return errors.New("CAMPAIGN_NAME_REQUIRED")
+	}
+	existing, err := s.campaignRepo.FindByUserIDAndName(userID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if self != nil && existing.ID == *self {
+		return nil
+	}
+	return errors.New("CAMPAIGN_ALREADY_EXISTS")
+}
+
+// campaignUTM returns the campaign's parameters, with utm_campaign defaulting
+// to the campaign name.
+func campaignUTM(name string, req request.UTMRequest) domain.UTMParams {
+	utm := normalizeUTM(req)
+	if utm.Campaign == "" {
+		utm.Campaign = name
+	}
+	return utm
+}
+
+// normalizeUTM trims every value and lowercases source and medium, whose
+// differences in case are the most common way reports get split.
+func normalizeUTM(req request.UTMRequest) domain.UTMParams {
+	return domain.UTMParams{
+		Source:   strings.ToLower(strings.TrimSpace(req.Source)),
+		Medium:   strings.ToLower(strings.TrimSpace(req.Medium)),
+		Campaign: strings.TrimSpace(req.Campaign),
+		Term:     strings.TrimSpace(req.Term),
+		Content:  strings.TrimSpace(req.Content),
+	}
+}
+
+// utmQueryParams lists the set parameters in their conventional order.
+func utmQueryParams(utm domain.UTMParams) []utils.QueryParam {
+	var params []utils.QueryParam
+	for _, p := range []utils.QueryParam{
+		{Key: "utm_source", Value: utm.Source},
+		{Key: "utm_medium", Value: utm.Medium},
+		{Key: "utm_campaign", Value: utm.Campaign},
+		{Key: "utm_term", Value: utm.Term},
+		{Key: "utm_content", Value: utm.Content},
+	} {
+		if p.Value != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
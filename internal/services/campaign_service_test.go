@@ -0,0 +1,21 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+)
+
+func TestCampaignUTM(t *testing.T) {
+	got := campaignUTM("Spring Sale", request.UTMRequest{Source: " NewsLetter ", Medium: "EMAIL", Term: " shoes "})
+	want := domain.UTMParams{Source: "newsletter", Medium: "email", Campaign: "Spring Sale", Term: "shoes"}
+	if got != want {
+		t.Errorf("campaignUTM = %+v, want %+v", got, want)
+	}
+
+	got = campaignUTM("Spring Sale", request.UTMRequest{Campaign: " spring_2026 "})
+	if got.Campaign != "spring_2026" {
+		t.Errorf("Campaign = %q, want the requested spring_2026", got.Campaign)
+	}
+}
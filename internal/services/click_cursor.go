@@ -0,0 +1,35 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+type clickCursorPayload struct {
+	ClickedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func encodeClickCursor(last *domain.ClickLogEntry) string {
+	data, _ := json.Marshal(clickCursorPayload{ClickedAt: last.ClickedAt, ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeClickCursor(cursor string) (*domain.ClickCursor, error) {
+	invalid := errors.New("CLICK_INVALID_CURSOR")
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var payload clickCursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.ID == uuid.Nil || payload.ClickedAt.IsZero() {
+		return nil, invalid
+	}
+	return &domain.ClickCursor{ClickedAt: payload.ClickedAt, ID: payload.ID}, nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+)
+
+// Click export formats. JSON is a single array; NDJSON is one object per
+// line, which is easier to process as a stream.
+const (
+	ClickExportCSV    = "csv"
+	ClickExportNDJSON = "ndjson"
+	ClickExportJSON   = "json"
+)
+
+var clickExportCSVHeader = []string{
+	"id", "url_id", "short_code", "clicked_at", "ip_address", "user_agent", "referrer",
+	"referrer_domain", "referrer_category", "utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+	"variant", "country", "region", "city", "browser", "os", "device_type", "language", "is_unique",
+}
+
+// ClickExport is a validated export that has not been run yet, so the caller
+// can still report errors normally before the first byte is written.
+type ClickExport struct {
+	Format   string
+	Filename string
+
+	clickRepo domain.ClickRepository
+	filter    domain.ClickFilter
+	ipPrivacy string
+}
+
+func (e *ClickExport) ContentType() string {
+	switch e.Format {
+	case ClickExportNDJSON:
+		return "application/x-ndjson"
+	case ClickExportJSON:
+		return "application/json"
+	default:
+		return "text/csv"
+	}
+}
+
+// Write streams the matching clicks to w, oldest first. Cancelling ctx, for
+// example when the client goes away, stops the underlying query.
+func (e *ClickExport) Write(ctx context.Context, w io.Writer) error {
+	switch e.Format {
+	case ClickExportNDJSON:
+		enc := json.NewEncoder(w)
+		return e.stream(ctx, func(click response.ClickResponse) error {
+			return enc.Encode(click)
+		})
+
+	case ClickExportJSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		first := true
+		err := e.stream(ctx, func(click response.ClickResponse) error {
+			data, err := json.Marshal(click)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			_, err = w.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, "]\n")
+		return err
+
+	default:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(clickExportCSVHeader); err != nil {
+			return err
+		}
+		err := e.stream(ctx, func(click response.ClickResponse) error {
+			return cw.Write([]string{
+				click.ID.String(),
+				click.URLID.String(),
+				csvText(click.ShortCode),
+				click.ClickedAt.UTC().Format(time.RFC3339Nano),
+				csvText(click.IPAddress),
+				csvText(click.UserAgent),
+				csvText(click.Referrer),
+				csvText(click.ReferrerDomain),
+				csvText(click.ReferrerCategory),
+				csvText(click.UTMSource),
+				csvText(click.UTMMedium),
+				csvText(click.UTMCampaign),
+				csvText(click.UTMTerm),
+				csvText(click.UTMContent),
+				csvText(click.Variant),
+				csvText(click.Country),
+				csvText(click.Region),
+				csvText(click.City),
+				csvText(click.Browser),
+				csvText(click.OS),
+				csvText(click.DeviceType),
+				csvText(click.Language),
+				strconv.FormatBool(click.IsUnique),
+			})
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// csvText keeps a text cell from being read as a formula by spreadsheet
+// applications. Visitors control the user agent, referrer and UTM values,
+// so a cell starting with =, +, -, @, a tab or a carriage return is
+// prefixed with a single quote.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *ClickExport) stream(ctx context.Context, write func(response.ClickResponse) error) error {
+	return e.clickRepo.StreamLog(ctx, e.filter, func(entry *domain.ClickLogEntry) error {
+		entry.IPAddress = applyIPPrivacy(entry.IPAddress, e.ipPrivacy)
+		return write(response.ToClickResponse(entry))
+	})
+}
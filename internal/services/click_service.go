@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClickService serves individual clicks, as opposed to the aggregates of
+// AnalyticsService.
+type ClickService interface {
+	GetURLClicks(ctx context.Context, urlID, userID uuid.UUID, req request.ClickLogRequest) (*response.ClickLogResponse, error)
+	ExportURLClicks(urlID, userID uuid.UUID, req request.ClickExportRequest) (*ClickExport, error)
+	ExportUserClicks(userID uuid.UUID, req request.ClickExportRequest) (*ClickExport, error)
+}
+
+type clickService struct {
+	urlRepo   domain.URLRepository
+	clickRepo domain.ClickRepository
+	userRepo  domain.UserRepository
+}
+
+func NewClickService(urlRepo domain.URLRepository, clickRepo domain.ClickRepository, userRepo domain.UserRepository) ClickService {
+	return &clickService{urlRepo: urlRepo, clickRepo: clickRepo, userRepo: userRepo}
+}
+
+func (s *clickService) GetURLClicks(ctx context.Context, urlID, userID uuid.UUID, req request.ClickLogRequest) (*response.ClickLogResponse, error) {
+	url, err := s.findOwnedURL(urlID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rng, err := clickLogRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *domain.ClickCursor
+	if req.Cursor != "" {
+		if after, err = decodeClickCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	ipPrivacy, err := s.ipPrivacy(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.ClickFilter{URLID: &url.ID, Range: rng, Dimensions: clickDimensions(req.ClickFilterRequest)}
+	entries, err := s.clickRepo.FindLog(ctx, filter, after, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.ClickLogResponse{Clicks: []response.ClickResponse{}, Limit: req.Limit}
+	if len(entries) > req.Limit {
+		entries = entries[:req.Limit]
+		result.NextCursor = encodeClickCursor(&entries[len(entries)-1])
+	}
+	for i := range entries {
+		entries[i].IPAddress = applyIPPrivacy(entries[i].IPAddress, ipPrivacy)
+		result.Clicks = append(result.Clicks, response.ToClickResponse(&entries[i]))
+	}
+	return result, nil
+}
+
+func (s *clickService) ExportURLClicks(urlID, userID uuid.UUID, req request.ClickExportRequest) (*ClickExport, error) {
+	url, err := s.findOwnedURL(urlID, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter := domain.ClickFilter{URLID: &url.ID}
+	return s.prepareExport(filter, userID, req, "clicks_"+url.ShortCode)
+}
+
+func (s *clickService) ExportUserClicks(userID uuid.UUID, req request.ClickExportRequest) (*ClickExport, error) {
+	filter := domain.ClickFilter{UserID: &userID}
+	return s.prepareExport(filter, userID, req, "clicks")
+}
+
+func (s *clickService) prepareExport(filter domain.ClickFilter, userID uuid.UUID, req request.ClickExportRequest, name string) (*ClickExport, error) {
+	rng, err := clickLogRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	ipPrivacy, err := s.ipPrivacy(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Range = rng
+	filter.Dimensions = clickDimensions(req.ClickFilterRequest)
+	return &ClickExport{
+		Format:    req.Format,
+		Filename:  name + "_" + time.Now().UTC().Format("20060102") + "." + req.Format,
+		clickRepo: s.clickRepo,
+		filter:    filter,
+		ipPrivacy: ipPrivacy,
+	}, nil
+}
+
+func (s *clickService) findOwnedURL(urlID, userID uuid.UUID) (*domain.URL, error) {
+	url, err := s.urlRepo.FindByID(urlID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("URL_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if url.UserID == nil || *url.UserID != userID {
+		return nil, errors.New("URL_FORBIDDEN")
+	}
+	return url, nil
+}
+
+func (s *clickService) ipPrivacy(userID uuid.UUID) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return user.IPPrivacy, nil
+}
+
+// clickLogRange builds a range from optional bounds; a missing bound is open.
+func clickLogRange(from, to *time.Time) (domain.TimeRange, error) {
+	var rng domain.TimeRange
+	if from != nil {
+		rng.From = from.UTC()
+	}
+	if to != nil {
+		rng.To = to.UTC()
+	}
+	if from != nil && to != nil && !rng.From.Before(rng.To) {
+		return rng, errors.New("CLICK_INVALID_RANGE")
+	}
+	return rng, nil
+}
+
+// applyIPPrivacy reduces a visitor IP address according to the owner's
+// setting. Anything other than full or hidden is masked.
+func applyIPPrivacy(ip, privacy string) string {
+	switch privacy {
+	case domain.IPPrivacyFull:
+		return ip
+	case domain.IPPrivacyHidden:
+		return ""
+	default:
+		return utils.MaskIP(ip)
+	}
+}
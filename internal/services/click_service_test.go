@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+)
+
+func TestApplyIPPrivacy(t *testing.T) {
+	tests := []struct {
+		ip, privacy, want string
+	}{
+		{"203.0.113.77", domain.IPPrivacyFull, "203.0.113.77"},
+		{"203.0.113.77", domain.IPPrivacyMasked, "203.0.113.0"},
+		{"203.0.113.77", "", "203.0.113.0"},
+		{"::ffff:203.0.113.77", domain.IPPrivacyMasked, "203.0.113.0"},
+		{"2001:db8:85a3:8d3:1319:8a2e:370:7348", domain.IPPrivacyMasked, "2001:db8:85a3::"},
+		{"2001:db8:85a3:8d3:1319:8a2e:370:7348", domain.IPPrivacyFull, "2001:db8:85a3:8d3:1319:8a2e:370:7348"},
+		{"203.0.113.77", domain.IPPrivacyHidden, ""},
+		{"2001:db8::1", domain.IPPrivacyHidden, ""},
+		{"not an address", domain.IPPrivacyMasked, ""},
+	}
+	for _, tt := range tests {
+		if got := applyIPPrivacy(tt.ip, tt.privacy); got != tt.want {
+			t.Errorf("applyIPPrivacy(%q, %q) = %q, want %q", tt.ip, tt.privacy, got, tt.want)
+		}
+	}
+}
+
+func newClickServiceFixture(entries []domain.ClickLogEntry, privacy string) (ClickService, *domain.URL, uuid.UUID) {
+	userID := uuid.New()
+	url := &domain.URL{ID: uuid.New(), UserID: &userID, ShortCode: "abc"}
+	users := fakeUserRepo{users: map[uuid.UUID]*domain.User{userID: {ID: userID, IPPrivacy: privacy}}}
+	return NewClickService(newFakeURLRepo(url), &fakeClickLog{entries: entries}, users), url, userID
+}
+
+func TestGetURLClicksPagesThroughEveryClickOnce(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	var entries []domain.ClickLogEntry
+	for i := 0; i < 7; i++ {
+		entry := domain.ClickLogEntry{ShortCode: "abc"}
+		entry.ID = uuid.New()
+		// Pairs of clicks share a timestamp, so the cursor must break ties
+		// by ID.
+		entry.ClickedAt = base.Add(time.Duration(i/2) * time.Second)
+		entry.IPAddress = "203.0.113.77"
+		entries = append(entries, entry)
+	}
+	svc, url, userID := newClickServiceFixture(entries, domain.IPPrivacyMasked)
+
+	seen := map[uuid.UUID]bool{}
+	var last time.Time
+	req := request.ClickLogRequest{Limit: 3}
+	for page := 1; ; page++ {
+		result, err := svc.GetURLClicks(context.Background(), url.ID, userID, req)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		for _, click := range result.Clicks {
+			if seen[click.ID] {
+				t.Fatalf("page %d repeats click %s", page, click.ID)
+			}
+			if !last.IsZero() && click.ClickedAt.After(last) {
+				t.Fatalf("page %d is not newest first", page)
+			}
+			if click.IPAddress != "203.0.113.0" {
+				t.Fatalf("IP address %q was not masked", click.IPAddress)
+			}
+			seen[click.ID] = true
+			last = click.ClickedAt
+		}
+		if result.NextCursor == "" {
+			if len(result.Clicks) != 1 {
+				t.Fatalf("last page has %d clicks, want 1", len(result.Clicks))
+			}
+			break
+		}
+		if len(result.Clicks) != req.Limit {
+			t.Fatalf("page %d has %d clicks but a next cursor", page, len(result.Clicks))
+		}
+		req.Cursor = result.NextCursor
+	}
+	if len(seen) != len(entries) {
+		t.Fatalf("saw %d clicks, want %d", len(seen), len(entries))
+	}
+}
+
+func TestGetURLClicksRejectsBadCursor(t *testing.T) {
+	svc, url, userID := newClickServiceFixture(nil, domain.IPPrivacyMasked)
+	for _, cursor := range []string{"!!!", "e30", "bm90IGpzb24"} {
+		_, err := svc.GetURLClicks(context.Background(), url.ID, userID, request.ClickLogRequest{Limit: 10, Cursor: cursor})
+		if err == nil || err.Error() != "CLICK_INVALID_CURSOR" {
+			t.Errorf("cursor %q: err = %v, want CLICK_INVALID_CURSOR", cursor, err)
+		}
+	}
+}
+
+func TestClickCursorRoundTrip(t *testing.T) {
+	entry := &domain.ClickLogEntry{}
+	entry.ID = uuid.New()
+	entry.ClickedAt = time.Date(2025, 5, 1, 12, 0, 0, 123456000, time.UTC)
+
+	cursor, err := decodeClickCursor(encodeClickCursor(entry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.ID != entry.ID || !cursor.ClickedAt.Equal(entry.ClickedAt) {
+		t.Fatalf("cursor = %+v, want %s at %s", cursor, entry.ID, entry.ClickedAt)
+	}
+}
+
+func TestClickExportCSVNeutralisesFormulas(t *testing.T) {
+	entry := domain.ClickLogEntry{ShortCode: "abc"}
+	entry.ID = uuid.New()
+	entry.ClickedAt = time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	entry.IPAddress = "203.0.113.77"
+	entry.UserAgent = "=HYPERLINK(\"https://evil.example\",\"click\")"
+	entry.Referer = "+cmd|' /C calc'!A0"
+	entry.UTMSource = "-2+3"
+	entry.UTMMedium = "@SUM(A1:A2)"
+	entry.UTMCampaign = "\t=1+1"
+	entry.UTMTerm = "spring sale"
+	svc, url, userID := newClickServiceFixture([]domain.ClickLogEntry{entry}, domain.IPPrivacyHidden)
+
+	export, err := svc.ExportURLClicks(url.ID, userID, request.ClickExportRequest{Format: ClickExportCSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := export.Write(context.Background(), &out); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one row", len(records))
+	}
+
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	want := map[string]string{
+		"ip_address":   "",
+		"user_agent":   "'=HYPERLINK(\"https://evil.example\",\"click\")",
+		"referrer":     "'+cmd|' /C calc'!A0",
+		"utm_source":   "'-2+3",
+		"utm_medium":   "'@SUM(A1:A2)",
+		"utm_campaign": "'\t=1+1",
+		"utm_term":     "spring sale",
+		"clicked_at":   "2025-05-01T12:00:00Z",
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("%s = %q, want %q", column, row[column], value)
+		}
+	}
+}
+
+func TestClickExportNDJSONKeepsValues(t *testing.T) {
+	entry := domain.ClickLogEntry{ShortCode: "abc"}
+	entry.ID = uuid.New()
+	entry.UserAgent = "=1+1"
+	svc, url, userID := newClickServiceFixture([]domain.ClickLogEntry{entry}, domain.IPPrivacyMasked)
+
+	export, err := svc.ExportURLClicks(url.ID, userID, request.ClickExportRequest{Format: ClickExportNDJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := export.Write(context.Background(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`"user_agent":"=1+1"`)) {
+		t.Fatalf("NDJSON altered the value: %s", out.String())
+	}
+}
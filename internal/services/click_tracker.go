@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/geoip"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/referrer"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	clickWriteAttempts = 3
+	clickRetryBackoff  = 200 * time.Millisecond
+
+	// visitorCacheLimit bounds the per-worker memory of recently seen
+	// visitors. Anything evicted is still found through the clicks table.
+	visitorCacheLimit = 100000
+)
+
+// VisitorInfo is the part of an inbound redirect request that analytics care
+// about. Handlers copy it out of the HTTP request so nothing downstream holds
+// on to a recycled gin.Context.
+type VisitorInfo struct {
+	VisitorID string
+	IPAddress string
+	UserAgent string
+	Referer   string
+	// Language is the raw Accept-Language header.
+	Language string
+	// UTM holds the utm_* query parameters of the short-link request.
+	UTM domain.UTMParams
+}
+
+// ClickEvent is an immutable record of one successful redirect. Variant
+// names the destination chosen by the link's targeting rules. Location is
+// set when the redirect already resolved it, sparing a second GeoIP lookup.
+type ClickEvent struct {
+	URLID     uuid.UUID
+	Visitor   VisitorInfo
+	Variant   string
+	Location  *geoip.LocationData
+	ClickedAt time.Time
+}
+
+type ClickPipelineStats struct {
+	QueueDepth    int
+	QueueCapacity int
+	Workers       int
+	Enqueued      uint64
+	Backpressured uint64
+	Dropped       uint64
+	Written       uint64
+	Failed        uint64
+	Batches       uint64
+}
+
+type ClickTracker interface {
+	Start()
+	Track(event ClickEvent) error
+	Stats() ClickPipelineStats
+	Shutdown(ctx context.Context) error
+}
+
+// clickTracker buffers click events in bounded per-worker queues and writes
+// them in batches. Events are sharded by URL so every counter update for a
+// given link is issued by the same worker, which keeps row locks ordered.
+type clickTracker struct {
+	urlRepo        domain.URLRepository
+	clickRepo      domain.ClickRepository
+	geoipSvc       geoip.GeoIPService
+	identifier     VisitorIdentifier
+	uniqueWindow   time.Duration
+	shards         []chan ClickEvent
+	batchSize      int
+	flushInterval  time.Duration
+	enqueueTimeout time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+
+	enqueued      atomic.Uint64
+	backpressured atomic.Uint64
+	dropped       atomic.Uint64
+	written       atomic.Uint64
+	failed        atomic.Uint64
+	batches       atomic.Uint64
+}
+
+func NewClickTracker(urlRepo domain.URLRepository, clickRepo domain.ClickRepository, geoipSvc geoip.GeoIPService, identifier VisitorIdentifier, cfg configs.Config) ClickTracker {
+	workers := cfg.Clicks.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	queueSize := cfg.Clicks.QueueSize / workers
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	batchSize := cfg.Clicks.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	flushInterval, err := time.ParseDuration(cfg.Clicks.FlushInterval)
+	if err != nil || flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	enqueueTimeout, err := time.ParseDuration(cfg.Clicks.EnqueueTimeout)
+	if err != nil || enqueueTimeout < 0 {
+		enqueueTimeout = 0
+	}
+	uniqueWindow := cfg.Visitor.UniquePeriod()
+	if configured, err := time.ParseDuration(cfg.Visitor.UniqueWindow); err == nil && configured > uniqueWindow {
+		log.Printf("Unique window %s is longer than the daily salt rotation of hash mode, using %s", configured, uniqueWindow)
+	}
+
+	shards := make([]chan ClickEvent, workers)
+	for i := range shards {
+		shards[i] = make(chan ClickEvent, queueSize)
+	}
+
+	return &clickTracker{
+		urlRepo:        urlRepo,
+		clickRepo:      clickRepo,
+		geoipSvc:       geoipSvc,
+		identifier:     identifier,
+		uniqueWindow:   uniqueWindow,
+		shards:         shards,
+		batchSize:      batchSize,
+		flushInterval:  flushInterval,
+		enqueueTimeout: enqueueTimeout,
+	}
+}
+
+func (t *clickTracker) Start() {
+	for _, shard := range t.shards {
+		t.wg.Add(1)
+		go t.runWorker(shard)
+	}
+}
+
+// Track enqueues an event without touching the database. When the target
+// queue is full the caller waits up to the configured enqueue timeout before
+// the event is dropped and counted.
+func (t *clickTracker) Track(event ClickEvent) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		return errors.New("CLICK_TRACKER_CLOSED")
+	}
+
+	shard := t.shards[t.shardFor(event.URLID)]
+	select {
+	case shard <- event:
+		t.enqueued.Add(1)
+		return nil
+	default:
+	}
+
+	t.backpressured.Add(1)
+	if t.enqueueTimeout > 0 {
+		timer := time.NewTimer(t.enqueueTimeout)
+		defer timer.Stop()
+		select {
+		case shard <- event:
+			t.enqueued.Add(1)
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	t.dropped.Add(1)
+	return errors.New("CLICK_QUEUE_FULL")
+}
+
+func (t *clickTracker) Stats() ClickPipelineStats {
+	stats := ClickPipelineStats{
+		Workers:       len(t.shards),
+		Enqueued:      t.enqueued.Load(),
+		Backpressured: t.backpressured.Load(),
+		Dropped:       t.dropped.Load(),
+		Written:       t.written.Load(),
+		Failed:        t.failed.Load(),
+		Batches:       t.batches.Load(),
+	}
+	for _, shard := range t.shards {
+		stats.QueueDepth += len(shard)
+		stats.QueueCapacity += cap(shard)
+	}
+	return stats
+}
+
+// Shutdown stops accepting events and waits for the workers to drain and
+// flush everything that is already queued.
+func (t *clickTracker) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		for _, shard := range t.shards {
+			close(shard)
+		}
+	}
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *clickTracker) shardFor(urlID uuid.UUID) int {
+	h := fnv.New32a()
+	h.Write(urlID[:])
+	return int(h.Sum32() % uint32(len(t.shards)))
+}
+
+func (t *clickTracker) runWorker(events <-chan ClickEvent) {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	seen := make(map[domain.VisitorKey]time.Time)
+	batch := make([]ClickEvent, 0, t.batchSize)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.flush(batch, seen)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= t.batchSize {
+				t.flush(batch, seen)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			t.flush(batch, seen)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (t *clickTracker) flush(batch []ClickEvent, seen map[domain.VisitorKey]time.Time) {
+	if len(batch) == 0 {
+		return
+	}
+	t.batches.Add(1)
+
+	clicks := make([]domain.Click, len(batch))
+	for i, event := range batch {
+		clicks[i] = t.buildClick(event)
+	}
+	t.markUniqueClicks(clicks, seen)
+
+	deltas := make(map[uuid.UUID]*domain.ClickCountDelta)
+	for _, click := range clicks {
+		delta, ok := deltas[click.URLID]
+		if !ok {
+			delta = &domain.ClickCountDelta{URLID: click.URLID}
+			deltas[click.URLID] = delta
+		}
+		delta.Clicks++
+		if click.IsUnique {
+			delta.UniqueClicks++
+		}
+		if click.ClickedAt.After(delta.LastClickedAt) {
+			delta.LastClickedAt = click.ClickedAt
+		}
+	}
+
+	// The counters are only moved for clicks that were stored, so they never
+	// run ahead of the click rows analytics are computed from.
+	if err := retryClickWrite(func() error { return t.clickRepo.StoreBatch(clicks) }); err != nil {
+		t.failed.Add(uint64(len(clicks)))
+		log.Printf("Error storing batch of %d clicks, counters left unchanged: %v", len(clicks), err)
+		return
+	}
+	t.written.Add(uint64(len(clicks)))
+
+	counts := make([]domain.ClickCountDelta, 0, len(deltas))
+	for _, delta := range deltas {
+		counts = append(counts, *delta)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].URLID.String() < counts[j].URLID.String()
+	})
+	if err := retryClickWrite(func() error { return t.urlRepo.IncrementClickCounts(counts) }); err != nil {
+		log.Printf("Error incrementing click counts for %d URLs: %v", len(counts), err)
+	}
+}
+
+// markUniqueClicks sets IsUnique on every click whose visitor has not clicked
+// the same URL within the unique window. The worker's own memory is checked
+// first; visitors it has not seen are looked up in the clicks table in one
+// query per batch.
+func (t *clickTracker) markUniqueClicks(clicks []domain.Click, seen map[domain.VisitorKey]time.Time) {
+	earliest := clicks[0].ClickedAt
+	var lookup []domain.VisitorKey
+	pending := make(map[domain.VisitorKey]bool)
+	for _, click := range clicks {
+		if click.ClickedAt.Before(earliest) {
+			earliest = click.ClickedAt
+		}
+		key := domain.VisitorKey{URLID: click.URLID, VisitorHash: click.VisitorHash}
+		if _, ok := seen[key]; !ok && !pending[key] {
+			pending[key] = true
+			lookup = append(lookup, key)
+		}
+	}
+
+	seenInDB := make(map[domain.VisitorKey]bool)
+	found, err := t.clickRepo.FindSeenVisitors(lookup, earliest.Add(-t.uniqueWindow))
+	if err != nil {
+		log.Printf("Error checking unique visitors, treating batch as repeat clicks: %v", err)
+		for _, key := range lookup {
+			seenInDB[key] = true
+		}
+	}
+	for _, key := range found {
+		seenInDB[key] = true
+	}
+
+	for i := range clicks {
+		key := domain.VisitorKey{URLID: clicks[i].URLID, VisitorHash: clicks[i].VisitorHash}
+		lastSeen, inMemory := seen[key]
+		if inMemory {
+			clicks[i].IsUnique = clicks[i].ClickedAt.Sub(lastSeen) >= t.uniqueWindow
+		} else {
+			clicks[i].IsUnique = !seenInDB[key]
+		}
+		if !inMemory || clicks[i].ClickedAt.After(lastSeen) {
+			seen[key] = clicks[i].ClickedAt
+		}
+	}
+
+	if len(seen) > visitorCacheLimit {
+		cutoff := time.Now().Add(-t.uniqueWindow)
+		for key, lastSeen := range seen {
+			if lastSeen.Before(cutoff) {
+				delete(seen, key)
+			}
+		}
+		if len(seen) > visitorCacheLimit {
+			clear(seen)
+		}
+	}
+}
+
+func (t *clickTracker) buildClick(event ClickEvent) domain.Click {
+	parsedUA := utils.ParseUserAgent(event.Visitor.UserAgent)
+
+	location := event.Location
+	if location == nil {
+		var err error
+		location, err = t.geoipSvc.Lookup(event.Visitor.IPAddress)
+		if err != nil {
+			log.Printf("Could not perform GeoIP lookup for IP %s: %v", event.Visitor.IPAddress, err)
+		}
+	}
+
+	utm := event.Visitor.UTM
+	source := referrer.Classify(event.Visitor.Referer)
+	if source.Category == referrer.CategoryDirect {
+		if category := referrer.CategoryForMedium(utm.Medium); category != "" {
+			source.Category = category
+		}
+	}
+
+	return domain.Click{
+		URLID:            event.URLID,
+		IPAddress:        event.Visitor.IPAddress,
+		UserAgent:        event.Visitor.UserAgent,
+		Referer:          event.Visitor.Referer,
+		ReferrerDomain:   truncate(source.Domain, 255),
+		ReferrerCategory: source.Category,
+		UTMSource:        truncate(utm.Source, 255),
+		UTMMedium:        truncate(utm.Medium, 255),
+		UTMCampaign:      truncate(utm.Campaign, 255),
+		UTMTerm:          truncate(utm.Term, 255),
+		UTMContent:       truncate(utm.Content, 255),
+		Variant:          truncate(event.Variant, 255),
+		DeviceType:       parsedUA.DeviceType,
+		Browser:          parsedUA.BrowserName,
+		OS:               parsedUA.OSName,
+		Country:          location.Country,
+		Region:           location.Region,
+		City:             location.City,
+		Language:         utils.PrimaryLanguage(event.Visitor.Language),
+		VisitorHash:      t.identifier.Identify(event.Visitor, event.ClickedAt),
+		ClickedAt:        event.ClickedAt,
+	}
+}
+
+// truncate cuts s to at most max bytes without splitting a UTF-8 sequence,
+// so visitor-supplied values fit their columns.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+func retryClickWrite(write func() error) error {
+	var err error
+	for attempt := 1; attempt <= clickWriteAttempts; attempt++ {
+		if err = write(); err == nil {
+			return nil
+		}
+		if attempt < clickWriteAttempts {
+			time.Sleep(time.Duration(attempt) * clickRetryBackoff)
+		}
+	}
+	return err
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/geoip"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/referrer"
+	"github.com/google/uuid"
+)
+
+func TestBuildClickSource(t *testing.T) {
+	tracker := &clickTracker{identifier: NewVisitorIdentifier(configs.Config{})}
+	tests := []struct {
+		name         string
+		visitor      VisitorInfo
+		wantDomain   string
+		wantCategory string
+	}{
+		{"direct", VisitorInfo{}, "", referrer.CategoryDirect},
+		{"email without referrer", VisitorInfo{UTM: domain.UTMParams{Medium: "Newsletter"}}, "", referrer.CategoryEmail},
+		{"unknown medium", VisitorInfo{UTM: domain.UTMParams{Medium: "cpc"}}, "", referrer.CategoryDirect},
+		{"referrer beats medium", VisitorInfo{Referer: "https://l.facebook.com/", UTM: domain.UTMParams{Medium: "email"}}, "facebook.com", referrer.CategorySocial},
+	}
+	for _, tt := range tests {
+		click := tracker.buildClick(ClickEvent{Visitor: tt.visitor, Location: &geoip.LocationData{}, ClickedAt: time.Now()})
+		if click.ReferrerDomain != tt.wantDomain || click.ReferrerCategory != tt.wantCategory {
+			t.Errorf("%s: source = %q/%q, want %q/%q", tt.name, click.ReferrerDomain, click.ReferrerCategory, tt.wantDomain, tt.wantCategory)
+		}
+	}
+}
+
+func TestBuildClickTruncatesUTM(t *testing.T) {
+	tracker := &clickTracker{identifier: NewVisitorIdentifier(configs.Config{})}
+	long := strings.Repeat("é", 200)
+	click := tracker.buildClick(ClickEvent{
+		Visitor:   VisitorInfo{UTM: domain.UTMParams{Source: "newsletter", Campaign: long}},
+		Location:  &geoip.LocationData{},
+		ClickedAt: time.Now(),
+	})
+	if click.UTMSource != "newsletter" {
+		t.Errorf("UTMSource = %q", click.UTMSource)
+	}
+	if len(click.UTMCampaign) != 254 || !strings.HasPrefix(long, click.UTMCampaign) {
+		t.Errorf("UTMCampaign is %d bytes, want 254 without a split character", len(click.UTMCampaign))
+	}
+}
+
+type pipelineClickRepo struct {
+	domain.ClickRepository
+	mu      sync.Mutex
+	stored  []domain.Click
+	batches int
+	err     error
+}
+
+func (r *pipelineClickRepo) StoreBatch(clicks []domain.Click) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.stored = append(r.stored, clicks...)
+	r.batches++
+	return nil
+}
+
+func (r *pipelineClickRepo) FindSeenVisitors([]domain.VisitorKey, time.Time) ([]domain.VisitorKey, error) {
+	return nil, nil
+}
+
+type pipelineURLRepo struct {
+	domain.URLRepository
+	mu     sync.Mutex
+	totals map[uuid.UUID]domain.ClickCountDelta
+}
+
+func (r *pipelineURLRepo) IncrementClickCounts(deltas []domain.ClickCountDelta) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delta := range deltas {
+		total := r.totals[delta.URLID]
+		total.Clicks += delta.Clicks
+		total.UniqueClicks += delta.UniqueClicks
+		if delta.LastClickedAt.After(total.LastClickedAt) {
+			total.LastClickedAt = delta.LastClickedAt
+		}
+		r.totals[delta.URLID] = total
+	}
+	return nil
+}
+
+func TestClickTrackerWritesEveryQueuedClickOnShutdown(t *testing.T) {
+	clicks := &pipelineClickRepo{}
+	urls := &pipelineURLRepo{totals: map[uuid.UUID]domain.ClickCountDelta{}}
+	cfg := configs.Config{Clicks: configs.ClickPipelineConfig{Workers: 2, QueueSize: 100, BatchSize: 7, FlushInterval: "1h"}}
+	tracker := NewClickTracker(urls, clicks, fixedGeoIP{}, NewVisitorIdentifier(cfg), cfg)
+	tracker.Start()
+
+	links := []uuid.UUID{uuid.New(), uuid.New()}
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		event := ClickEvent{
+			URLID:     links[i%2],
+			Visitor:   VisitorInfo{VisitorID: "visitor-" + strconv.Itoa(i%5)},
+			ClickedAt: start.Add(time.Duration(i) * time.Second),
+		}
+		if err := tracker.Track(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracker.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clicks.stored) != 30 {
+		t.Errorf("stored %d clicks, want 30", len(clicks.stored))
+	}
+	// Each link is clicked three times by each of the five visitors.
+	for i, link := range links {
+		total := urls.totals[link]
+		if total.Clicks != 15 || total.UniqueClicks != 5 {
+			t.Errorf("link %d: counted %d clicks, %d unique, want 15 and 5", i, total.Clicks, total.UniqueClicks)
+		}
+		if want := start.Add(time.Duration(28+i) * time.Second); !total.LastClickedAt.Equal(want) {
+			t.Errorf("link %d: last clicked at %v, want %v", i, total.LastClickedAt, want)
+		}
+	}
+
+	stats := tracker.Stats()
+	if stats.Enqueued != 30 || stats.Written != 30 || stats.Dropped != 0 || stats.QueueDepth != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if err := tracker.Track(ClickEvent{URLID: links[0]}); err == nil || err.Error() != "CLICK_TRACKER_CLOSED" {
+		t.Errorf("Track after shutdown: error = %v, want CLICK_TRACKER_CLOSED", err)
+	}
+}
+
+func TestClickTrackerDropsClicksWhenTheQueueIsFull(t *testing.T) {
+	cfg := configs.Config{Clicks: configs.ClickPipelineConfig{Workers: 1, QueueSize: 1, BatchSize: 1, EnqueueTimeout: "10ms"}}
+	tracker := NewClickTracker(&pipelineURLRepo{}, &pipelineClickRepo{}, fixedGeoIP{}, NewVisitorIdentifier(cfg), cfg)
+
+	// Not started, so nothing drains the queue.
+	if err := tracker.Track(ClickEvent{URLID: uuid.New()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Track(ClickEvent{URLID: uuid.New()}); err == nil || err.Error() != "CLICK_QUEUE_FULL" {
+		t.Errorf("error = %v, want CLICK_QUEUE_FULL", err)
+	}
+	stats := tracker.Stats()
+	if stats.Enqueued != 1 || stats.Backpressured != 1 || stats.Dropped != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestClickTrackerLeavesCountersAloneWhenClicksCannotBeStored(t *testing.T) {
+	clicks := &pipelineClickRepo{err: errors.New("connection refused")}
+	urls := &pipelineURLRepo{totals: map[uuid.UUID]domain.ClickCountDelta{}}
+	cfg := configs.Config{Clicks: configs.ClickPipelineConfig{Workers: 1, QueueSize: 10, BatchSize: 10, FlushInterval: "1h"}}
+	tracker := NewClickTracker(urls, clicks, fixedGeoIP{}, NewVisitorIdentifier(cfg), cfg)
+	tracker.Start()
+
+	link := uuid.New()
+	for i := 0; i < 3; i++ {
+		if err := tracker.Track(ClickEvent{URLID: link, Visitor: VisitorInfo{VisitorID: strconv.Itoa(i)}, ClickedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tracker.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if total, ok := urls.totals[link]; ok {
+		t.Errorf("counters moved by %+v for clicks that were not stored", total)
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/dns"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DomainVerificationPrefix is the label under which the verification TXT
+	// record must be published, e.g. _uswa-verification.links.example.com.
+	DomainVerificationPrefix = "_uswa-verification"
+
+	domainLookupTimeout = 5 * time.Second
+)
+
+type DomainService interface {
+	AddDomain(userID uuid.UUID, req request.CreateDomainRequest) (*domain.Domain, error)
+	GetUserDomains(userID uuid.UUID) ([]domain.Domain, error)
+	GetDomain(domainID, userID uuid.UUID) (*domain.Domain, error)
+	UpdateDomain(domainID, userID uuid.UUID, req request.UpdateDomainRequest) (*domain.Domain, error)
+	VerifyDomain(domainID, userID uuid.UUID) (*domain.Domain, error)
+	DeleteDomain(domainID, userID uuid.UUID) error
+}
+
+type domainService struct {
+	domainRepo domain.DomainRepository
+	urlRepo    domain.URLRepository
+	resolver   dns.Resolver
+	cfg        configs.Config
+}
+
+func NewDomainService(domainRepo domain.DomainRepository, urlRepo domain.URLRepository, resolver dns.Resolver, cfg configs.Config) DomainService {
+	return &domainService{domainRepo: domainRepo, urlRepo: urlRepo, resolver: resolver, cfg: cfg}
+}
+
+// NormalizeDomainName lowercases a host name and strips a trailing dot and
+// any port, so lookups by Host header and by stored name agree.
+func NormalizeDomainName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if host, _, err := net.SplitHostPort(name); err == nil {
+		name = host
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// AddDomain claims a name for the user. Any number of users may hold an
+// unverified claim on the same name, so nobody can squat it; the name is
+// only taken once one of them verifies. Adding a name again after the
+// user's own claim expired starts a new claim with a new token.
+func (s *domainService) AddDomain(userID uuid.UUID, req request.CreateDomainRequest) (*domain.Domain, error) {
+	name := NormalizeDomainName(req.DomainName)
+
+	baseHost, _ := utils.GetDomainFromURL(s.cfg.Server.BaseURL)
+	if name == NormalizeDomainName(baseHost) {
+		return nil, errors.New("DOMAIN_RESERVED")
+	}
+
+	if _, err := s.domainRepo.FindVerifiedByDomainName(name); err == nil {
+		return nil, errors.New("DOMAIN_ALREADY_EXISTS")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := s.domainRepo.DeleteExpiredClaims(now.Add(-s.cfg.Domains.ClaimPeriod())); err != nil {
+		return nil, err
+	}
+
+	_, err := s.domainRepo.FindByUserIDAndDomainName(userID, name)
+	if err == nil {
+		return nil, errors.New("DOMAIN_ALREADY_EXISTS")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomString(24)
+	if err != nil {
+		return nil, err
+	}
+
+	newDomain := &domain.Domain{
+		UserID:            userID,
+		DomainName:        name,
+		VerificationToken: token,
+		IsActive:          true,
+	}
+	if err := s.domainRepo.Store(newDomain); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("DOMAIN_ALREADY_EXISTS")
+		}
+		return nil, err
+	}
+	return newDomain, nil
+}
+
+func (s *domainService) GetUserDomains(userID uuid.UUID) ([]domain.Domain, error) {
+	return s.domainRepo.FindAllByUserID(userID)
+}
+
+func (s *domainService) GetDomain(domainID, userID uuid.UUID) (*domain.Domain, error) {
+	d, err := s.domainRepo.FindByID(domainID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("DOMAIN_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if d.UserID != userID {
+		return nil, errors.New("DOMAIN_FORBIDDEN")
+	}
+	return d, nil
+}
+
+func (s *domainService) UpdateDomain(domainID, userID uuid.UUID, req request.UpdateDomainRequest) (*domain.Domain, error) {
+	d, err := s.GetDomain(domainID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IsActive != nil {
+		d.IsActive = *req.IsActive
+	}
+
+	if err := s.domainRepo.Update(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// VerifyDomain checks that the verification TXT record carries the domain's
+// token. Verification is sticky: a verified domain is not re-checked. A claim
+// cannot be verified once it has expired or another user has verified the
+// name.
+func (s *domainService) VerifyDomain(domainID, userID uuid.UUID) (*domain.Domain, error) {
+	d, err := s.GetDomain(domainID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if d.IsVerified {
+		return d, nil
+	}
+	if d.ClaimExpired(time.Now(), s.cfg.Domains.ClaimPeriod()) {
+		return nil, errors.New("DOMAIN_CLAIM_EXPIRED")
+	}
+	if _, err := s.domainRepo.FindVerifiedByDomainName(d.DomainName); err == nil {
+		return nil, errors.New("DOMAIN_ALREADY_EXISTS")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), domainLookupTimeout)
+	defer cancel()
+
+	records, err := s.resolver.LookupTXT(ctx, DomainVerificationPrefix+"."+d.DomainName)
+	if err != nil {
+		return nil, errors.New("DOMAIN_VERIFICATION_FAILED")
+	}
+
+	for _, record := range records {
+		if strings.TrimSpace(record) == d.VerificationToken {
+			now := time.Now()
+			d.IsVerified = true
+			d.VerifiedAt = &now
+			if err := s.domainRepo.Update(d); err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return nil, errors.New("DOMAIN_ALREADY_EXISTS")
+				}
+				return nil, err
+			}
+			return d, nil
+		}
+	}
+
+	return nil, errors.New("DOMAIN_VERIFICATION_FAILED")
+}
+
+func (s *domainService) DeleteDomain(domainID, userID uuid.UUID) error {
+	d, err := s.GetDomain(domainID, userID)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := s.urlRepo.CountByDomainID(d.ID)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return errors.New("DOMAIN_IN_USE")
+	}
+
+	// Trashed links could not be restored without their domain, so they are
+	// purged with it rather than left to block the deletion.
+	if err := s.urlRepo.PurgeDeletedByDomainID(d.ID); err != nil {
+		return err
+	}
+	return s.domainRepo.Delete(d)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+)
+
+func newTestDomainService(repo domain.DomainRepository, resolver fakeResolver) DomainService {
+	cfg := configs.Config{}
+	cfg.Server.BaseURL = "https://sho.rt"
+	cfg.Domains.ClaimTTL = "24h"
+	return NewDomainService(repo, nil, resolver, cfg)
+}
+
+func publishToken(resolver fakeResolver, d *domain.Domain) {
+	resolver[DomainVerificationPrefix+"."+d.DomainName] = []string{d.VerificationToken}
+}
+
+func wantError(t *testing.T, err error, code string) {
+	t.Helper()
+	if err == nil || err.Error() != code {
+		t.Fatalf("error = %v, want %s", err, code)
+	}
+}
+
+func TestAddDomainDoesNotLetSquattersBlockTheOwner(t *testing.T) {
+	repo := newFakeDomainRepo()
+	resolver := fakeResolver{}
+	svc := newTestDomainService(repo, resolver)
+	squatter, owner, latecomer := uuid.New(), uuid.New(), uuid.New()
+
+	squat, err := svc.AddDomain(squatter, request.CreateDomainRequest{DomainName: "links.example.com"})
+	if err != nil {
+		t.Fatalf("squatter claim: %v", err)
+	}
+	claim, err := svc.AddDomain(owner, request.CreateDomainRequest{DomainName: "Links.Example.com."})
+	if err != nil {
+		t.Fatalf("owner claim while another claim is pending: %v", err)
+	}
+	if claim.VerificationToken == squat.VerificationToken {
+		t.Fatal("competing claims share a verification token")
+	}
+
+	publishToken(resolver, claim)
+	verified, err := svc.VerifyDomain(claim.ID, owner)
+	if err != nil {
+		t.Fatalf("owner verification: %v", err)
+	}
+	if !verified.IsVerified {
+		t.Fatal("owner claim is not verified")
+	}
+
+	_, err = svc.VerifyDomain(squat.ID, squatter)
+	wantError(t, err, "DOMAIN_ALREADY_EXISTS")
+	_, err = svc.AddDomain(latecomer, request.CreateDomainRequest{DomainName: "links.example.com"})
+	wantError(t, err, "DOMAIN_ALREADY_EXISTS")
+}
+
+func TestAddDomainRejectsSecondClaimBySameUser(t *testing.T) {
+	svc := newTestDomainService(newFakeDomainRepo(), fakeResolver{})
+	userID := uuid.New()
+
+	if _, err := svc.AddDomain(userID, request.CreateDomainRequest{DomainName: "links.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := svc.AddDomain(userID, request.CreateDomainRequest{DomainName: "links.example.com"})
+	wantError(t, err, "DOMAIN_ALREADY_EXISTS")
+}
+
+func TestAddDomainRejectsServiceHost(t *testing.T) {
+	svc := newTestDomainService(newFakeDomainRepo(), fakeResolver{})
+	_, err := svc.AddDomain(uuid.New(), request.CreateDomainRequest{DomainName: "SHO.RT"})
+	wantError(t, err, "DOMAIN_RESERVED")
+}
+
+func TestExpiredClaimsCannotBeVerifiedAndAreReplaced(t *testing.T) {
+	repo := newFakeDomainRepo()
+	resolver := fakeResolver{}
+	svc := newTestDomainService(repo, resolver)
+	userID, other := uuid.New(), uuid.New()
+
+	stale := &domain.Domain{UserID: userID, DomainName: "links.example.com", VerificationToken: "old", CreatedAt: time.Now().Add(-25 * time.Hour)}
+	if err := repo.Store(stale); err != nil {
+		t.Fatal(err)
+	}
+	publishToken(resolver, stale)
+
+	_, err := svc.VerifyDomain(stale.ID, userID)
+	wantError(t, err, "DOMAIN_CLAIM_EXPIRED")
+
+	// Any new claim sweeps expired ones, so the user can start over.
+	if _, err := svc.AddDomain(other, request.CreateDomainRequest{DomainName: "other.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(stale.ID); err == nil {
+		t.Fatal("expired claim was not removed")
+	}
+	renewed, err := svc.AddDomain(userID, request.CreateDomainRequest{DomainName: "links.example.com"})
+	if err != nil {
+		t.Fatalf("claim after expiry: %v", err)
+	}
+	if renewed.VerificationToken == stale.VerificationToken {
+		t.Fatal("renewed claim kept the expired token")
+	}
+}
+
+func TestVerifyDomainReportsLostRace(t *testing.T) {
+	repo := newFakeDomainRepo()
+	resolver := fakeResolver{}
+	svc := newTestDomainService(repo, resolver)
+	first, second := uuid.New(), uuid.New()
+
+	a, _ := svc.AddDomain(first, request.CreateDomainRequest{DomainName: "links.example.com"})
+	b, _ := svc.AddDomain(second, request.CreateDomainRequest{DomainName: "links.example.com"})
+
+	// The other claim is verified between the pre-check and the update, so
+	// only the unique index notices.
+	racing := &racingDomainRepo{fakeDomainRepo: repo, before: func() {
+		a.IsVerified = true
+		repo.Update(a)
+	}}
+	svc = newTestDomainService(racing, resolver)
+	publishToken(resolver, b)
+
+	_, err := svc.VerifyDomain(b.ID, second)
+	wantError(t, err, "DOMAIN_ALREADY_EXISTS")
+}
+
+type racingDomainRepo struct {
+	*fakeDomainRepo
+	before func()
+}
+
+func (r *racingDomainRepo) Update(d *domain.Domain) error {
+	r.before()
+	return r.fakeDomainRepo.Update(d)
+}
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeDomainRepo keeps domain claims in memory and enforces the same unique
+// indexes as url_shortener.sql: one claim per user and name, and one
+// verified claim per name.
+type fakeDomainRepo struct {
+	mu      sync.Mutex
+	domains map[uuid.UUID]domain.Domain
+}
+
+func newFakeDomainRepo() *fakeDomainRepo {
+	return &fakeDomainRepo{domains: make(map[uuid.UUID]domain.Domain)}
+}
+
+func (r *fakeDomainRepo) conflicts(d *domain.Domain) bool {
+	for _, other := range r.domains {
+		if other.ID == d.ID || other.DomainName != d.DomainName {
+			continue
+		}
+		if other.UserID == d.UserID || (other.IsVerified && d.IsVerified) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeDomainRepo) Store(d *domain.Domain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conflicts(d) {
+		return gorm.ErrDuplicatedKey
+	}
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	r.domains[d.ID] = *d
+	return nil
+}
+
+func (r *fakeDomainRepo) find(match func(domain.Domain) bool) (*domain.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.domains {
+		if match(d) {
+			return &d, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeDomainRepo) FindByID(id uuid.UUID) (*domain.Domain, error) {
+	return r.find(func(d domain.Domain) bool { return d.ID == id })
+}
+
+func (r *fakeDomainRepo) FindVerifiedByDomainName(name string) (*domain.Domain, error) {
+	return r.find(func(d domain.Domain) bool { return d.DomainName == name && d.IsVerified })
+}
+
+func (r *fakeDomainRepo) FindByUserIDAndDomainName(userID uuid.UUID, name string) (*domain.Domain, error) {
+	return r.find(func(d domain.Domain) bool { return d.UserID == userID && d.DomainName == name })
+}
+
+func (r *fakeDomainRepo) FindAllByUserID(userID uuid.UUID) ([]domain.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var domains []domain.Domain
+	for _, d := range r.domains {
+		if d.UserID == userID {
+			domains = append(domains, d)
+		}
+	}
+	return domains, nil
+}
+
+func (r *fakeDomainRepo) Update(d *domain.Domain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conflicts(d) {
+		return gorm.ErrDuplicatedKey
+	}
+	r.domains[d.ID] = *d
+	return nil
+}
+
+func (r *fakeDomainRepo) Delete(d *domain.Domain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.domains, d.ID)
+	return nil
+}
+
+func (r *fakeDomainRepo) DeleteExpiredClaims(cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, d := range r.domains {
+		if !d.IsVerified && d.CreatedAt.Before(cutoff) {
+			delete(r.domains, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// fakeURLRepo keeps links in memory. Methods a test does not need fall
+// through to the nil embedded interface and panic.
+type fakeURLRepo struct {
+	domain.URLRepository
+
+	mu   sync.Mutex
+	urls map[uuid.UUID]*domain.URL
+}
+
+func newFakeURLRepo(urls ...*domain.URL) *fakeURLRepo {
+	r := &fakeURLRepo{urls: make(map[uuid.UUID]*domain.URL)}
+	for _, url := range urls {
+		if url.ID == uuid.Nil {
+			url.ID = uuid.New()
+		}
+		r.urls[url.ID] = url
+	}
+	return r
+}
+
+func (r *fakeURLRepo) get(id uuid.UUID) *domain.URL {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.urls[id]
+}
+
+// Store enforces the unique index on short codes per domain, trashed links
+// included.
+func (r *fakeURLRepo) Store(url *domain.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, other := range r.urls {
+		if other.ShortCode == url.ShortCode && sameDomain(other.DomainID, url.DomainID) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	url.ID = uuid.New()
+	url.IsActive = true
+	stored := *url
+	r.urls[url.ID] = &stored
+	return nil
+}
+
+func (r *fakeURLRepo) FindByID(id uuid.UUID) (*domain.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	url, ok := r.urls[id]
+	if !ok || url.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *url
+	return &found, nil
+}
+
+func (r *fakeURLRepo) FindByShortCode(domainID *uuid.UUID, shortCode string) (*domain.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, url := range r.urls {
+		if url.ShortCode == shortCode && sameDomain(url.DomainID, domainID) && !url.DeletedAt.Valid {
+			found := *url
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeURLRepo) IsShortCodeTaken(domainID *uuid.UUID, shortCode string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, url := range r.urls {
+		if url.ShortCode == shortCode && sameDomain(url.DomainID, domainID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeURLRepo) Update(url *domain.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *url
+	r.urls[url.ID] = &stored
+	return nil
+}
+
+// ConsumeClick mirrors the conditional UPDATE of the Postgres repository.
+func (r *fakeURLRepo) ConsumeClick(url *domain.URL) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.urls[url.ID]
+	if !ok || stored.DeletedAt.Valid || !stored.IsActive || stored.MaxClicks == nil || stored.RedirectCount >= *stored.MaxClicks {
+		return false, nil
+	}
+	stored.RedirectCount++
+	if stored.RedirectCount >= *stored.MaxClicks && stored.CapFallbackURL == nil {
+		stored.IsActive = false
+	}
+	url.RedirectCount = stored.RedirectCount
+	return true, nil
+}
+
+func (r *fakeURLRepo) Delete(url *domain.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.urls[url.ID].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (r *fakeURLRepo) CountCreatedByUserSince(uuid.UUID, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func sameDomain(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// fakeUserRepo serves users from a map.
+type fakeUserRepo struct {
+	domain.UserRepository
+	users map[uuid.UUID]*domain.User
+}
+
+func (r fakeUserRepo) FindByID(id uuid.UUID) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+// fakeBulkRepo records the last saved state of each bulk operation.
+type fakeBulkRepo struct {
+	mu    sync.Mutex
+	saved map[uuid.UUID]domain.BulkOperation
+}
+
+func newFakeBulkRepo() *fakeBulkRepo {
+	return &fakeBulkRepo{saved: make(map[uuid.UUID]domain.BulkOperation)}
+}
+
+func (r *fakeBulkRepo) Store(op *domain.BulkOperation) error {
+	return r.Update(op)
+}
+
+func (r *fakeBulkRepo) FindByID(id uuid.UUID) (*domain.BulkOperation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	op, ok := r.saved[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &op, nil
+}
+
+func (r *fakeBulkRepo) Update(op *domain.BulkOperation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved[op.ID] = *op
+	return nil
+}
+
+func (r *fakeBulkRepo) ClaimNext(string, time.Time) (*domain.BulkOperation, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeResolver answers TXT lookups from a map of record name to values.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+// fakeSessionRepo keeps sessions in memory.
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]domain.Session
+}
+
+func newFakeSessionRepo(sessions ...*domain.Session) *fakeSessionRepo {
+	r := &fakeSessionRepo{sessions: map[uuid.UUID]domain.Session{}}
+	for _, session := range sessions {
+		r.sessions[session.ID] = *session
+	}
+	return r
+}
+
+func (r *fakeSessionRepo) Store(session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeSessionRepo) FindByID(id uuid.UUID) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &session, nil
+}
+
+func (r *fakeSessionRepo) Touch(id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[id]
+	session.LastUsedAt = at
+	r.sessions[id] = session
+	return nil
+}
+
+func (r *fakeSessionRepo) Revoke(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[id]
+	now := time.Now()
+	session.RevokedAt = &now
+	r.sessions[id] = session
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllByUserID(userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for id, session := range r.sessions {
+		if session.UserID == userID {
+			session.RevokedAt = &now
+			r.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+// fakeRefreshTokenRepo keeps refresh tokens in memory. Rotate is atomic, as
+// the conditional UPDATE in the postgres repository is.
+type fakeRefreshTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]domain.RefreshToken
+}
+
+func newFakeRefreshTokenRepo() *fakeRefreshTokenRepo {
+	return &fakeRefreshTokenRepo{tokens: map[uuid.UUID]domain.RefreshToken{}}
+}
+
+func (r *fakeRefreshTokenRepo) Store(token *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByID(id uuid.UUID) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &token, nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByParentID(parentID uuid.UUID) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ParentID != nil && *token.ParentID == parentID {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRefreshTokenRepo) Rotate(usedID uuid.UUID, next *domain.RefreshToken, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.tokens[usedID]
+	if !ok || used.UsedAt != nil || !at.Before(used.ExpiresAt) {
+		return gorm.ErrRecordNotFound
+	}
+	used.UsedAt = &at
+	r.tokens[usedID] = used
+	r.tokens[next.ID] = *next
+	return nil
+}
+
+// backdateUse moves the time a token was used into the past.
+func (r *fakeRefreshTokenRepo) backdateUse(id uuid.UUID, by time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token := r.tokens[id]
+	usedAt := token.UsedAt.Add(-by)
+	token.UsedAt = &usedAt
+	r.tokens[id] = token
+}
+
+// fakeClickTracker records tracked clicks.
+type fakeClickTracker struct {
+	ClickTracker
+
+	mu     sync.Mutex
+	events []ClickEvent
+}
+
+func (t *fakeClickTracker) Track(event ClickEvent) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+	return nil
+}
+
+// fakeClickLog serves a click log from memory, newest first like the
+// postgres repository.
+type fakeClickLog struct {
+	domain.ClickRepository
+	entries []domain.ClickLogEntry
+}
+
+func (r *fakeClickLog) newestFirst() []domain.ClickLogEntry {
+	sorted := append([]domain.ClickLogEntry(nil), r.entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].ClickedAt.Equal(sorted[j].ClickedAt) {
+			return sorted[i].ClickedAt.After(sorted[j].ClickedAt)
+		}
+		return sorted[i].ID.String() > sorted[j].ID.String()
+	})
+	return sorted
+}
+
+func (r *fakeClickLog) FindLog(_ context.Context, _ domain.ClickFilter, after *domain.ClickCursor, limit int) ([]domain.ClickLogEntry, error) {
+	var page []domain.ClickLogEntry
+	for _, entry := range r.newestFirst() {
+		if after != nil {
+			if entry.ClickedAt.After(after.ClickedAt) {
+				continue
+			}
+			if entry.ClickedAt.Equal(after.ClickedAt) && entry.ID.String() >= after.ID.String() {
+				continue
+			}
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, entry)
+	}
+	return page, nil
+}
+
+func (r *fakeClickLog) StreamLog(_ context.Context, _ domain.ClickFilter, fn func(*domain.ClickLogEntry) error) error {
+	entries := r.newestFirst()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := fn(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
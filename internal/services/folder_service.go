@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FolderService interface {
+	CreateFolder(userID uuid.UUID, req request.CreateFolderRequest) (*domain.Folder, error)
+	GetUserFolders(userID uuid.UUID) ([]domain.Folder, error)
+	GetFolder(folderID, userID uuid.UUID) (*domain.Folder, error)
+	UpdateFolder(folderID, userID uuid.UUID, req request.UpdateFolderRequest) (*domain.Folder, error)
+	DeleteFolder(folderID, userID uuid.UUID) error
+}
+
+type folderService struct {
+	folderRepo domain.FolderRepository
+}
+
+func NewFolderService(folderRepo domain.FolderRepository) FolderService {
+	return &folderService{folderRepo: folderRepo}
+}
+
+func (s *folderService) CreateFolder(userID uuid.UUID, req request.CreateFolderRequest) (*domain.Folder, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkNameAvailable(userID, name, nil); err != nil {
+		return nil, err
+	}
+
+	folder := &domain.Folder{UserID: userID, Name: name}
+	if err := s.folderRepo.Store(folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (s *folderService) GetUserFolders(userID uuid.UUID) ([]domain.Folder, error) {
+	return s.folderRepo.FindAllByUserID(userID)
+}
+
+func (s *folderService) GetFolder(folderID, userID uuid.UUID) (*domain.Folder, error) {
+	folder, err := s.folderRepo.FindByID(folderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("FOLDER_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if folder.UserID != userID {
+		return nil, errors.New("FOLDER_FORBIDDEN")
+	}
+	return folder, nil
+}
+
+func (s *folderService) UpdateFolder(folderID, userID uuid.UUID, req request.UpdateFolderRequest) (*domain.Folder, error) {
+	folder, err := s.GetFolder(folderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkNameAvailable(userID, name, &folder.ID); err != nil {
+		return nil, err
+	}
+	folder.Name = name
+
+	if err := s.folderRepo.Update(folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// DeleteFolder removes the folder. Its links are kept outside any folder.
+func (s *folderService) DeleteFolder(folderID, userID uuid.UUID) error {
+	folder, err := s.GetFolder(folderID, userID)
+	if err != nil {
+		return err
+	}
+	return s.folderRepo.Delete(folder)
+}
+
+// checkNameAvailable reports FOLDER_ALREADY_EXISTS when another of the
+// user's folders has the name, ignoring case.
+func (s *folderService) checkNameAvailable(userID uuid.UUID, name string, self *uuid.UUID) error {
+	if name == "" {
+		return errors.New("FOLDER_NAME_REQUIRED")
+	}
+	existing, err := s.folderRepo.FindByUserIDAndName(userID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if self != nil && existing.ID == *self {
+		return nil
+	}
+	return errors.New("FOLDER_ALREADY_EXISTS")
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
@@ -41,7 +40,7 @@ func (s *qrCodeService) GetQRCodeInfo(urlID, userID uuid.UUID, size int, format
 		return nil, "", err
 	}
 
-	shortURL := fmt.Sprintf("%s/%s", s.cfg.Server.BaseURL, url.ShortCode)
+	shortURL := utils.BuildShortURL(s.cfg.Server.BaseURL, url.DomainName(), url.ShortCode)
 	qrCodeBase64, err := utils.GenerateQRCodeBase64(shortURL, size)
 	if err != nil {
 		return nil, "", err
@@ -56,7 +55,7 @@ func (s *qrCodeService) GetQRCodeForDownload(urlID, userID uuid.UUID, size int)
 		return nil, nil, err
 	}
 
-	shortURL := fmt.Sprintf("%s/%s", s.cfg.Server.BaseURL, url.ShortCode)
+	shortURL := utils.BuildShortURL(s.cfg.Server.BaseURL, url.DomainName(), url.ShortCode)
 	qrCodeBytes, err := utils.GenerateQRCodeBytes(shortURL, size)
 	if err != nil {
 		return nil, nil, err
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"log"
 	"time"
@@ -8,123 +10,364 @@ import (
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/geoip"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/ratelimit"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// UnlockResult is a successful unlock. Token lets ProcessRedirect through
+// to URL until ExpiresAt.
 type UnlockResult struct {
-	RedirectURL string
-	AccessToken string
+	URL       *domain.URL
+	Token     string
+	ExpiresAt time.Time
+}
+
+// ThrottledError is returned by UnlockURL once a client has made too many
+// password attempts on a link. Its Error is the code URL_UNLOCK_THROTTLED.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return "URL_UNLOCK_THROTTLED"
+}
+
+// RedirectResult is where to send a visitor. FallbackURL is only set when
+// URL is a custom-scheme deep link, which has to be opened from a page that
+// falls back to FallbackURL when no app handles it.
+type RedirectResult struct {
+	URL         string
+	FallbackURL string
 }
 
 type InfoResult struct {
-	URL    *domain.URL
-	Domain string
-	IsSafe bool
+	URL      *domain.URL
+	Domain   string
+	IsSafe   bool
+	Schedule domain.ScheduleStatus
+}
+
+// UnavailableError is returned for a link that exists but is not live: not
+// launched yet, outside its schedule, or expired without a fallback
+// destination. Its Error is the code URL_NOT_AVAILABLE.
+type UnavailableError struct {
+	Status domain.ScheduleStatus
+	// Message is the link's ExpiredMessage, for expired links.
+	Message *string
+	// Location is the time zone of the link's schedule, UTC without one.
+	Location *time.Location
+}
+
+func (e *UnavailableError) Error() string {
+	return "URL_NOT_AVAILABLE"
+}
+
+func newUnavailableError(url *domain.URL, status domain.ScheduleStatus) *UnavailableError {
+	err := &UnavailableError{Status: status, Location: time.UTC}
+	if status.State == domain.ScheduleExpired {
+		err.Message = url.ExpiredMessage
+	}
+	if url.Schedule != nil {
+		if loc, locErr := url.Schedule.Location(); locErr == nil {
+			err.Location = loc
+		}
+	}
+	return err
 }
 
 type RedirectService interface {
-	ProcessRedirect(c *gin.Context, shortCode string) (string, error)
-	UnlockURL(shortCode, password string) (*UnlockResult, error)
-	GetURLInfo(shortCode string) (*InfoResult, error)
+	// ProcessRedirect records a click and returns where to send the visitor:
+	// the destination of the link's device rule for the visitor's platform,
+	// else of its first matching geo rule, else the visitor's split
+	// destination, else OriginalURL. Password-protected links need an
+	// unlockToken issued by UnlockURL.
+	ProcessRedirect(host, shortCode string, visitor VisitorInfo, unlockToken string) (*RedirectResult, error)
+	// UnlockURL checks a password attempt made from ip and, if it is right,
+	// issues the token that opens the link. Attempts are throttled per link
+	// and IP.
+	UnlockURL(host, shortCode, password, ip string) (*UnlockResult, error)
+	GetURLInfo(host, shortCode string) (*InfoResult, error)
 }
 
 type redirectService struct {
-	urlRepo   domain.URLRepository
-	clickRepo domain.ClickRepository
-	geoipSvc  geoip.GeoIPService
-	cfg       configs.Config
+	urlRepo      domain.URLRepository
+	domainRepo   domain.DomainRepository
+	clickTracker ClickTracker
+	geoipSvc     geoip.GeoIPService
+	limiter      *ratelimit.Limiter
+	unlockSigner *unlockSigner
+	cfg          configs.Config
+	baseHost     string
 }
 
-func NewRedirectService(urlRepo domain.URLRepository, clickRepo domain.ClickRepository, geoipSvc geoip.GeoIPService, cfg configs.Config) RedirectService {
-	return &redirectService{urlRepo: urlRepo, clickRepo: clickRepo, geoipSvc: geoipSvc, cfg: cfg}
+func NewRedirectService(urlRepo domain.URLRepository, domainRepo domain.DomainRepository, clickTracker ClickTracker, geoipSvc geoip.GeoIPService, limiter *ratelimit.Limiter, cfg configs.Config) RedirectService {
+	baseHost, _ := utils.GetDomainFromURL(cfg.Server.BaseURL)
+	return &redirectService{
+		urlRepo:      urlRepo,
+		domainRepo:   domainRepo,
+		clickTracker: clickTracker,
+		geoipSvc:     geoipSvc,
+		limiter:      limiter,
+		unlockSigner: newUnlockSigner(cfg),
+		cfg:          cfg,
+		baseHost:     NormalizeDomainName(baseHost),
+	}
+}
+
+// findURL resolves a (host, shortCode) pair. Requests on the default host, or
+// on any host that is not a verified custom domain, resolve against links
+// without a domain; requests on a verified domain only see that domain's
+// links and only while the domain is active.
+func (s *redirectService) findURL(host, shortCode string) (*domain.URL, error) {
+	host = NormalizeDomainName(host)
+
+	var domainID *uuid.UUID
+	if host != "" && host != s.baseHost {
+		d, err := s.domainRepo.FindVerifiedByDomainName(host)
+		if err == nil {
+			if !d.IsActive {
+				return nil, errors.New("URL_NOT_FOUND")
+			}
+			domainID = &d.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	url, err := s.urlRepo.FindByShortCode(domainID, shortCode)
+	if err != nil {
+		return nil, errors.New("URL_NOT_FOUND")
+	}
+	return url, nil
 }
 
-func (s *redirectService) ProcessRedirect(c *gin.Context, shortCode string) (string, error) {
-	url, err := s.urlRepo.FindByShortCode(shortCode)
+func (s *redirectService) ProcessRedirect(host, shortCode string, visitor VisitorInfo, unlockToken string) (*RedirectResult, error) {
+	url, err := s.findURL(host, shortCode)
 	if err != nil {
-		return "", errors.New("URL_NOT_FOUND")
+		return nil, errors.New("URL_NOT_FOUND")
 	}
 
 	if !url.IsActive {
-		return "", errors.New("URL_NOT_FOUND")
+		return nil, errors.New("URL_NOT_FOUND")
+	}
+
+	event := ClickEvent{URLID: url.ID, Visitor: visitor, ClickedAt: time.Now()}
+	destination, err := s.destination(url, &event, unlockToken)
+	if err != nil {
+		return nil, err
 	}
-	if url.ExpiresAt != nil && url.ExpiresAt.Before(time.Now()) {
-		return "", errors.New("URL_NOT_FOUND")
+	if err := s.clickTracker.Track(event); err != nil {
+		log.Printf("Error queueing click for URL %s: %v", url.ID, err)
 	}
-	if url.PasswordHash != nil {
-		return "", errors.New("URL_PASSWORD_PROTECTED")
+
+	return destination, nil
+}
+
+// destination decides where the visitor of event goes: to the expired
+// fallback, nowhere while the link is not live or locked, to the click
+// cap's fallback, or through the targeting rules.
+func (s *redirectService) destination(url *domain.URL, event *ClickEvent, unlockToken string) (*RedirectResult, error) {
+	status := url.ScheduleStatus(event.ClickedAt)
+	switch {
+	case status.State == domain.ScheduleExpired && url.ExpiredRedirectURL != nil:
+		event.Variant = domain.VariantExpired
+		return &RedirectResult{URL: *url.ExpiredRedirectURL}, nil
+	case status.State != domain.ScheduleAvailable:
+		return nil, newUnavailableError(url, status)
+	case url.IsPasswordProtected() && !s.unlockSigner.Verify(url, unlockToken, event.ClickedAt):
+		return nil, errors.New("URL_PASSWORD_PROTECTED")
 	}
 
-	go s.trackClick(c, url.ID)
+	allowed, err := s.consumeClickCap(url, event.Visitor.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	if allowed {
+		return s.chooseDestination(url, event), nil
+	}
+	if url.CapFallbackURL == nil {
+		return nil, errors.New("URL_NOT_FOUND")
+	}
+	event.Variant = domain.VariantClickCapReached
+	return &RedirectResult{URL: *url.CapFallbackURL}, nil
+}
 
-	return url.OriginalURL, nil
+// consumeClickCap counts a redirect against the link's MaxClicks and reports
+// whether it may go to the link's destination. Bots, such as the link
+// previews of chat apps, are refused without counting, so they cannot use
+// up a one-time link before the person it was sent to opens it.
+func (s *redirectService) consumeClickCap(url *domain.URL, userAgent string) (bool, error) {
+	if url.MaxClicks == nil {
+		return true, nil
+	}
+	if url.ClickCapReached() || utils.ParseUserAgent(userAgent).IsBot {
+		return false, nil
+	}
+	return s.urlRepo.ConsumeClick(url)
 }
 
-func (s *redirectService) trackClick(c *gin.Context, urlID uuid.UUID) {
-	if err := s.urlRepo.IncrementClickCount(urlID); err != nil {
-		log.Printf("Error incrementing click count for URL %s: %v", urlID, err)
+// chooseDestination applies the link's targeting rules to the visitor of
+// event and records the chosen variant on it. A device rule wins over the
+// geo rules and the split, whose destination stays the web fallback of a
+// deep link. Links without rules or split always go to OriginalURL and
+// record no variant.
+func (s *redirectService) chooseDestination(url *domain.URL, event *ClickEvent) *RedirectResult {
+	web := s.webDestination(url, event)
+
+	platform := utils.ParseUserAgent(event.Visitor.UserAgent).Platform
+	rule := domain.MatchDeviceRule(url.DeviceRules, platform)
+	if rule == nil {
+		if len(url.DeviceRules) > 0 && event.Variant == "" {
+			event.Variant = domain.VariantDefault
+		}
+		return &RedirectResult{URL: web}
+	}
+
+	event.Variant = rule.Variant()
+	if !rule.IsDeepLink() {
+		return &RedirectResult{URL: rule.DestinationURL}
 	}
+	fallback := rule.FallbackURL
+	if fallback == "" {
+		fallback = web
+	}
+	return &RedirectResult{URL: rule.DestinationURL, FallbackURL: fallback}
+}
 
-	uaString := c.Request.UserAgent()
-	parsedUA := utils.ParseUserAgent(uaString)
-	clientIP := c.ClientIP()
+// webDestination applies the link's geo rules, then its split. The GeoIP
+// lookup is kept on event so the click tracker does not repeat it.
+func (s *redirectService) webDestination(url *domain.URL, event *ClickEvent) string {
+	if len(url.GeoRules) > 0 {
+		location, err := s.geoipSvc.Lookup(event.Visitor.IPAddress)
+		if err != nil {
+			log.Printf("Could not perform GeoIP lookup for IP %s: %v", event.Visitor.IPAddress, err)
+		}
+		event.Location = location
 
-	location, err := s.geoipSvc.Lookup(clientIP)
-	if err != nil {
-		log.Printf("Could not perform GeoIP lookup for IP %s: %v", clientIP, err)
+		if rule := domain.MatchGeoRule(url.GeoRules, location.Country, location.Region); rule != nil {
+			event.Variant = rule.Variant()
+			return rule.DestinationURL
+		}
 	}
 
-	newClick := &domain.Click{
-		URLID:      urlID,
-		IPAddress:  clientIP,
-		UserAgent:  uaString,
-		Referer:    c.Request.Referer(),
-		DeviceType: parsedUA.DeviceType,
-		Browser:    parsedUA.BrowserName,
-		OS:         parsedUA.OSName,
-		Country:    location.Country,
-		Region:     location.Region,
-		City:       location.City,
+	if split := domain.PickSplitDestination(url.SplitDestinations, splitPoint(url.ID, event.Visitor)); split != nil {
+		event.Variant = split.Variant()
+		return split.DestinationURL
 	}
-	if err := s.clickRepo.Store(newClick); err != nil {
-		log.Printf("Error storing click details for URL %s: %v", urlID, err)
+	if len(url.GeoRules) > 0 {
+		event.Variant = domain.VariantDefault
 	}
+	return url.OriginalURL
 }
 
-func (s *redirectService) UnlockURL(shortCode, password string) (*UnlockResult, error) {
-	url, err := s.urlRepo.FindByShortCode(shortCode)
+// splitPoint places a visitor in a link's split. It hashes the visitor
+// cookie, or IP and user agent without one, together with the link ID, so a
+// visitor gets the same destination on every visit without landing in the
+// same arm of every split.
+func splitPoint(urlID uuid.UUID, visitor VisitorInfo) uint64 {
+	key := visitor.VisitorID
+	if key == "" {
+		key = visitor.IPAddress + "|" + visitor.UserAgent
+	}
+	sum := sha256.Sum256([]byte(urlID.String() + "|" + key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+func (s *redirectService) UnlockURL(host, shortCode, password, ip string) (*UnlockResult, error) {
+	url, err := s.findURL(host, shortCode)
 	if err != nil {
 		return nil, errors.New("URL_NOT_FOUND")
 	}
 
-	if url.PasswordHash == nil {
+	if !url.IsActive || url.ScheduleStatus(time.Now()).State != domain.ScheduleAvailable {
+		return nil, errors.New("URL_NOT_FOUND")
+	}
+	if !url.IsPasswordProtected() {
 		return nil, errors.New("URL_NOT_PROTECTED")
 	}
-
-	if !utils.CheckPasswordHash(password, *url.PasswordHash) {
-		return nil, errors.New("URL_INVALID_PASSWORD")
+	if err := s.throttleUnlock(url, ip); err != nil {
+		return nil, err
 	}
 
-	tempToken, err := utils.GenerateToken(url.ID, s.cfg.JWT.SecretKey, 1*time.Minute)
+	passwordHash, err := s.passwordHash(url)
 	if err != nil {
 		return nil, err
 	}
+	if !utils.CheckPasswordHash(password, passwordHash) {
+		return nil, errors.New("URL_INVALID_PASSWORD")
+	}
 
+	expiresAt := time.Now().Add(s.cfg.Unlock.Session())
 	return &UnlockResult{
-		RedirectURL: url.OriginalURL,
-		AccessToken: tempToken,
+		URL:       url,
+		Token:     s.unlockSigner.Sign(url, expiresAt),
+		ExpiresAt: expiresAt,
 	}, nil
 }
 
-func (s *redirectService) GetURLInfo(shortCode string) (*InfoResult, error) {
-	url, err := s.urlRepo.FindByShortCode(shortCode)
+// passwordHash returns the password hash of url. Cached copies of a link
+// carry only a fingerprint of it, so the hash is then read from the
+// database.
+func (s *redirectService) passwordHash(url *domain.URL) (string, error) {
+	if url.PasswordHash != nil {
+		return *url.PasswordHash, nil
+	}
+	stored, err := s.urlRepo.FindByID(url.ID)
+	if err != nil {
+		return "", err
+	}
+	if stored.PasswordHash == nil {
+		return "", errors.New("URL_NOT_PROTECTED")
+	}
+	return *stored.PasswordHash, nil
+}
+
+// throttleUnlock counts a password attempt on url from ip. Every attempt
+// counts, right or wrong, so the check can run before the password is.
+// Attempts are limited per link and IP, and per link alone, so guesses
+// spread over many addresses still run out. If the counter store fails the
+// attempt is allowed, as with the API limits.
+func (s *redirectService) throttleUnlock(url *domain.URL, ip string) error {
+	limits := []struct {
+		key   string
+		limit int
+	}{
+		{"unlock:" + url.ID.String() + "|ip:" + ip, s.cfg.Unlock.Attempts},
+		{"unlock:" + url.ID.String(), s.cfg.Unlock.LinkAttempts},
+	}
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+		rule := ratelimit.Rule{Limit: l.limit, Window: s.cfg.Unlock.AttemptPeriod()}
+		result, err := s.limiter.Allow(&domain.RateLimit{
+			LimitKey:  l.key,
+			IPAddress: ip,
+			Endpoint:  "/:shortCode/unlock",
+		}, rule)
+		if err != nil {
+			log.Printf("Unlock throttle check failed for URL %s: %v", url.ID, err)
+			continue
+		}
+		if !result.Allowed {
+			return &ThrottledError{RetryAfter: result.RetryAfter}
+		}
+	}
+	return nil
+}
+
+func (s *redirectService) GetURLInfo(host, shortCode string) (*InfoResult, error) {
+	url, err := s.findURL(host, shortCode)
 	if err != nil {
 		return nil, errors.New("URL_NOT_FOUND")
 	}
 
-	if !url.IsActive || (url.ExpiresAt != nil && url.ExpiresAt.Before(time.Now())) {
+	if !url.IsActive {
+		return nil, errors.New("URL_NOT_FOUND")
+	}
+	if url.ClickCapReached() && url.CapFallbackURL == nil {
 		return nil, errors.New("URL_NOT_FOUND")
 	}
 
@@ -136,8 +379,9 @@ func (s *redirectService) GetURLInfo(shortCode string) (*InfoResult, error) {
 	isSafe := true
 
 	return &InfoResult{
-		URL:    url,
-		Domain: domainName,
-		IsSafe: isSafe,
+		URL:      url,
+		Domain:   domainName,
+		IsSafe:   isSafe,
+		Schedule: url.ScheduleStatus(time.Now()),
 	}, nil
 }
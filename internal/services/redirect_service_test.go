@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/repository/cached"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/cache"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/geoip"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/ratelimit"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUnlockThroughTheLookupCache(t *testing.T) {
+	hash, err := utils.HashPassword("open sesame")
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := &domain.URL{ShortCode: "locked", OriginalURL: "https://example.com/", IsActive: true, PasswordHash: &hash}
+	urls := newFakeURLRepo(link)
+	repo := cached.NewURLRepository(urls, cache.NewMemoryCache(10), time.Minute, 0)
+	cfg := configs.Config{
+		Server: configs.ServerConfig{BaseURL: "https://sho.rt"},
+		JWT:    configs.JWTConfig{SecretKey: "secret"},
+	}
+	svc := NewRedirectService(repo, newFakeDomainRepo(), &fakeClickTracker{}, nil, nil, cfg)
+
+	// Warm the cache, whose copy carries only the password fingerprint.
+	if _, err := svc.GetURLInfo("sho.rt", "locked"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ProcessRedirect("sho.rt", "locked", VisitorInfo{}, ""); err == nil || err.Error() != "URL_PASSWORD_PROTECTED" {
+		t.Fatalf("redirect without unlocking: err = %v", err)
+	}
+
+	if _, err := svc.UnlockURL("sho.rt", "locked", "wrong", "203.0.113.1"); err == nil || err.Error() != "URL_INVALID_PASSWORD" {
+		t.Fatalf("wrong password: err = %v", err)
+	}
+	unlocked, err := svc.UnlockURL("sho.rt", "locked", "open sesame", "203.0.113.1")
+	if err != nil {
+		t.Fatalf("right password: %v", err)
+	}
+	if _, err := svc.ProcessRedirect("sho.rt", "locked", VisitorInfo{}, unlocked.Token); err != nil {
+		t.Fatalf("redirect with the unlock token: %v", err)
+	}
+
+	// Changing the password revokes the token, cached copy or not.
+	stored, _ := urls.FindByID(link.ID)
+	newHash, _ := utils.HashPassword("new password")
+	stored.PasswordHash = &newHash
+	if err := repo.Update(stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ProcessRedirect("sho.rt", "locked", VisitorInfo{}, unlocked.Token); err == nil || err.Error() != "URL_PASSWORD_PROTECTED" {
+		t.Fatalf("redirect with a token from before the password change: err = %v", err)
+	}
+}
+
+func TestUnlockAttemptsAreLimitedPerLinkAcrossIPs(t *testing.T) {
+	// The lowest cost keeps the guesses fast; checks work at any cost.
+	hashed, err := bcrypt.GenerateFromPassword([]byte("open sesame"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := string(hashed)
+	link := &domain.URL{ShortCode: "locked", OriginalURL: "https://example.com/", IsActive: true, PasswordHash: &hash}
+	cfg := configs.Config{
+		JWT:    configs.JWTConfig{SecretKey: "secret"},
+		Unlock: configs.UnlockConfig{Attempts: 2, LinkAttempts: 3},
+	}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	svc := NewRedirectService(newFakeURLRepo(link), newFakeDomainRepo(), &fakeClickTracker{}, nil, limiter, cfg)
+
+	// Each guess comes from a new address, as with a spoofed or rotated IP.
+	for i := 1; i <= 3; i++ {
+		if _, err := svc.UnlockURL("sho.rt", "locked", "wrong", fmt.Sprintf("203.0.113.%d", i)); err == nil || err.Error() != "URL_INVALID_PASSWORD" {
+			t.Fatalf("guess %d: err = %v, want URL_INVALID_PASSWORD", i, err)
+		}
+	}
+	var throttled *ThrottledError
+	if _, err := svc.UnlockURL("sho.rt", "locked", "open sesame", "198.51.100.1"); !errors.As(err, &throttled) {
+		t.Fatalf("fourth attempt from a fresh IP: err = %v, want throttled", err)
+	}
+}
+
+type fixedGeoIP geoip.LocationData
+
+func (g fixedGeoIP) Lookup(string) (*geoip.LocationData, error) {
+	location := geoip.LocationData(g)
+	return &location, nil
+}
+
+func TestChooseDestination(t *testing.T) {
+	link := &domain.URL{
+		ID:          uuid.New(),
+		OriginalURL: "https://example.com/",
+		GeoRules:    []domain.GeoRule{{Country: "ID", DestinationURL: "https://example.com/id"}},
+		DeviceRules: []domain.DeviceRule{{Platform: "ios", DestinationURL: "myapp://home"}},
+	}
+	const iPhone = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+	const desktop = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+	tests := []struct {
+		name         string
+		country      string
+		userAgent    string
+		wantURL      string
+		wantFallback string
+		wantVariant  string
+	}{
+		{"geo rule", "ID", desktop, "https://example.com/id", "", "geo:ID"},
+		{"no rule matches", "DE", desktop, "https://example.com/", "", domain.VariantDefault},
+		{"deep link falls back to the geo destination", "ID", iPhone, "myapp://home", "https://example.com/id", "device:ios"},
+	}
+	for _, tt := range tests {
+		svc := &redirectService{geoipSvc: fixedGeoIP{Country: tt.country}}
+		event := &ClickEvent{Visitor: VisitorInfo{IPAddress: "203.0.113.7", UserAgent: tt.userAgent}}
+		got := svc.chooseDestination(link, event)
+		if got.URL != tt.wantURL || got.FallbackURL != tt.wantFallback || event.Variant != tt.wantVariant {
+			t.Errorf("%s: got %q (fallback %q, variant %q), want %q (fallback %q, variant %q)", tt.name,
+				got.URL, got.FallbackURL, event.Variant, tt.wantURL, tt.wantFallback, tt.wantVariant)
+		}
+		if event.Location == nil || event.Location.Country != tt.country {
+			t.Errorf("%s: location not kept on the click event", tt.name)
+		}
+	}
+}
+
+func newCappedRedirectService(link *domain.URL) (RedirectService, *fakeURLRepo) {
+	urls := newFakeURLRepo(link)
+	cfg := configs.Config{Server: configs.ServerConfig{BaseURL: "https://sho.rt"}}
+	return NewRedirectService(urls, newFakeDomainRepo(), &fakeClickTracker{}, fixedGeoIP{}, nil, cfg), urls
+}
+
+func TestBurnAfterReadingIgnoresLinkPreviews(t *testing.T) {
+	maxClicks := 1
+	link := &domain.URL{ShortCode: "once", OriginalURL: "https://example.com/secret", IsActive: true, MaxClicks: &maxClicks}
+	svc, urls := newCappedRedirectService(link)
+	const preview = "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"
+	const browser = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+	if _, err := svc.ProcessRedirect("sho.rt", "once", VisitorInfo{UserAgent: preview}, ""); err == nil {
+		t.Error("link preview was redirected")
+	}
+	result, err := svc.ProcessRedirect("sho.rt", "once", VisitorInfo{UserAgent: browser}, "")
+	if err != nil {
+		t.Fatalf("first visitor after the preview: %v", err)
+	}
+	if result.URL != "https://example.com/secret" {
+		t.Errorf("URL = %q", result.URL)
+	}
+	if _, err := svc.ProcessRedirect("sho.rt", "once", VisitorInfo{UserAgent: browser}, ""); err == nil || err.Error() != "URL_NOT_FOUND" {
+		t.Errorf("second visitor: error = %v, want URL_NOT_FOUND", err)
+	}
+	if stored := urls.get(link.ID); stored.IsActive || stored.RedirectCount != 1 {
+		t.Errorf("stored link is active=%v with %d redirects, want deactivated after 1", stored.IsActive, stored.RedirectCount)
+	}
+}
+
+func TestClickCapSendsConcurrentVisitorsOverTheCapToTheFallback(t *testing.T) {
+	maxClicks := 5
+	fallback := "https://example.com/sold-out"
+	link := &domain.URL{ShortCode: "drop", OriginalURL: "https://example.com/drop", IsActive: true, MaxClicks: &maxClicks, CapFallbackURL: &fallback}
+	svc, _ := newCappedRedirectService(link)
+
+	var mu sync.Mutex
+	destinations := map[string]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := svc.ProcessRedirect("sho.rt", "drop", VisitorInfo{UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0"}, "")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			destinations[result.URL]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if destinations["https://example.com/drop"] != 5 || destinations[fallback] != 15 {
+		t.Errorf("destinations = %v, want 5 to the link and 15 to the fallback", destinations)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BulkTagResult struct {
+	Added   int64
+	Removed int64
+}
+
+type TagService interface {
+	CreateTag(userID uuid.UUID, req request.CreateTagRequest) (*domain.Tag, error)
+	GetUserTags(userID uuid.UUID) ([]domain.Tag, error)
+	GetTag(tagID, userID uuid.UUID) (*domain.Tag, error)
+	UpdateTag(tagID, userID uuid.UUID, req request.UpdateTagRequest) (*domain.Tag, error)
+	DeleteTag(tagID, userID uuid.UUID) error
+	BulkTagURLs(userID uuid.UUID, req request.BulkTagRequest) (*BulkTagResult, error)
+}
+
+type tagService struct {
+	tagRepo domain.TagRepository
+}
+
+func NewTagService(tagRepo domain.TagRepository) TagService {
+	return &tagService{tagRepo: tagRepo}
+}
+
+func (s *tagService) CreateTag(userID uuid.UUID, req request.CreateTagRequest) (*domain.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkNameAvailable(userID, name, nil); err != nil {
+		return nil, err
+	}
+
+	tag := &domain.Tag{UserID: userID, Name: name}
+	if err := s.tagRepo.Store(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) GetUserTags(userID uuid.UUID) ([]domain.Tag, error) {
+	return s.tagRepo.FindAllByUserID(userID)
+}
+
+func (s *tagService) GetTag(tagID, userID uuid.UUID) (*domain.Tag, error) {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("TAG_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, errors.New("TAG_FORBIDDEN")
+	}
+	return tag, nil
+}
+
+func (s *tagService) UpdateTag(tagID, userID uuid.UUID, req request.UpdateTagRequest) (*domain.Tag, error) {
+	tag, err := s.GetTag(tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkNameAvailable(userID, name, &tag.ID); err != nil {
+		return nil, err
+	}
+	tag.Name = name
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag removes the tag from every link that has it.
+func (s *tagService) DeleteTag(tagID, userID uuid.UUID) error {
+	tag, err := s.GetTag(tagID, userID)
+	if err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(tag)
+}
+
+// BulkTagURLs adds and then removes tags, so a tag in both lists ends up
+// removed. Every tag must be the user's; links that are not are skipped.
+func (s *tagService) BulkTagURLs(userID uuid.UUID, req request.BulkTagRequest) (*BulkTagResult, error) {
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		return nil, errors.New("TAG_NOTHING_TO_DO")
+	}
+
+	urlIDs := uniqueIDs(req.URLIDs)
+	add := uniqueIDs(req.Add)
+	remove := uniqueIDs(req.Remove)
+	if err := s.checkTagsOwned(userID, append(append([]uuid.UUID{}, add...), remove...)); err != nil {
+		return nil, err
+	}
+
+	result := &BulkTagResult{}
+	if len(add) > 0 {
+		added, err := s.tagRepo.AddToURLs(userID, urlIDs, add)
+		if err != nil {
+			return nil, err
+		}
+		result.Added = added
+	}
+	if len(remove) > 0 {
+		removed, err := s.tagRepo.RemoveFromURLs(userID, urlIDs, remove)
+		if err != nil {
+			return nil, err
+		}
+		result.Removed = removed
+	}
+	return result, nil
+}
+
+func (s *tagService) checkTagsOwned(userID uuid.UUID, tagIDs []uuid.UUID) error {
+	ids := uniqueIDs(tagIDs)
+	tags, err := s.tagRepo.FindByIDs(userID, ids)
+	if err != nil {
+		return err
+	}
+	if len(tags) != len(ids) {
+		return errors.New("TAG_NOT_FOUND")
+	}
+	return nil
+}
+
+// checkNameAvailable reports TAG_ALREADY_EXISTS when another of the user's
+// tags has the name, ignoring case.
+func (s *tagService) checkNameAvailable(userID uuid.UUID, name string, self *uuid.UUID) error {
+	if name == "" {
+		return errors.New("TAG_NAME_REQUIRED")
+	}
+	existing, err := s.tagRepo.FindByUserIDAndName(userID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if self != nil && existing.ID == *self {
+		return nil
+	}
+	return errors.New("TAG_ALREADY_EXISTS")
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeTagRepo struct {
+	domain.TagRepository
+	tags    map[uuid.UUID]domain.Tag
+	added   [][]uuid.UUID
+	removed [][]uuid.UUID
+}
+
+func newFakeTagRepo(tags ...domain.Tag) *fakeTagRepo {
+	r := &fakeTagRepo{tags: map[uuid.UUID]domain.Tag{}}
+	for _, tag := range tags {
+		r.tags[tag.ID] = tag
+	}
+	return r
+}
+
+func (r *fakeTagRepo) Store(tag *domain.Tag) error {
+	tag.ID = uuid.New()
+	r.tags[tag.ID] = *tag
+	return nil
+}
+
+func (r *fakeTagRepo) FindByID(id uuid.UUID) (*domain.Tag, error) {
+	tag, ok := r.tags[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &tag, nil
+}
+
+func (r *fakeTagRepo) FindByIDs(userID uuid.UUID, ids []uuid.UUID) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	for _, id := range ids {
+		if tag, ok := r.tags[id]; ok && tag.UserID == userID {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (r *fakeTagRepo) FindByUserIDAndName(userID uuid.UUID, name string) (*domain.Tag, error) {
+	for _, tag := range r.tags {
+		if tag.UserID == userID && strings.EqualFold(tag.Name, name) {
+			return &tag, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeTagRepo) Update(tag *domain.Tag) error {
+	r.tags[tag.ID] = *tag
+	return nil
+}
+
+func (r *fakeTagRepo) AddToURLs(_ uuid.UUID, _, tagIDs []uuid.UUID) (int64, error) {
+	r.added = append(r.added, tagIDs)
+	return int64(len(tagIDs)), nil
+}
+
+func (r *fakeTagRepo) RemoveFromURLs(_ uuid.UUID, _, tagIDs []uuid.UUID) (int64, error) {
+	r.removed = append(r.removed, tagIDs)
+	return int64(len(tagIDs)), nil
+}
+
+func TestTagNamesAreUniquePerUserIgnoringCase(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	repo := newFakeTagRepo()
+	svc := NewTagService(repo)
+
+	tag, err := svc.CreateTag(owner, request.CreateTagRequest{Name: "  Summer  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Name != "Summer" {
+		t.Errorf("Name = %q, want it trimmed", tag.Name)
+	}
+	if _, err := svc.CreateTag(owner, request.CreateTagRequest{Name: "SUMMER"}); err == nil || err.Error() != "TAG_ALREADY_EXISTS" {
+		t.Errorf("duplicate name: error = %v, want TAG_ALREADY_EXISTS", err)
+	}
+	if _, err := svc.CreateTag(owner, request.CreateTagRequest{Name: "   "}); err == nil || err.Error() != "TAG_NAME_REQUIRED" {
+		t.Errorf("blank name: error = %v, want TAG_NAME_REQUIRED", err)
+	}
+	if _, err := svc.CreateTag(other, request.CreateTagRequest{Name: "summer"}); err != nil {
+		t.Errorf("same name for another user: %v", err)
+	}
+
+	if _, err := svc.UpdateTag(tag.ID, owner, request.UpdateTagRequest{Name: "summer"}); err != nil {
+		t.Errorf("renaming a tag to its own name in another case: %v", err)
+	}
+	if _, err := svc.UpdateTag(tag.ID, other, request.UpdateTagRequest{Name: "mine"}); err == nil || err.Error() != "TAG_FORBIDDEN" {
+		t.Errorf("rename by another user: error = %v, want TAG_FORBIDDEN", err)
+	}
+}
+
+func TestBulkTagURLs(t *testing.T) {
+	owner := uuid.New()
+	mine := domain.Tag{ID: uuid.New(), UserID: owner, Name: "mine"}
+	theirs := domain.Tag{ID: uuid.New(), UserID: uuid.New(), Name: "theirs"}
+	repo := newFakeTagRepo(mine, theirs)
+	svc := NewTagService(repo)
+	links := []uuid.UUID{uuid.New(), uuid.New()}
+
+	if _, err := svc.BulkTagURLs(owner, request.BulkTagRequest{URLIDs: links}); err == nil || err.Error() != "TAG_NOTHING_TO_DO" {
+		t.Errorf("no tags: error = %v, want TAG_NOTHING_TO_DO", err)
+	}
+	if _, err := svc.BulkTagURLs(owner, request.BulkTagRequest{URLIDs: links, Add: []uuid.UUID{mine.ID, theirs.ID}}); err == nil || err.Error() != "TAG_NOT_FOUND" {
+		t.Errorf("another user's tag: error = %v, want TAG_NOT_FOUND", err)
+	}
+	if len(repo.added) != 0 {
+		t.Fatal("tags were added although one was not the user's")
+	}
+
+	result, err := svc.BulkTagURLs(owner, request.BulkTagRequest{URLIDs: links, Add: []uuid.UUID{mine.ID, mine.ID}, Remove: []uuid.UUID{mine.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Added != 1 || result.Removed != 1 {
+		t.Errorf("result = %+v, want the duplicate tag added once and then removed", result)
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+// unlockSigner issues and checks the tokens of unlocked password-protected
+// links. A token names its expiry and is signed together with the link's ID
+// and password fingerprint, so it opens no other link and changing the
+// password revokes every earlier unlock.
+type unlockSigner struct {
+	secret []byte
+}
+
+func newUnlockSigner(cfg configs.Config) *unlockSigner {
+	secret := cfg.Unlock.Secret
+	if secret == "" {
+		secret = cfg.JWT.SecretKey
+	}
+	return &unlockSigner{secret: []byte(secret)}
+}
+
+// Sign returns a token for url that is valid until expiresAt, in the form
+// "<expiry unix seconds>.<signature>".
+func (u *unlockSigner) Sign(url *domain.URL, expiresAt time.Time) string {
+	expiry := expiresAt.Unix()
+	return strconv.FormatInt(expiry, 10) + "." + base64.RawURLEncoding.EncodeToString(u.mac(url, expiry))
+}
+
+// Verify reports whether token was issued for url and is still valid at
+// now.
+func (u *unlockSigner) Verify(url *domain.URL, token string, now time.Time) bool {
+	if !url.IsPasswordProtected() {
+		return false
+	}
+	expiryPart, signaturePart, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expiry, err := strconv.ParseInt(expiryPart, 10, 64)
+	if err != nil || now.Unix() >= expiry {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signature, u.mac(url, expiry))
+}
+
+func (u *unlockSigner) mac(url *domain.URL, expiry int64) []byte {
+	mac := hmac.New(sha256.New, u.secret)
+	mac.Write([]byte("unlock|" + url.ID.String() + "|" + strconv.FormatInt(expiry, 10) + "|" + url.PasswordVersion()))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestUnlockSigner(t *testing.T) {
+	signer := newUnlockSigner(configs.Config{Unlock: configs.UnlockConfig{Secret: "unlock-secret"}})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hash := "$2a$10$first"
+	link := &domain.URL{ID: uuid.New(), PasswordHash: &hash}
+	token := signer.Sign(link, now.Add(time.Hour))
+
+	if !signer.Verify(link, token, now) {
+		t.Fatal("fresh token rejected")
+	}
+	if signer.Verify(link, token, now.Add(time.Hour)) {
+		t.Error("token accepted at its expiry")
+	}
+
+	// The cache's copy of the link carries only the fingerprint.
+	cachedCopy := &domain.URL{ID: link.ID, PasswordFingerprint: link.PasswordVersion()}
+	if !signer.Verify(cachedCopy, token, now) {
+		t.Error("token rejected on the cached copy of the link")
+	}
+
+	changed := "$2a$10$second"
+	if signer.Verify(&domain.URL{ID: link.ID, PasswordHash: &changed}, token, now) {
+		t.Error("token accepted after the password changed")
+	}
+	if signer.Verify(&domain.URL{ID: link.ID}, token, now) {
+		t.Error("token accepted after the password was removed")
+	}
+	if signer.Verify(&domain.URL{ID: uuid.New(), PasswordHash: &hash}, token, now) {
+		t.Error("token accepted for another link")
+	}
+
+	other := newUnlockSigner(configs.Config{Unlock: configs.UnlockConfig{Secret: "other-secret"}})
+	if other.Verify(link, token, now) {
+		t.Error("token accepted under another secret")
+	}
+
+	// A signature moved onto a later expiry must not verify either.
+	_, signature, _ := strings.Cut(token, ".")
+	for _, forged := range []string{"", "nodot", "notanumber.sig", "9999999999.!!!", "9999999999." + signature} {
+		if signer.Verify(link, forged, now) {
+			t.Errorf("forged token %q accepted", forged)
+		}
+	}
+}
+
+func TestUnlockSignerFallsBackToJWTSecret(t *testing.T) {
+	hash := "$2a$10$first"
+	link := &domain.URL{ID: uuid.New(), PasswordHash: &hash}
+	expiresAt := time.Now().Add(time.Hour)
+
+	fallback := newUnlockSigner(configs.Config{JWT: configs.JWTConfig{SecretKey: "jwt-secret"}})
+	explicit := newUnlockSigner(configs.Config{Unlock: configs.UnlockConfig{Secret: "jwt-secret"}})
+	if fallback.Sign(link, expiresAt) != explicit.Sign(link, expiresAt) {
+		t.Error("signer without an unlock secret does not use the JWT secret")
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+// urlCursorPayload is the content of an opaque list cursor. The sort field
+// and order are included so a cursor cannot be replayed against a list
+// sorted differently, where its position would be meaningless.
+type urlCursorPayload struct {
+	SortBy string    `json:"s"`
+	Order  string    `json:"o"`
+	Value  string    `json:"v"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func encodeURLCursor(sortBy, order string, last *domain.URL) string {
+	data, _ := json.Marshal(urlCursorPayload{
+		SortBy: sortBy,
+		Order:  order,
+		Value:  last.SortValue(sortBy),
+		ID:     last.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeURLCursor(cursor, sortBy, order string) (*domain.URLCursor, error) {
+	invalid := errors.New("URL_INVALID_CURSOR")
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var payload urlCursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, invalid
+	}
+	if payload.SortBy != sortBy || payload.Order != order || payload.ID == uuid.Nil {
+		return nil, invalid
+	}
+
+	switch sortBy {
+	case domain.URLSortCreatedAt, domain.URLSortLastClickedAt:
+		if _, err := time.Parse(time.RFC3339Nano, payload.Value); err != nil {
+			return nil, invalid
+		}
+	case domain.URLSortClickCount, domain.URLSortUniqueClickCount:
+		if _, err := strconv.ParseInt(payload.Value, 10, 64); err != nil {
+			return nil, invalid
+		}
+	}
+	return &domain.URLCursor{SortValue: payload.Value, ID: payload.ID}, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestURLCursorRoundTrip(t *testing.T) {
+	title := "Spring sale"
+	clickedAt := time.Date(2026, 3, 1, 9, 30, 0, 123456789, time.FixedZone("WIB", 7*3600))
+	link := &domain.URL{
+		ID:               uuid.New(),
+		Title:            &title,
+		ClickCount:       42,
+		UniqueClickCount: 17,
+		CreatedAt:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		LastClickedAt:    &clickedAt,
+	}
+	want := map[string]string{
+		domain.URLSortCreatedAt:        "2026-02-01T00:00:00Z",
+		domain.URLSortClickCount:       "42",
+		domain.URLSortUniqueClickCount: "17",
+		domain.URLSortTitle:            "Spring sale",
+		domain.URLSortLastClickedAt:    "2026-03-01T02:30:00.123456789Z",
+	}
+
+	for _, sortBy := range domain.URLSortFields {
+		cursor, err := decodeURLCursor(encodeURLCursor(sortBy, "desc", link), sortBy, "desc")
+		if err != nil {
+			t.Errorf("%s: %v", sortBy, err)
+			continue
+		}
+		if cursor.ID != link.ID || cursor.SortValue != want[sortBy] {
+			t.Errorf("%s: cursor = %+v, want value %q and the link's ID", sortBy, cursor, want[sortBy])
+		}
+	}
+}
+
+func TestURLCursorSortValueOfEmptyFields(t *testing.T) {
+	link := &domain.URL{ID: uuid.New()}
+	if got := link.SortValue(domain.URLSortTitle); got != "" {
+		t.Errorf("title sort value = %q, want empty", got)
+	}
+	if got := link.SortValue(domain.URLSortLastClickedAt); got != "1970-01-01T00:00:00Z" {
+		t.Errorf("last clicked sort value = %q, want the epoch", got)
+	}
+}
+
+func TestDecodeURLCursorRejects(t *testing.T) {
+	link := &domain.URL{ID: uuid.New(), ClickCount: 3}
+	cursor := encodeURLCursor(domain.URLSortClickCount, "desc", link)
+	encode := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		sortBy string
+		order  string
+	}{
+		{"another sort field", cursor, domain.URLSortCreatedAt, "desc"},
+		{"another order", cursor, domain.URLSortClickCount, "asc"},
+		{"not base64", "%%%", domain.URLSortClickCount, "desc"},
+		{"not JSON", encode("nope"), domain.URLSortClickCount, "desc"},
+		{"no ID", encode(`{"s":"click_count","o":"desc","v":"3"}`), domain.URLSortClickCount, "desc"},
+		{"count that is no number", encode(`{"s":"click_count","o":"desc","v":"3 OR 1=1","id":"` + link.ID.String() + `"}`), domain.URLSortClickCount, "desc"},
+		{"time that is no time", encode(`{"s":"created_at","o":"desc","v":"yesterday","id":"` + link.ID.String() + `"}`), domain.URLSortCreatedAt, "desc"},
+	}
+	for _, tt := range tests {
+		_, err := decodeURLCursor(tt.cursor, tt.sortBy, tt.order)
+		if err == nil || err.Error() != "URL_INVALID_CURSOR" {
+			t.Errorf("%s: error = %v, want URL_INVALID_CURSOR", tt.name, err)
+		}
+	}
+}
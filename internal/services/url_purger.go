@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+// urlPurgeBatchSize bounds each DELETE so a large backlog does not hold
+// locks on the urls and clicks tables for long.
+const urlPurgeBatchSize = 500
+
+type URLPurger interface {
+	Start()
+	Shutdown(ctx context.Context) error
+}
+
+// urlPurger permanently removes links that have been in the trash for longer
+// than the retention period. Purging is idempotent, so running it on several
+// instances at once is harmless.
+type urlPurger struct {
+	urlRepo   domain.URLRepository
+	retention time.Duration
+	interval  time.Duration
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewURLPurger(urlRepo domain.URLRepository, cfg configs.Config) URLPurger {
+	interval, err := time.ParseDuration(cfg.Trash.PurgeInterval)
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &urlPurger{
+		urlRepo:   urlRepo,
+		retention: cfg.Trash.RetentionPeriod(),
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (p *urlPurger) Start() {
+	go p.run()
+}
+
+// Shutdown stops the purger after the batch in progress.
+func (p *urlPurger) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *urlPurger) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge()
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *urlPurger) purge() {
+	cutoff := time.Now().Add(-p.retention)
+	var total int64
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		purged, err := p.urlRepo.PurgeDeletedBefore(cutoff, urlPurgeBatchSize)
+		if err != nil {
+			log.Printf("Error purging deleted URLs: %v", err)
+			return
+		}
+		total += purged
+		if purged < urlPurgeBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Purged %d URLs deleted before %s", total, cutoff.Format(time.RFC3339))
+	}
+}
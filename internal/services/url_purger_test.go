@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+type purgeURLRepo struct {
+	domain.URLRepository
+	batches []int64
+	err     error
+	cutoffs []time.Time
+}
+
+func (r *purgeURLRepo) PurgeDeletedBefore(cutoff time.Time, limit int) (int64, error) {
+	r.cutoffs = append(r.cutoffs, cutoff)
+	if len(r.batches) == 0 {
+		return 0, r.err
+	}
+	purged := r.batches[0]
+	r.batches = r.batches[1:]
+	return purged, nil
+}
+
+func TestURLPurgerPurgesInBatchesUntilDone(t *testing.T) {
+	repo := &purgeURLRepo{batches: []int64{urlPurgeBatchSize, urlPurgeBatchSize, 3, urlPurgeBatchSize}}
+	purger := NewURLPurger(repo, configs.Config{Trash: configs.TrashConfig{Retention: "168h"}}).(*urlPurger)
+
+	before := time.Now()
+	purger.purge()
+
+	if len(repo.cutoffs) != 3 {
+		t.Fatalf("ran %d batches, want 3", len(repo.cutoffs))
+	}
+	want := before.Add(-7 * 24 * time.Hour)
+	if d := repo.cutoffs[0].Sub(want); d < 0 || d > time.Second {
+		t.Errorf("cutoff = %v, want a week ago", repo.cutoffs[0])
+	}
+	for _, cutoff := range repo.cutoffs[1:] {
+		if !cutoff.Equal(repo.cutoffs[0]) {
+			t.Error("cutoff moved between batches")
+		}
+	}
+}
+
+func TestURLPurgerStopsOnError(t *testing.T) {
+	repo := &purgeURLRepo{batches: []int64{urlPurgeBatchSize}, err: errors.New("connection reset")}
+	purger := NewURLPurger(repo, configs.Config{}).(*urlPurger)
+
+	purger.purge()
+	if len(repo.cutoffs) != 2 {
+		t.Errorf("ran %d batches, want to stop at the failing second one", len(repo.cutoffs))
+	}
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
@@ -27,36 +29,387 @@ type URLListResult struct {
 type URLService interface {
 	CreateShortURL(userID uuid.UUID, req request.CreateURLRequest) (*CreateURLResult, error)
 	GetURLDetails(urlID, userID uuid.UUID) (*domain.URL, error)
-	GetUserURLs(userID uuid.UUID, options *domain.FindAllOptions) (*URLListResult, error)
+	GetUserURLs(userID uuid.UUID, req request.ListURLsRequest) (*URLListResult, error)
 	UpdateURL(urlID, userID uuid.UUID, req request.UpdateURLRequest) (*domain.URL, error)
 	DeleteURL(urlID, userID uuid.UUID) error
+	GetTrash(userID uuid.UUID, req request.ListTrashRequest) (*URLListResult, error)
+	RestoreURL(urlID, userID uuid.UUID) (*domain.URL, error)
 }
 
 type urlService struct {
-	urlRepo domain.URLRepository
-	cfg     configs.Config
+	urlRepo      domain.URLRepository
+	domainRepo   domain.DomainRepository
+	userRepo     domain.UserRepository
+	campaignRepo domain.CampaignRepository
+	tagRepo      domain.TagRepository
+	folderRepo   domain.FolderRepository
+	cfg          configs.Config
 }
 
-func NewURLService(urlRepo domain.URLRepository, cfg configs.Config) URLService {
-	return &urlService{urlRepo: urlRepo, cfg: cfg}
+func NewURLService(urlRepo domain.URLRepository, domainRepo domain.DomainRepository, userRepo domain.UserRepository, campaignRepo domain.CampaignRepository, tagRepo domain.TagRepository, folderRepo domain.FolderRepository, cfg configs.Config) URLService {
+	return newURLService(urlRepo, domainRepo, userRepo, campaignRepo, tagRepo, folderRepo, cfg)
+}
+
+// newURLService is NewURLService for callers in this package, such as the
+// bulk runner, that need the unexported helpers.
+func newURLService(urlRepo domain.URLRepository, domainRepo domain.DomainRepository, userRepo domain.UserRepository, campaignRepo domain.CampaignRepository, tagRepo domain.TagRepository, folderRepo domain.FolderRepository, cfg configs.Config) *urlService {
+	return &urlService{urlRepo: urlRepo, domainRepo: domainRepo, userRepo: userRepo, campaignRepo: campaignRepo, tagRepo: tagRepo, folderRepo: folderRepo, cfg: cfg}
+}
+
+// checkLinkQuota enforces the links-per-month quota of the user's plan.
+// Months are calendar months in UTC.
+func (s *urlService) checkLinkQuota(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	quota := s.cfg.RateLimit.Plans.For(user.PlanType)
+	if quota.LinksPerMonth <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	created, err := s.urlRepo.CountCreatedByUserSince(userID, monthStart)
+	if err != nil {
+		return err
+	}
+	if created >= int64(quota.LinksPerMonth) {
+		return errors.New("URL_QUOTA_EXCEEDED")
+	}
+	return nil
+}
+
+// resolveUserDomain looks up a custom domain by name and checks that the user
+// owns it and that it is ready to serve links.
+func (s *urlService) resolveUserDomain(userID uuid.UUID, name string) (*domain.Domain, error) {
+	d, err := s.domainRepo.FindByUserIDAndDomainName(userID, NormalizeDomainName(name))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("URL_DOMAIN_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if !d.IsVerified || !d.IsActive {
+		return nil, errors.New("URL_DOMAIN_NOT_VERIFIED")
+	}
+	return d, nil
+}
+
+// resolveUserCampaign looks up a campaign the user owns.
+func (s *urlService) resolveUserCampaign(userID, campaignID uuid.UUID) (*domain.Campaign, error) {
+	campaign, err := s.campaignRepo.FindByID(campaignID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("URL_CAMPAIGN_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if campaign.UserID != userID {
+		return nil, errors.New("URL_CAMPAIGN_NOT_FOUND")
+	}
+	return campaign, nil
+}
+
+// resolveUserFolder looks up a folder the user owns.
+func (s *urlService) resolveUserFolder(userID, folderID uuid.UUID) (*domain.Folder, error) {
+	folder, err := s.folderRepo.FindByID(folderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("URL_FOLDER_NOT_FOUND")
+		}
+		return nil, err
+	}
+	if folder.UserID != userID {
+		return nil, errors.New("URL_FOLDER_NOT_FOUND")
+	}
+	return folder, nil
+}
+
+// resolveUserTags looks up tags the user owns. Repeated IDs count once.
+func (s *urlService) resolveUserTags(userID uuid.UUID, tagIDs []uuid.UUID) ([]domain.Tag, error) {
+	ids := uniqueIDs(tagIDs)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	tags, err := s.tagRepo.FindByIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(ids) {
+		return nil, errors.New("URL_TAG_NOT_FOUND")
+	}
+	return tags, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// checkDestination rejects a destination visitors are sent to that is not
+// an absolute http(s) URL with URL_INVALID_DESTINATION. The url binding
+// takes any scheme, javascript: included, and the app redirect page opens
+// fallbacks from script.
+func checkDestination(rawURL string) error {
+	if !utils.IsValidURL(rawURL) {
+		return errors.New("URL_INVALID_DESTINATION")
+	}
+	return nil
+}
+
+// checkOptionalDestination is checkDestination for a destination that may
+// be left out or, in an update, cleared with "".
+func checkOptionalDestination(rawURL *string) error {
+	if rawURL == nil || *rawURL == "" {
+		return nil
+	}
+	return checkDestination(*rawURL)
+}
+
+// geoRules validates and normalises requested rules: countries are
+// upper-cased to match GeoIP codes and blank regions are dropped.
+func geoRules(req []request.GeoRuleRequest) ([]domain.GeoRule, error) {
+	if len(req) == 0 {
+		return nil, nil
+	}
+	rules := make([]domain.GeoRule, len(req))
+	for i, r := range req {
+		if err := checkDestination(r.DestinationURL); err != nil {
+			return nil, err
+		}
+		rules[i] = domain.GeoRule{
+			Country:        strings.ToUpper(r.Country),
+			Region:         strings.TrimSpace(r.Region),
+			DestinationURL: r.DestinationURL,
+		}
+	}
+	return rules, nil
+}
+
+// deviceRules validates and copies requested rules. Deep links are opened
+// by script from the redirect page, so schemes a browser would run or read
+// locally are rejected with URL_INVALID_DEEP_LINK.
+func deviceRules(req []request.DeviceRuleRequest) ([]domain.DeviceRule, error) {
+	if len(req) == 0 {
+		return nil, nil
+	}
+	rules := make([]domain.DeviceRule, len(req))
+	for i, r := range req {
+		rule := domain.DeviceRule{
+			Platform:       r.Platform,
+			DestinationURL: strings.TrimSpace(r.DestinationURL),
+			FallbackURL:    strings.TrimSpace(r.FallbackURL),
+		}
+		if !utils.IsSafeDeepLink(rule.DestinationURL) {
+			return nil, errors.New("URL_INVALID_DEEP_LINK")
+		}
+		if err := checkOptionalDestination(&rule.FallbackURL); err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+	return rules, nil
+}
+
+// splitDestinations validates and copies requested split arms. A split
+// needs two arms at least; one alone is rejected with URL_INVALID_SPLIT
+// rather than silently acting as a plain link.
+func splitDestinations(req []request.SplitDestinationRequest) ([]domain.SplitDestination, error) {
+	if len(req) == 0 {
+		return nil, nil
+	}
+	if len(req) < 2 {
+		return nil, errors.New("URL_INVALID_SPLIT")
+	}
+	destinations := make([]domain.SplitDestination, len(req))
+	for i, d := range req {
+		if err := checkDestination(d.DestinationURL); err != nil {
+			return nil, err
+		}
+		destinations[i] = domain.SplitDestination{
+			Name:           strings.TrimSpace(d.Name),
+			DestinationURL: d.DestinationURL,
+			Weight:         d.Weight,
+		}
+	}
+	return destinations, nil
+}
+
+// checkLiveRange rejects a link that would expire before it goes live.
+func checkLiveRange(startsAt, expiresAt *time.Time) error {
+	if startsAt != nil && expiresAt != nil && !startsAt.Before(*expiresAt) {
+		return errors.New("URL_INVALID_SCHEDULE")
+	}
+	return nil
+}
+
+// schedule copies a requested schedule; one without windows means none.
+func schedule(req *request.ScheduleRequest) *domain.Schedule {
+	if req == nil || len(req.Windows) == 0 {
+		return nil
+	}
+	windows := make([]domain.ScheduleWindow, len(req.Windows))
+	for i, w := range req.Windows {
+		windows[i] = domain.ScheduleWindow{Days: w.Days, Start: w.Start, End: w.End}
+	}
+	return &domain.Schedule{TimeZone: req.TimeZone, Windows: windows}
+}
+
+// expiredMessage trims the message; a blank one means the default.
+func expiredMessage(req *string) *string {
+	if req == nil {
+		return nil
+	}
+	message := strings.TrimSpace(*req)
+	if message == "" {
+		return nil
+	}
+	return &message
+}
+
+// maxClicks returns the requested click cap; burn after reading is a cap of
+// one.
+func maxClicks(req request.CreateURLRequest) *int {
+	if req.BurnAfterReading {
+		one := 1
+		return &one
+	}
+	return req.MaxClicks
+}
+
+// taggedDestination merges the campaign's UTM parameters and those of the
+// request, which take precedence, into the destination URL.
+func taggedDestination(originalURL string, campaign *domain.Campaign, req *request.UTMRequest) (string, error) {
+	var utm domain.UTMParams
+	if campaign != nil {
+		utm = campaign.UTM
+	}
+	if req != nil {
+		utm = utm.Override(normalizeUTM(*req))
+	}
+
+	params := utmQueryParams(utm)
+	if len(params) == 0 {
+		return originalURL, nil
+	}
+	return utils.SetQueryParams(originalURL, params)
 }
 
 func (s *urlService) CreateShortURL(userID uuid.UUID, req request.CreateURLRequest) (*CreateURLResult, error) {
+	newURL, shortURLString, err := s.createURL(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err := utils.GenerateQRCodeBase64(shortURLString, 256)
+	if err != nil {
+		fmt.Printf("Gagal generate QR Code untuk URL %s: %v\n", newURL.ID, err)
+	}
+
+	return &CreateURLResult{
+		URL:      newURL,
+		QRCode:   qrCode,
+		ShortURL: shortURLString,
+	}, nil
+}
+
+// createURL stores a new link and returns it with its public short URL.
+func (s *urlService) createURL(userID uuid.UUID, req request.CreateURLRequest) (*domain.URL, string, error) {
+	if err := s.checkLinkQuota(userID); err != nil {
+		return nil, "", err
+	}
+
+	var linkDomain *domain.Domain
+	var domainID *uuid.UUID
+	if req.Domain != nil && *req.Domain != "" {
+		d, err := s.resolveUserDomain(userID, *req.Domain)
+		if err != nil {
+			return nil, "", err
+		}
+		linkDomain = d
+		domainID = &d.ID
+	}
+
+	var campaign *domain.Campaign
+	if req.CampaignID != nil {
+		c, err := s.resolveUserCampaign(userID, *req.CampaignID)
+		if err != nil {
+			return nil, "", err
+		}
+		campaign = c
+	}
+	if err := checkDestination(req.OriginalURL); err != nil {
+		return nil, "", err
+	}
+	originalURL, err := taggedDestination(req.OriginalURL, campaign, req.UTM)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if req.FolderID != nil {
+		if _, err := s.resolveUserFolder(userID, *req.FolderID); err != nil {
+			return nil, "", err
+		}
+	}
+	tags, err := s.resolveUserTags(userID, req.TagIDs)
+	if err != nil {
+		return nil, "", err
+	}
+	geos, err := geoRules(req.GeoRules)
+	if err != nil {
+		return nil, "", err
+	}
+	devices, err := deviceRules(req.DeviceRules)
+	if err != nil {
+		return nil, "", err
+	}
+	splits, err := splitDestinations(req.SplitDestinations)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkOptionalDestination(req.ExpiredRedirectURL); err != nil {
+		return nil, "", err
+	}
+	if err := checkOptionalDestination(req.CapFallbackURL); err != nil {
+		return nil, "", err
+	}
+	if err := checkLiveRange(req.StartsAt, req.ExpiresAt); err != nil {
+		return nil, "", err
+	}
+
+	// Codes of links in the trash stay taken, so a deleted link cannot be
+	// re-registered by someone else while it can still be restored.
 	shortCode := ""
-	if req.CustomAlias != nil && *req.CustomAlias != "" {
-		_, err := s.urlRepo.FindByCustomAlias(*req.CustomAlias)
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("URL_CUSTOM_ALIAS_EXISTS")
+	hasAlias := req.CustomAlias != nil && *req.CustomAlias != ""
+	if hasAlias {
+		taken, err := s.urlRepo.IsShortCodeTaken(domainID, *req.CustomAlias)
+		if err != nil {
+			return nil, "", err
+		}
+		if taken {
+			return nil, "", errors.New("URL_CUSTOM_ALIAS_EXISTS")
 		}
 		shortCode = *req.CustomAlias
 	} else {
 		for {
 			newCode, err := utils.GenerateShortCode()
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
-			_, err = s.urlRepo.FindByShortCode(newCode)
-			if errors.Is(err, gorm.ErrRecordNotFound) {
+			taken, err := s.urlRepo.IsShortCodeTaken(domainID, newCode)
+			if err != nil {
+				return nil, "", err
+			}
+			if !taken {
 				shortCode = newCode
 				break
 			}
@@ -67,55 +420,133 @@ func (s *urlService) CreateShortURL(userID uuid.UUID, req request.CreateURLReque
 	if req.Password != nil && *req.Password != "" {
 		hash, err := utils.HashPassword(*req.Password)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		hashedPassword = &hash
 	}
 
 	newURL := &domain.URL{
-		UserID:       &userID,
-		OriginalURL:  req.OriginalURL,
-		ShortCode:    shortCode,
-		CustomAlias:  req.CustomAlias,
-		Title:        req.Title,
-		Description:  req.Description,
-		ExpiresAt:    req.ExpiresAt,
-		PasswordHash: hashedPassword,
+		UserID:             &userID,
+		OriginalURL:        originalURL,
+		ShortCode:          shortCode,
+		CustomAlias:        req.CustomAlias,
+		DomainID:           domainID,
+		CampaignID:         req.CampaignID,
+		FolderID:           req.FolderID,
+		Tags:               tags,
+		GeoRules:           geos,
+		DeviceRules:        devices,
+		SplitDestinations:  splits,
+		Title:              req.Title,
+		Description:        req.Description,
+		StartsAt:           req.StartsAt,
+		ExpiresAt:          req.ExpiresAt,
+		Schedule:           schedule(req.Schedule),
+		ExpiredRedirectURL: req.ExpiredRedirectURL,
+		ExpiredMessage:     expiredMessage(req.ExpiredMessage),
+		MaxClicks:          maxClicks(req),
+		CapFallbackURL:     req.CapFallbackURL,
+		PasswordHash:       hashedPassword,
 	}
 
 	if err := s.urlRepo.Store(newURL); err != nil {
-		return nil, err
+		// Another request can take the alias between the check and the insert.
+		if hasAlias && errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, "", errors.New("URL_CUSTOM_ALIAS_EXISTS")
+		}
+		return nil, "", err
 	}
+	newURL.Domain = linkDomain
 
-	shortURLString := fmt.Sprintf("%s/%s", s.cfg.Server.BaseURL, newURL.ShortCode)
-	qrCode, err := utils.GenerateQRCodeBase64(shortURLString, 256)
-	if err != nil {
-		fmt.Printf("Gagal generate QR Code untuk URL %s: %v\n", newURL.ID, err)
+	return newURL, utils.BuildShortURL(s.cfg.Server.BaseURL, newURL.DomainName(), newURL.ShortCode), nil
+}
+
+// GetUserURLs returns one page of the user's links. Pages can be addressed
+// by number or, to avoid offset scans and drift while links are added, by
+// the cursor returned with the previous page.
+func (s *urlService) GetUserURLs(userID uuid.UUID, req request.ListURLsRequest) (*URLListResult, error) {
+	options := &domain.FindAllOptions{
+		Search:            req.Search,
+		SortBy:            req.Sort,
+		Order:             req.Order,
+		Limit:             req.Limit + 1, // the extra row tells whether another page follows
+		Offset:            (req.Page - 1) * req.Limit,
+		IsActive:          req.IsActive,
+		Expired:           req.Expired,
+		PasswordProtected: req.PasswordProtected,
+		CreatedFrom:       req.CreatedFrom,
+		CreatedTo:         req.CreatedTo,
+		ClickedFrom:       req.ClickedFrom,
+		ClickedTo:         req.ClickedTo,
 	}
 
-	return &CreateURLResult{
-		URL:      newURL,
-		QRCode:   qrCode,
-		ShortURL: shortURLString,
-	}, nil
-}
+	if req.Cursor != "" {
+		after, err := decodeURLCursor(req.Cursor, req.Sort, req.Order)
+		if err != nil {
+			return nil, err
+		}
+		options.After = after
+	}
+
+	switch req.Domain {
+	case "":
+	case "default":
+		options.DefaultDomainOnly = true
+	default:
+		d, err := s.domainRepo.FindByUserIDAndDomainName(userID, NormalizeDomainName(req.Domain))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("URL_DOMAIN_NOT_FOUND")
+			}
+			return nil, err
+		}
+		options.DomainID = &d.ID
+	}
+
+	if req.CampaignID != "" {
+		campaignID, err := uuid.Parse(req.CampaignID)
+		if err != nil {
+			return nil, errors.New("URL_CAMPAIGN_NOT_FOUND")
+		}
+		options.CampaignID = &campaignID
+	}
+
+	for _, raw := range req.TagID {
+		tagID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("URL_TAG_NOT_FOUND")
+		}
+		options.TagIDs = append(options.TagIDs, tagID)
+	}
+
+	switch req.FolderID {
+	case "":
+	case "none":
+		options.NoFolderOnly = true
+	default:
+		folderID, err := uuid.Parse(req.FolderID)
+		if err != nil {
+			return nil, errors.New("URL_FOLDER_NOT_FOUND")
+		}
+		options.FolderID = &folderID
+	}
 
-func (s *urlService) GetUserURLs(userID uuid.UUID, options *domain.FindAllOptions) (*URLListResult, error) {
 	urls, total, err := s.urlRepo.FindAllByUserID(userID, options)
 	if err != nil {
 		return nil, err
 	}
 
-	totalPages := 0
-	if options.Limit > 0 {
-		totalPages = int((total + int64(options.Limit) - 1) / int64(options.Limit))
-	}
-
 	pagination := response.PaginationResponse{
-		Page:       (options.Offset / options.Limit) + 1,
-		Limit:      options.Limit,
+		Limit:      req.Limit,
 		Total:      total,
-		TotalPages: totalPages,
+		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+	}
+	if options.After == nil {
+		pagination.Page = req.Page
+	}
+	if len(urls) > req.Limit {
+		urls = urls[:req.Limit]
+		pagination.NextCursor = encodeURLCursor(req.Sort, req.Order, &urls[len(urls)-1])
 	}
 
 	return &URLListResult{
@@ -152,16 +583,102 @@ func (s *urlService) UpdateURL(urlID, userID uuid.UUID, req request.UpdateURLReq
 	if req.Description != nil {
 		url.Description = req.Description
 	}
+	if req.StartsAt != nil {
+		url.StartsAt = req.StartsAt
+	}
 	if req.ExpiresAt != nil {
 		url.ExpiresAt = req.ExpiresAt
 	}
+	if err := checkLiveRange(url.StartsAt, url.ExpiresAt); err != nil {
+		return nil, err
+	}
+	if req.Schedule != nil {
+		url.Schedule = schedule(req.Schedule)
+	}
+	if req.ExpiredRedirectURL != nil {
+		if err := checkOptionalDestination(req.ExpiredRedirectURL); err != nil {
+			return nil, err
+		}
+		url.ExpiredRedirectURL = req.ExpiredRedirectURL
+		if *req.ExpiredRedirectURL == "" {
+			url.ExpiredRedirectURL = nil
+		}
+	}
+	if req.ExpiredMessage != nil {
+		url.ExpiredMessage = expiredMessage(req.ExpiredMessage)
+	}
 	if req.IsActive != nil {
 		url.IsActive = *req.IsActive
 	}
+	if req.MaxClicks != nil {
+		url.MaxClicks = req.MaxClicks
+		if *req.MaxClicks == 0 {
+			url.MaxClicks = nil
+		}
+	}
+	if req.CapFallbackURL != nil {
+		if err := checkOptionalDestination(req.CapFallbackURL); err != nil {
+			return nil, err
+		}
+		url.CapFallbackURL = req.CapFallbackURL
+		if *req.CapFallbackURL == "" {
+			url.CapFallbackURL = nil
+		}
+	}
+	if req.FolderID != nil {
+		url.FolderID = nil
+		if *req.FolderID != "" {
+			folderID, err := uuid.Parse(*req.FolderID)
+			if err != nil {
+				return nil, errors.New("URL_FOLDER_NOT_FOUND")
+			}
+			if _, err := s.resolveUserFolder(userID, folderID); err != nil {
+				return nil, err
+			}
+			url.FolderID = &folderID
+		}
+	}
+
+	if req.GeoRules != nil {
+		url.GeoRules, err = geoRules(*req.GeoRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.DeviceRules != nil {
+		url.DeviceRules, err = deviceRules(*req.DeviceRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.SplitDestinations != nil {
+		url.SplitDestinations, err = splitDestinations(*req.SplitDestinations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []domain.Tag
+	if req.TagIDs != nil {
+		tags, err = s.resolveUserTags(userID, *req.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.urlRepo.Update(url); err != nil {
 		return nil, err
 	}
+	if req.TagIDs != nil {
+		tagIDs := make([]uuid.UUID, len(tags))
+		for i, tag := range tags {
+			tagIDs[i] = tag.ID
+		}
+		if err := s.tagRepo.ReplaceURLTags(url.ID, tagIDs); err != nil {
+			return nil, err
+		}
+		url.Tags = tags
+	}
 	return url, nil
 }
 
@@ -176,3 +693,39 @@ func (s *urlService) DeleteURL(urlID, userID uuid.UUID) error {
 
 	return s.urlRepo.Delete(url)
 }
+
+// GetTrash lists the user's deleted links, most recently deleted first.
+func (s *urlService) GetTrash(userID uuid.UUID, req request.ListTrashRequest) (*URLListResult, error) {
+	urls, total, err := s.urlRepo.FindDeletedByUserID(userID, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &URLListResult{
+		URLs: urls,
+		Pagination: response.PaginationResponse{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			Total:      total,
+			TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+		},
+	}, nil
+}
+
+// RestoreURL takes a link out of the trash. Its short code was reserved
+// while it was deleted, so it comes back at the same address.
+func (s *urlService) RestoreURL(urlID, userID uuid.UUID) (*domain.URL, error) {
+	url, err := s.urlRepo.FindDeletedByID(urlID)
+	if err != nil {
+		return nil, err
+	}
+	if url.UserID == nil || *url.UserID != userID {
+		return nil, errors.New("URL_FORBIDDEN")
+	}
+
+	if err := s.urlRepo.Restore(url); err != nil {
+		return nil, err
+	}
+	url.DeletedAt = gorm.DeletedAt{}
+	return url, nil
+}
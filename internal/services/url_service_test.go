@@ -0,0 +1,222 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/request"
+	"github.com/google/uuid"
+)
+
+func TestGeoRulesNormalisesCountryAndRegion(t *testing.T) {
+	got, err := geoRules([]request.GeoRuleRequest{{Country: "us", Region: " California ", DestinationURL: "https://example.com/ca"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Country != "US" || got[0].Region != "California" {
+		t.Errorf("rules = %+v", got)
+	}
+	if rules, _ := geoRules(nil); rules != nil {
+		t.Error("no rules should stay nil")
+	}
+}
+
+func TestDeviceRulesRejectsUnsafeDeepLinks(t *testing.T) {
+	for _, destination := range []string{"javascript:alert(document.cookie)", " data:text/html,<script>", "file:///etc/passwd"} {
+		_, err := deviceRules([]request.DeviceRuleRequest{{Platform: "ios", DestinationURL: destination}})
+		if err == nil || err.Error() != "URL_INVALID_DEEP_LINK" {
+			t.Errorf("%q: error = %v, want URL_INVALID_DEEP_LINK", destination, err)
+		}
+	}
+
+	rules, err := deviceRules([]request.DeviceRuleRequest{{Platform: "ios", DestinationURL: " myapp://product/42 "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].DestinationURL != "myapp://product/42" {
+		t.Errorf("DestinationURL = %q, want it trimmed", rules[0].DestinationURL)
+	}
+}
+
+func TestSplitPoint(t *testing.T) {
+	link := uuid.MustParse("7f8e4a1c-0d3b-4b7a-9a55-0c1d2e3f4a5b")
+	other := uuid.MustParse("1b2c3d4e-5f60-4718-8a9b-0c1d2e3f4a5b")
+	visitor := VisitorInfo{VisitorID: "v-1", IPAddress: "203.0.113.7", UserAgent: "Firefox"}
+
+	if splitPoint(link, visitor) != splitPoint(link, VisitorInfo{VisitorID: "v-1", IPAddress: "198.51.100.1"}) {
+		t.Error("visitor cookie does not pin the split point when the IP changes")
+	}
+	anonymous := VisitorInfo{IPAddress: "203.0.113.7", UserAgent: "Firefox"}
+	if splitPoint(link, anonymous) != splitPoint(link, anonymous) {
+		t.Error("split point without a cookie is not stable")
+	}
+	if splitPoint(link, anonymous) == splitPoint(link, VisitorInfo{IPAddress: "203.0.113.7", UserAgent: "Safari"}) {
+		t.Error("split point without a cookie ignores the user agent")
+	}
+	if splitPoint(link, visitor) == splitPoint(other, visitor) {
+		t.Error("visitor has the same split point on every link")
+	}
+
+	// Visitors spread over the arms in proportion to the weights.
+	destinations := []domain.SplitDestination{{Name: "A", Weight: 70}, {Name: "B", Weight: 30}}
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		point := splitPoint(link, VisitorInfo{VisitorID: "visitor-" + strconv.Itoa(i)})
+		counts[domain.PickSplitDestination(destinations, point).Name]++
+	}
+	if counts["A"] < 6700 || counts["A"] > 7300 {
+		t.Errorf("arm A got %d of 10000 visitors, want about 7000", counts["A"])
+	}
+}
+
+func TestSplitDestinationsNeedTwoArms(t *testing.T) {
+	_, err := splitDestinations([]request.SplitDestinationRequest{{Name: "A", DestinationURL: "https://example.com/a", Weight: 1}})
+	if err == nil || err.Error() != "URL_INVALID_SPLIT" {
+		t.Errorf("error = %v, want URL_INVALID_SPLIT", err)
+	}
+
+	got, err := splitDestinations([]request.SplitDestinationRequest{
+		{Name: " A ", DestinationURL: "https://example.com/a", Weight: 1},
+		{Name: "B", DestinationURL: "https://example.com/b", Weight: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "A" || got[1].Weight != 2 {
+		t.Errorf("destinations = %+v", got)
+	}
+}
+
+func TestTaggedDestination(t *testing.T) {
+	campaign := &domain.Campaign{UTM: domain.UTMParams{Source: "newsletter", Medium: "email", Campaign: "spring_sale"}}
+	tests := []struct {
+		name     string
+		campaign *domain.Campaign
+		req      *request.UTMRequest
+		want     string
+	}{
+		{"untagged", nil, nil, "https://example.com/sale?ref=1"},
+		{"campaign defaults", campaign, nil, "https://example.com/sale?ref=1&utm_source=newsletter&utm_medium=email&utm_campaign=spring_sale"},
+		{
+			"request overrides the campaign and is normalised",
+			campaign,
+			&request.UTMRequest{Source: " Twitter ", Content: " banner "},
+			"https://example.com/sale?ref=1&utm_source=twitter&utm_medium=email&utm_campaign=spring_sale&utm_content=banner",
+		},
+		{"blank request values keep the campaign's", campaign, &request.UTMRequest{Medium: "  "}, "https://example.com/sale?ref=1&utm_source=newsletter&utm_medium=email&utm_campaign=spring_sale"},
+	}
+	for _, tt := range tests {
+		got, err := taggedDestination("https://example.com/sale?ref=1", tt.campaign, tt.req)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTaggedDestinationReplacesUTMAlreadyInTheURL(t *testing.T) {
+	got, err := taggedDestination("https://example.com/?utm_source=old&id=7", nil, &request.UTMRequest{Source: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://example.com/?id=7&utm_source=new"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCheckLiveRange(t *testing.T) {
+	launch := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	later := launch.Add(time.Hour)
+
+	if err := checkLiveRange(&launch, &later); err != nil {
+		t.Errorf("launch before expiry: %v", err)
+	}
+	if err := checkLiveRange(nil, &later); err != nil {
+		t.Errorf("expiry alone: %v", err)
+	}
+	for _, expiry := range []time.Time{launch, launch.Add(-time.Hour)} {
+		if err := checkLiveRange(&launch, &expiry); err == nil || err.Error() != "URL_INVALID_SCHEDULE" {
+			t.Errorf("expiry %v: error = %v, want URL_INVALID_SCHEDULE", expiry, err)
+		}
+	}
+}
+
+func TestDestinationsMustBeWebURLs(t *testing.T) {
+	const script = "javascript:alert(document.domain)//x"
+	if _, err := geoRules([]request.GeoRuleRequest{{Country: "US", DestinationURL: script}}); err == nil || err.Error() != "URL_INVALID_DESTINATION" {
+		t.Errorf("geo rule: error = %v, want URL_INVALID_DESTINATION", err)
+	}
+	if _, err := deviceRules([]request.DeviceRuleRequest{{Platform: "ios", DestinationURL: "myapp://x", FallbackURL: script}}); err == nil || err.Error() != "URL_INVALID_DESTINATION" {
+		t.Errorf("device fallback: error = %v, want URL_INVALID_DESTINATION", err)
+	}
+	_, err := splitDestinations([]request.SplitDestinationRequest{
+		{Name: "A", DestinationURL: "https://example.com/a", Weight: 1},
+		{Name: "B", DestinationURL: script, Weight: 1},
+	})
+	if err == nil || err.Error() != "URL_INVALID_DESTINATION" {
+		t.Errorf("split: error = %v, want URL_INVALID_DESTINATION", err)
+	}
+}
+
+func TestCreateAndUpdateRejectScriptDestinations(t *testing.T) {
+	userID := uuid.New()
+	urls := newFakeURLRepo()
+	svc := newURLService(urls, nil, fakeUserRepo{users: map[uuid.UUID]*domain.User{userID: {ID: userID}}}, nil, nil, nil, configs.Config{})
+	script := "javascript:alert(document.domain)//x"
+
+	for name, req := range map[string]request.CreateURLRequest{
+		"original_url":         {OriginalURL: script},
+		"expired_redirect_url": {OriginalURL: "https://example.com", ExpiredRedirectURL: &script},
+		"cap_fallback_url":     {OriginalURL: "https://example.com", CapFallbackURL: &script},
+	} {
+		if _, _, err := svc.createURL(userID, req); err == nil || err.Error() != "URL_INVALID_DESTINATION" {
+			t.Errorf("create with %s: error = %v, want URL_INVALID_DESTINATION", name, err)
+		}
+	}
+	if len(urls.urls) != 0 {
+		t.Fatalf("%d links stored", len(urls.urls))
+	}
+
+	link, _, err := svc.createURL(userID, request.CreateURLRequest{OriginalURL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	geo := []request.GeoRuleRequest{{Country: "US", DestinationURL: script}}
+	if _, err := svc.UpdateURL(link.ID, userID, request.UpdateURLRequest{GeoRules: &geo}); err == nil || err.Error() != "URL_INVALID_DESTINATION" {
+		t.Errorf("update geo rules: error = %v, want URL_INVALID_DESTINATION", err)
+	}
+	if _, err := svc.UpdateURL(link.ID, userID, request.UpdateURLRequest{CapFallbackURL: &script}); err == nil || err.Error() != "URL_INVALID_DESTINATION" {
+		t.Errorf("update cap_fallback_url: error = %v, want URL_INVALID_DESTINATION", err)
+	}
+	if stored := urls.get(link.ID); stored.GeoRules != nil || stored.CapFallbackURL != nil {
+		t.Errorf("rejected update was stored: %+v", stored)
+	}
+}
+
+// racedURLRepo answers every alias check as if a concurrent request had not
+// stored its link yet.
+type racedURLRepo struct {
+	*fakeURLRepo
+}
+
+func (r racedURLRepo) IsShortCodeTaken(*uuid.UUID, string) (bool, error) {
+	return false, nil
+}
+
+func TestCreateReportsAliasTakenByAConcurrentRequest(t *testing.T) {
+	userID := uuid.New()
+	urls := newFakeURLRepo(&domain.URL{ShortCode: "sale", OriginalURL: "https://example.com/first"})
+	svc := newURLService(racedURLRepo{urls}, nil, fakeUserRepo{users: map[uuid.UUID]*domain.User{userID: {ID: userID}}}, nil, nil, nil, configs.Config{})
+
+	alias := "sale"
+	_, _, err := svc.createURL(userID, request.CreateURLRequest{OriginalURL: "https://example.com/second", CustomAlias: &alias})
+	if err == nil || err.Error() != "URL_CUSTOM_ALIAS_EXISTS" {
+		t.Errorf("error = %v, want URL_CUSTOM_ALIAS_EXISTS", err)
+	}
+}
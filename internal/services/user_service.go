@@ -13,15 +13,16 @@ type UserService interface {
 	GetProfile(userID uuid.UUID) (*domain.User, error)
 	UpdateProfile(userID uuid.UUID, req request.UpdateProfileRequest) (*domain.User, error)
 	ChangePassword(userID uuid.UUID, req request.ChangePasswordRequest) error
-	RegenerateAPIKey(userID uuid.UUID) (string, error)
+	UpdatePrivacy(userID uuid.UUID, req request.UpdatePrivacyRequest) (*domain.User, error)
 }
 
 type userService struct {
-	userRepo domain.UserRepository
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
 }
 
-func NewUserService(userRepo domain.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(userRepo domain.UserRepository, sessionRepo domain.SessionRepository) UserService {
+	return &userService{userRepo: userRepo, sessionRepo: sessionRepo}
 }
 
 func (s *userService) GetProfile(userID uuid.UUID) (*domain.User, error) {
@@ -56,21 +57,19 @@ func (s *userService) ChangePassword(userID uuid.UUID, req request.ChangePasswor
 	}
 	user.PasswordHash = newHashedPassword
 
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	return s.sessionRepo.RevokeAllByUserID(userID)
 }
 
-func (s *userService) RegenerateAPIKey(userID uuid.UUID) (string, error) {
+func (s *userService) UpdatePrivacy(userID uuid.UUID, req request.UpdatePrivacyRequest) (*domain.User, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return "", err
-	}
-
-	newAPIKey, err := utils.GenerateAPIKey()
-	if err != nil {
-		return "", err
+		return nil, err
 	}
-	user.APIKey = newAPIKey
+	user.IPPrivacy = req.IPPrivacy
 
 	err = s.userRepo.Update(user)
-	return newAPIKey, err
+	return user, err
 }
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+)
+
+// VisitorIdentifier turns the visitor data captured on a redirect into a
+// stable, non-reversible key that is safe to persist on the click row.
+type VisitorIdentifier interface {
+	Identify(visitor VisitorInfo, at time.Time) string
+}
+
+type visitorIdentifier struct {
+	secret []byte
+}
+
+func NewVisitorIdentifier(cfg configs.Config) VisitorIdentifier {
+	secret := cfg.Visitor.HashSecret
+	if secret == "" {
+		secret = cfg.JWT.SecretKey
+	}
+	return &visitorIdentifier{secret: []byte(secret)}
+}
+
+// Identify prefers the first-party visitor cookie. Without one it falls back
+// to IP + user agent hashed with a per-day salt, so the same visitor cannot be
+// correlated across days.
+func (v *visitorIdentifier) Identify(visitor VisitorInfo, at time.Time) string {
+	if visitor.VisitorID != "" {
+		sum := sha256.Sum256([]byte("cookie:" + visitor.VisitorID))
+		return hex.EncodeToString(sum[:])
+	}
+
+	salt := hmac.New(sha256.New, v.secret)
+	salt.Write([]byte(at.UTC().Format("2006-01-02")))
+
+	mac := hmac.New(sha256.New, salt.Sum(nil))
+	mac.Write([]byte(visitor.IPAddress + "|" + visitor.UserAgent))
+	return hex.EncodeToString(mac.Sum(nil))
+}
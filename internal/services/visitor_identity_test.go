@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestVisitorIdentifier(t *testing.T) {
+	identifier := NewVisitorIdentifier(configs.Config{Visitor: configs.VisitorConfig{HashSecret: "visitor-secret"}})
+	monday := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	anonymous := VisitorInfo{IPAddress: "203.0.113.7", UserAgent: "Firefox"}
+
+	cookie := identifier.Identify(VisitorInfo{VisitorID: "v-1", IPAddress: "203.0.113.7"}, monday)
+	if cookie != identifier.Identify(VisitorInfo{VisitorID: "v-1", IPAddress: "198.51.100.1"}, monday.AddDate(0, 1, 0)) {
+		t.Error("cookie visitor changed identity with IP or day")
+	}
+	if cookie == identifier.Identify(VisitorInfo{VisitorID: "v-2"}, monday) {
+		t.Error("two cookies share an identity")
+	}
+
+	hashed := identifier.Identify(anonymous, monday)
+	if hashed != identifier.Identify(anonymous, monday.Add(13*time.Hour)) {
+		t.Error("anonymous visitor changed identity within the day")
+	}
+	if hashed == identifier.Identify(anonymous, monday.AddDate(0, 0, 1)) {
+		t.Error("anonymous visitor kept the same identity on the next day")
+	}
+	if hashed == identifier.Identify(VisitorInfo{IPAddress: "203.0.113.7", UserAgent: "Safari"}, monday) {
+		t.Error("user agent does not affect the identity")
+	}
+
+	other := NewVisitorIdentifier(configs.Config{Visitor: configs.VisitorConfig{HashSecret: "another-secret"}})
+	if hashed == other.Identify(anonymous, monday) {
+		t.Error("identity does not depend on the secret")
+	}
+}
+
+type seenVisitorsRepo struct {
+	domain.ClickRepository
+	seen   map[domain.VisitorKey]bool
+	err    error
+	lookup []domain.VisitorKey
+}
+
+func (r *seenVisitorsRepo) FindSeenVisitors(keys []domain.VisitorKey, _ time.Time) ([]domain.VisitorKey, error) {
+	r.lookup = append(r.lookup, keys...)
+	if r.err != nil {
+		return nil, r.err
+	}
+	var found []domain.VisitorKey
+	for _, key := range keys {
+		if r.seen[key] {
+			found = append(found, key)
+		}
+	}
+	return found, nil
+}
+
+func TestMarkUniqueClicks(t *testing.T) {
+	link := uuid.New()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	returning := domain.VisitorKey{URLID: link, VisitorHash: "returning"}
+	repo := &seenVisitorsRepo{seen: map[domain.VisitorKey]bool{returning: true}}
+	tracker := &clickTracker{clickRepo: repo, uniqueWindow: 24 * time.Hour}
+	seen := map[domain.VisitorKey]time.Time{
+		{URLID: link, VisitorHash: "yesterday"}: now.Add(-25 * time.Hour),
+		{URLID: link, VisitorHash: "earlier"}:   now.Add(-time.Hour),
+	}
+
+	clicks := []domain.Click{
+		{URLID: link, VisitorHash: "new", ClickedAt: now},
+		{URLID: link, VisitorHash: "new", ClickedAt: now.Add(time.Second)},
+		{URLID: link, VisitorHash: "returning", ClickedAt: now},
+		{URLID: link, VisitorHash: "yesterday", ClickedAt: now},
+		{URLID: link, VisitorHash: "earlier", ClickedAt: now},
+		{URLID: uuid.New(), VisitorHash: "earlier", ClickedAt: now},
+	}
+	tracker.markUniqueClicks(clicks, seen)
+
+	want := []bool{true, false, false, true, false, true}
+	for i, click := range clicks {
+		if click.IsUnique != want[i] {
+			t.Errorf("click %d (%s): IsUnique = %v, want %v", i, click.VisitorHash, click.IsUnique, want[i])
+		}
+	}
+	if len(repo.lookup) != 3 {
+		t.Errorf("looked up %d visitors, want only the 3 not in memory", len(repo.lookup))
+	}
+}
+
+func TestMarkUniqueClicksCountsRepeatsWhenLookupFails(t *testing.T) {
+	repo := &seenVisitorsRepo{err: errors.New("connection reset")}
+	tracker := &clickTracker{clickRepo: repo, uniqueWindow: 24 * time.Hour}
+	clicks := []domain.Click{{URLID: uuid.New(), VisitorHash: "v", ClickedAt: time.Now()}}
+
+	tracker.markUniqueClicks(clicks, map[domain.VisitorKey]time.Time{})
+	if clicks[0].IsUnique {
+		t.Error("click counted as unique although the lookup failed")
+	}
+}
+
+func TestHashModeUniqueWindowEndsWithTheSalt(t *testing.T) {
+	cfg := configs.Config{Visitor: configs.VisitorConfig{Mode: "hash", UniqueWindow: "168h"}}
+	tracker := NewClickTracker(&pipelineURLRepo{}, &pipelineClickRepo{}, fixedGeoIP{}, NewVisitorIdentifier(cfg), cfg).(*clickTracker)
+	if tracker.uniqueWindow != 24*time.Hour {
+		t.Errorf("uniqueWindow = %v, want 24h", tracker.uniqueWindow)
+	}
+}
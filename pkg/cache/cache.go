@@ -0,0 +1,31 @@
+// Package cache provides byte-value caches with per-entry TTLs: a
+// process-local LRU and a Redis-backed store that several instances can
+// share.
+package cache
+
+import "time"
+
+// Cache stores opaque values under string keys. Implementations are safe for
+// concurrent use. Failures of a remote backend are not returned: Get reports
+// a miss and the failure is counted in Stats.Errors, so callers fall back to
+// the source of truth.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(keys ...string)
+	Stats() Stats
+}
+
+// Stats are cumulative counters since the cache was created. Entries and
+// Capacity are only known for the in-process cache.
+type Stats struct {
+	Backend   string
+	Hits      uint64
+	Misses    uint64
+	Sets      uint64
+	Deletes   uint64
+	Evictions uint64
+	Errors    uint64
+	Entries   int
+	Capacity  int
+}
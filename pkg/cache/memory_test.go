@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func TestMemoryCacheExpiresEntries(t *testing.T) {
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewMemoryCacheWithClock(10, clock.Now)
+
+	c.Set("a", []byte("1"), time.Minute)
+	clock.now = clock.now.Add(59 * time.Second)
+	if value, ok := c.Get("a"); !ok || string(value) != "1" {
+		t.Fatalf("Get before the TTL = %q, %v", value, ok)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("entry served at its TTL")
+	}
+	if stats := c.Stats(); stats.Entries != 0 || stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+	c.Get("a")
+	c.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if stats := c.Stats(); stats.Evictions != 1 || stats.Capacity != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestMemoryCacheIgnoresNonPositiveTTL(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("1"), 0)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("entry without a TTL was stored")
+	}
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisOptions configures the shared cache. Keys are stored under
+// KeyPrefix so one Redis database can serve several applications.
+type RedisOptions struct {
+	Addr      string
+	Password  string
+	DB        int
+	KeyPrefix string
+	Timeout   time.Duration
+	PoolSize  int
+}
+
+type redisCache struct {
+	client    *redis.Client
+	keyPrefix string
+	timeout   time.Duration
+
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	sets    atomic.Uint64
+	deletes atomic.Uint64
+	errors  atomic.Uint64
+}
+
+// NewRedisCache returns a cache stored in Redis, shared by every instance
+// pointing at the same server. Connections are dialled lazily, so the
+// service starts even while Redis is down. Eviction is left to the server's
+// maxmemory policy.
+func NewRedisCache(opts RedisOptions) Cache {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 100 * time.Millisecond
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 16
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:         opts.Addr,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		DialTimeout:  opts.Timeout,
+		ReadTimeout:  opts.Timeout,
+		WriteTimeout: opts.Timeout,
+		PoolSize:     opts.PoolSize,
+		MaxRetries:   -1,
+	})
+	return &redisCache{client: client, keyPrefix: opts.KeyPrefix, timeout: opts.Timeout}
+}
+
+func (c *redisCache) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+func (c *redisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	value, err := c.client.Get(ctx, c.keyPrefix+key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.errors.Add(1)
+		}
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return value, true
+}
+
+func (c *redisCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	c.sets.Add(1)
+	if err := c.client.Set(ctx, c.keyPrefix+key, value, max(ttl, time.Millisecond)).Err(); err != nil {
+		c.errors.Add(1)
+		log.Printf("Cache set of %s failed: %v", key, err)
+	}
+}
+
+func (c *redisCache) Delete(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.keyPrefix + key
+	}
+	c.deletes.Add(uint64(len(keys)))
+	if err := c.client.Del(ctx, prefixed...).Err(); err != nil {
+		c.errors.Add(1)
+		log.Printf("Cache invalidation of %v failed, entries may be served until they expire: %v", keys, err)
+	}
+}
+
+func (c *redisCache) Stats() Stats {
+	return Stats{
+		Backend: "redis",
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Sets:    c.sets.Load(),
+		Deletes: c.deletes.Load(),
+		Errors:  c.errors.Load(),
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedisCacheGetSetDelete(t *testing.T) {
+	server := miniredis.RunT(t)
+	c := NewRedisCache(RedisOptions{Addr: server.Addr(), KeyPrefix: "test:"})
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("hit on an empty server")
+	}
+	c.Set("a", []byte("1"), time.Minute)
+	if got, _ := server.Get("test:a"); got != "1" {
+		t.Fatalf("stored value = %q, want it under the key prefix", got)
+	}
+	if value, ok := c.Get("a"); !ok || string(value) != "1" {
+		t.Fatalf("Get = %q, %v", value, ok)
+	}
+
+	server.FastForward(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("entry served after its TTL")
+	}
+
+	c.Set("b", []byte{}, time.Minute)
+	if value, ok := c.Get("b"); !ok || len(value) != 0 {
+		t.Fatalf("empty value: Get = %q, %v", value, ok)
+	}
+	c.Delete("b")
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("deleted entry served")
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 3 || stats.Sets != 2 || stats.Deletes != 1 || stats.Errors != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestRedisCacheCountsFailuresAsMisses(t *testing.T) {
+	server := miniredis.RunT(t)
+	c := NewRedisCache(RedisOptions{Addr: server.Addr()})
+	server.Close()
+
+	c.Set("a", []byte("1"), time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("hit while Redis is down")
+	}
+	c.Delete("a")
+
+	if stats := c.Stats(); stats.Errors != 3 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,24 @@
+package dns
+
+import (
+	"context"
+	"net"
+)
+
+// Resolver looks up DNS records. It is an interface so domain verification
+// can be exercised without real DNS.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type netResolver struct {
+	resolver *net.Resolver
+}
+
+func NewResolver() Resolver {
+	return &netResolver{resolver: net.DefaultResolver}
+}
+
+func (r *netResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.resolver.LookupTXT(ctx, name)
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
@@ -12,7 +14,14 @@ import (
 	"github.com/google/uuid"
 )
 
-func AuthMiddleware(cfg configs.JWTConfig, userRepo domain.UserRepository) gin.HandlerFunc {
+// apiKeyTouchInterval limits how often last_used_at is written for a key
+// that is used continuously.
+const apiKeyTouchInterval = time.Minute
+
+// AuthMiddleware accepts a bearer access token whose session is still active,
+// or an active X-API-Key. It sets "userID", plus "sessionID" for token
+// requests or "apiKeyID" and "scopes" for API key requests.
+func AuthMiddleware(cfg configs.JWTConfig, apiKeyRepo domain.APIKeyRepository, sessionRepo domain.SessionRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var userID uuid.UUID
 
@@ -24,19 +33,33 @@ func AuthMiddleware(cfg configs.JWTConfig, userRepo domain.UserRepository) gin.H
 				response.SendError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid or expired token", nil)
 				return
 			}
+			session, err := sessionRepo.FindByID(claims.SessionID)
+			if err != nil || session.UserID != claims.UserID || !session.IsActive(time.Now()) {
+				response.SendError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Session has been revoked or has expired", nil)
+				return
+			}
 			userID = claims.UserID
+			c.Set("sessionID", session.ID)
 		} else {
 			apiKey := c.GetHeader("X-API-Key")
 			if apiKey == "" {
 				response.SendError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authorization header or X-API-Key header is required", nil)
 				return
 			}
-			user, err := userRepo.FindByAPIKey(apiKey)
-			if err != nil {
-				response.SendError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid API Key", nil)
+			key, err := apiKeyRepo.FindByHash(utils.HashAPIKey(apiKey))
+			now := time.Now()
+			if err != nil || !key.IsActive(now) {
+				response.SendError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid, revoked or expired API Key", nil)
 				return
 			}
-			userID = user.ID
+			if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+				if err := apiKeyRepo.TouchLastUsed(key.ID, now); err != nil {
+					log.Printf("Failed to record use of API key %s: %v", key.ID, err)
+				}
+			}
+			userID = key.UserID
+			c.Set("apiKeyID", key.ID)
+			c.Set("scopes", key.Scopes)
 		}
 
 		if userID == uuid.Nil {
@@ -48,3 +71,47 @@ func AuthMiddleware(cfg configs.JWTConfig, userRepo domain.UserRepository) gin.H
 		c.Next()
 	}
 }
+
+// RequireScope restricts a route to API keys that were granted every listed
+// scope. Requests authenticated with an access token pass unchecked. It must
+// run after AuthMiddleware.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get("scopes")
+		if !ok {
+			c.Next()
+			return
+		}
+
+		granted := value.([]string)
+		for _, scope := range scopes {
+			if !containsScope(granted, scope) {
+				response.SendError(c, http.StatusForbidden, "INSUFFICIENT_SCOPE", "API key is missing the required scope", []response.ErrorDetail{{Field: "scope", Message: scope + " is required"}})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireSession restricts a route to requests authenticated with an access
+// token, for account management that API keys must not reach. It must run
+// after AuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("sessionID"); !ok {
+			response.SendError(c, http.StatusForbidden, "SESSION_REQUIRED", "This endpoint requires a bearer token; API keys cannot be used", nil)
+			return
+		}
+		c.Next()
+	}
+}
+
+func containsScope(granted []string, scope string) bool {
+	for _, s := range granted {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
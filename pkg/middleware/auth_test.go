@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type authSessionRepo struct {
+	domain.SessionRepository
+	sessions map[uuid.UUID]domain.Session
+}
+
+func (r authSessionRepo) FindByID(id uuid.UUID) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &session, nil
+}
+
+// newAuthRouter serves /me, which answers with the authenticated user ID.
+func newAuthRouter(sessions authSessionRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(configs.JWTConfig{SecretKey: "secret"}, nil, sessions))
+	router.GET("/me", func(c *gin.Context) { c.String(http.StatusOK, c.MustGet("userID").(uuid.UUID).String()) })
+	return router
+}
+
+func getWithToken(router *gin.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthMiddlewareChecksTheTokenSession(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+	active := domain.Session{ID: uuid.New(), UserID: userID, ExpiresAt: now.Add(time.Hour)}
+	revoked := domain.Session{ID: uuid.New(), UserID: userID, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}
+	expired := domain.Session{ID: uuid.New(), UserID: userID, ExpiresAt: now.Add(-time.Minute)}
+	someoneElses := domain.Session{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: now.Add(time.Hour)}
+	router := newAuthRouter(authSessionRepo{sessions: map[uuid.UUID]domain.Session{
+		active.ID: active, revoked.ID: revoked, expired.ID: expired, someoneElses.ID: someoneElses,
+	}})
+
+	tests := []struct {
+		name      string
+		sessionID uuid.UUID
+		want      int
+	}{
+		{"active session", active.ID, http.StatusOK},
+		{"revoked session", revoked.ID, http.StatusUnauthorized},
+		{"expired session", expired.ID, http.StatusUnauthorized},
+		{"another user's session", someoneElses.ID, http.StatusUnauthorized},
+		{"unknown session", uuid.New(), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		token, err := utils.GenerateToken(userID, tt.sessionID, "secret", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := getWithToken(router, token)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && rec.Body.String() != userID.String() {
+			t.Errorf("%s: userID = %q, want %s", tt.name, rec.Body.String(), userID)
+		}
+	}
+}
+
+type authAPIKeyRepo struct {
+	domain.APIKeyRepository
+	keys    map[string]domain.APIKey
+	touched []uuid.UUID
+}
+
+func (r *authAPIKeyRepo) FindByHash(keyHash string) (*domain.APIKey, error) {
+	key, ok := r.keys[keyHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &key, nil
+}
+
+func (r *authAPIKeyRepo) TouchLastUsed(id uuid.UUID, _ time.Time) error {
+	r.touched = append(r.touched, id)
+	return nil
+}
+
+func TestAuthMiddlewareAPIKeys(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	earlier, later := now.Add(-time.Minute), now.Add(time.Hour)
+	keys := map[string]domain.APIKey{
+		"usk_reader":  {ID: uuid.New(), UserID: userID, Scopes: []string{domain.ScopeURLsRead}},
+		"usk_writer":  {ID: uuid.New(), UserID: userID, Scopes: []string{domain.ScopeURLsRead, domain.ScopeURLsWrite}, ExpiresAt: &later, LastUsedAt: &now},
+		"usk_revoked": {ID: uuid.New(), UserID: userID, Scopes: domain.APIKeyScopes, RevokedAt: &earlier},
+		"usk_expired": {ID: uuid.New(), UserID: userID, Scopes: domain.APIKeyScopes, ExpiresAt: &earlier},
+	}
+	repo := &authAPIKeyRepo{keys: map[string]domain.APIKey{}}
+	for secret, key := range keys {
+		repo.keys[utils.HashAPIKey(secret)] = key
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(configs.JWTConfig{SecretKey: "secret"}, repo, authSessionRepo{}))
+	router.GET("/urls", RequireScope(domain.ScopeURLsRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/urls", RequireScope(domain.ScopeURLsWrite), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/api-keys", RequireSession(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name, method, path, key string
+		want                    int
+	}{
+		{"scoped read", http.MethodGet, "/urls", "usk_reader", http.StatusOK},
+		{"missing scope", http.MethodPost, "/urls", "usk_reader", http.StatusForbidden},
+		{"granted scope", http.MethodPost, "/urls", "usk_writer", http.StatusCreated},
+		{"account management", http.MethodGet, "/api-keys", "usk_writer", http.StatusForbidden},
+		{"revoked key", http.MethodGet, "/urls", "usk_revoked", http.StatusUnauthorized},
+		{"expired key", http.MethodGet, "/urls", "usk_expired", http.StatusUnauthorized},
+		{"unknown key", http.MethodGet, "/urls", "usk_unknown", http.StatusUnauthorized},
+		{"no credentials", http.MethodGet, "/urls", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+
+	// The writer key was used just now, so only the reader's use is recorded.
+	reader := keys["usk_reader"].ID
+	for _, id := range repo.touched {
+		if id != reader {
+			t.Errorf("last use of key %s recorded, want only the reader key", id)
+		}
+	}
+	if len(repo.touched) == 0 {
+		t.Error("use of the reader key was not recorded")
+	}
+}
+
+func TestRequireScopeLetsSessionsThrough(t *testing.T) {
+	userID := uuid.New()
+	session := domain.Session{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(configs.JWTConfig{SecretKey: "secret"}, nil, authSessionRepo{sessions: map[uuid.UUID]domain.Session{session.ID: session}}))
+	router.GET("/me", RequireScope(domain.ScopeDomainsWrite), RequireSession(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, err := utils.GenerateToken(userID, session.ID, "secret", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := getWithToken(router, token); rec.Code != http.StatusOK {
+		t.Errorf("status %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/dto/response"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// planCacheTTL bounds how long a plan change takes to affect API limits.
+	planCacheTTL   = time.Minute
+	planCacheLimit = 10000
+)
+
+// RateLimitByIP limits unauthenticated endpoints per client IP and route.
+// A rule with a non-positive limit disables the check.
+func RateLimitByIP(limiter *ratelimit.Limiter, rule ratelimit.Rule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rule.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		ip := c.ClientIP()
+		endpoint := c.FullPath()
+		enforceRateLimit(c, limiter, rule, &domain.RateLimit{
+			LimitKey:  fmt.Sprintf("ip:%s|%s", ip, endpoint),
+			IPAddress: ip,
+			Endpoint:  endpoint,
+		})
+	}
+}
+
+// PlanRateLimit applies the API-calls-per-minute quota of the authenticated
+// user's plan. It must run after AuthMiddleware. The quota applies to each
+// credential separately and is shared by all endpoints: every API key, and
+// the user's bearer sessions together, get the full quota, so one noisy
+// integration cannot starve the rest of the account. The endpoint is kept
+// on the counter as a label only.
+func PlanRateLimit(limiter *ratelimit.Limiter, plans configs.PlanQuotasConfig, userRepo domain.UserRepository) gin.HandlerFunc {
+	cache := &planCache{userRepo: userRepo, entries: make(map[uuid.UUID]planCacheEntry)}
+
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(uuid.UUID)
+
+		planType, err := cache.get(userID)
+		if err != nil {
+			log.Printf("Could not load plan for user %s, skipping rate limit: %v", userID, err)
+			c.Next()
+			return
+		}
+
+		quota := plans.For(planType)
+		if quota.APICallsPerMinute <= 0 {
+			c.Next()
+			return
+		}
+
+		key := &domain.RateLimit{
+			LimitKey:  fmt.Sprintf("user:%s|session", userID),
+			UserID:    &userID,
+			IPAddress: c.ClientIP(),
+			Endpoint:  c.FullPath(),
+		}
+		if apiKeyID, ok := c.Get("apiKeyID"); ok {
+			id := apiKeyID.(uuid.UUID).String()
+			key.LimitKey = fmt.Sprintf("user:%s|key:%s", userID, id)
+			key.APIKey = &id
+		}
+		enforceRateLimit(c, limiter, ratelimit.Rule{Limit: quota.APICallsPerMinute, Window: time.Minute}, key)
+	}
+}
+
+// enforceRateLimit counts the request and either lets it through or aborts
+// with 429. If the counter store fails the request is allowed, so a
+// database hiccup does not take the API down with it.
+func enforceRateLimit(c *gin.Context, limiter *ratelimit.Limiter, rule ratelimit.Rule, key *domain.RateLimit) {
+	result, err := limiter.Allow(key, rule)
+	if err != nil {
+		log.Printf("Rate limit check failed for %s: %v", key.LimitKey, err)
+		c.Next()
+		return
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.Limit, ceilSeconds(rule.Window)))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		response.SendError(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Too many requests, please retry later", nil)
+		return
+	}
+	c.Next()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+type planCacheEntry struct {
+	planType  string
+	expiresAt time.Time
+}
+
+type planCache struct {
+	userRepo domain.UserRepository
+	mu       sync.Mutex
+	entries  map[uuid.UUID]planCacheEntry
+}
+
+func (p *planCache) get(userID uuid.UUID) (string, error) {
+	now := time.Now()
+
+	p.mu.Lock()
+	entry, ok := p.entries[userID]
+	p.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.planType, nil
+	}
+
+	user, err := p.userRepo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.entries) > planCacheLimit {
+		for id, e := range p.entries {
+			if now.After(e.expiresAt) {
+				delete(p.entries, id)
+			}
+		}
+	}
+	p.entries[userID] = planCacheEntry{planType: user.PlanType, expiresAt: now.Add(planCacheTTL)}
+	return user.PlanType, nil
+}
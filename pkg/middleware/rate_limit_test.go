@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/configs"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type planUserRepo struct {
+	domain.UserRepository
+	plan string
+}
+
+func (r planUserRepo) FindByID(id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id, PlanType: r.plan}, nil
+}
+
+// newPlanLimitedRouter authenticates every request as userID, with the API
+// key named in the X-Key header if there is one.
+func newPlanLimitedRouter(userID uuid.UUID, callsPerMinute int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	plans := configs.PlanQuotasConfig{Free: configs.PlanQuota{APICallsPerMinute: callsPerMinute}}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		if key := c.GetHeader("X-Key"); key != "" {
+			c.Set("apiKeyID", uuid.MustParse(key))
+		}
+	}, PlanRateLimit(limiter, plans, planUserRepo{plan: "free"}))
+	router.GET("/urls", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/analytics/dashboard", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func get(router *gin.Engine, path, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if apiKey != "" {
+		req.Header.Set("X-Key", apiKey)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPlanRateLimitKeepsCredentialsApart(t *testing.T) {
+	router := newPlanLimitedRouter(uuid.New(), 2)
+	noisyKey, quietKey := uuid.NewString(), uuid.NewString()
+
+	for i := 0; i < 2; i++ {
+		if rec := get(router, "/urls", noisyKey); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i+1, rec.Code)
+		}
+	}
+	limited := get(router, "/urls", noisyKey)
+	if limited.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request with the noisy key: status %d, want 429", limited.Code)
+	}
+	if limited.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+
+	for name, rec := range map[string]*httptest.ResponseRecorder{
+		"another API key": get(router, "/urls", quietKey),
+		"bearer session":  get(router, "/urls", ""),
+	} {
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d, want 200", name, rec.Code)
+		}
+	}
+}
+
+func TestPlanRateLimitSharesTheQuotaAcrossEndpoints(t *testing.T) {
+	router := newPlanLimitedRouter(uuid.New(), 2)
+	key := uuid.NewString()
+
+	get(router, "/urls", key)
+	get(router, "/analytics/dashboard", key)
+	if rec := get(router, "/urls", key); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("third request over two endpoints: status %d, want 429", rec.Code)
+	}
+	if rec := get(router, "/analytics/dashboard", key); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("fourth request on another endpoint: status %d, want 429", rec.Code)
+	}
+}
+
+// limitedByIP serves one request per client IP from behind trustedProxies.
+func limitedByIP(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatal(err)
+	}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	router.GET("/:shortCode", RateLimitByIP(limiter, ratelimit.Rule{Limit: 1, Window: time.Minute}), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func getFrom(router *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRateLimitByIPIgnoresForwardedForFromUntrustedPeers(t *testing.T) {
+	router := limitedByIP(t, nil)
+	if code := getFrom(router, "203.0.113.7:4000", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("first request: status %d", code)
+	}
+	if code := getFrom(router, "203.0.113.7:4000", "198.51.100.2"); code != http.StatusTooManyRequests {
+		t.Errorf("spoofed X-Forwarded-For: status %d, want 429", code)
+	}
+}
+
+func TestRateLimitByIPUsesForwardedForFromTrustedProxies(t *testing.T) {
+	router := limitedByIP(t, []string{"10.0.0.0/8"})
+	for _, client := range []string{"198.51.100.1", "198.51.100.2"} {
+		if code := getFrom(router, "10.0.0.2:4000", client); code != http.StatusOK {
+			t.Errorf("client %s behind the proxy: status %d, want 200", client, code)
+		}
+	}
+}
+
+func TestPlanRateLimitSendsRateLimitHeaders(t *testing.T) {
+	router := newPlanLimitedRouter(uuid.New(), 5)
+
+	rec := get(router, "/urls", "")
+	for header, want := range map[string]string{
+		"RateLimit-Limit":     "5",
+		"RateLimit-Remaining": "4",
+		"RateLimit-Policy":    "5;w=60",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestPlanRateLimitUnlimitedPlan(t *testing.T) {
+	router := newPlanLimitedRouter(uuid.New(), 0)
+	for i := 0; i < 20; i++ {
+		if rec := get(router, "/urls", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d on an unlimited plan: status %d", i+1, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+// sweepInterval is how often expired counters are removed from the store.
+const sweepInterval = time.Minute
+
+// Rule is a limit of Limit requests per Window for one key.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Result describes the state of a key after a request was counted.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter enforces sliding-window limits. Each key keeps one counter per
+// fixed window; the request rate is estimated from the current window plus
+// the previous window weighted by how much of it still overlaps the sliding
+// window. This needs two counters per key regardless of traffic and smooths
+// out the burst a plain fixed window allows at window boundaries.
+type Limiter struct {
+	store domain.RateLimitRepository
+	now   func() time.Time
+
+	mu        sync.Mutex
+	maxWindow time.Duration
+	lastSweep time.Time
+}
+
+func NewLimiter(store domain.RateLimitRepository) *Limiter {
+	return &Limiter{store: store, now: time.Now, lastSweep: time.Now()}
+}
+
+// Allow counts one request against key and reports whether it is within
+// rule. Denied requests are counted too, so a client that keeps retrying
+// while limited stays limited.
+func (l *Limiter) Allow(key *domain.RateLimit, rule Rule) (Result, error) {
+	now := l.now()
+	windowStart := now.Truncate(rule.Window)
+	elapsed := now.Sub(windowStart)
+
+	key.WindowStart = windowStart
+	previous, err := l.store.Hit(key, windowStart.Add(-rule.Window))
+	if err != nil {
+		return Result{}, err
+	}
+	l.maybeSweep(now, rule.Window)
+
+	current := key.RequestCount
+	weight := 1 - float64(elapsed)/float64(rule.Window)
+	estimate := float64(previous)*weight + float64(current)
+
+	result := Result{
+		Allowed: estimate <= float64(rule.Limit),
+		Limit:   rule.Limit,
+		Reset:   rule.Window - elapsed,
+	}
+	if remaining := rule.Limit - int(math.Ceil(estimate)); remaining > 0 {
+		result.Remaining = remaining
+	}
+	if !result.Allowed {
+		result.RetryAfter = retryAfter(rule, elapsed, current, previous)
+	}
+	return result, nil
+}
+
+// retryAfter is the time until the estimate leaves room for one more request.
+func retryAfter(rule Rule, elapsed time.Duration, current, previous int) time.Duration {
+	window := float64(rule.Window)
+	limit := float64(rule.Limit)
+
+	// Still in this window: wait for the previous window's weight to decay.
+	if float64(current)+1 <= limit && previous > 0 {
+		at := window * (1 - (limit-float64(current)-1)/float64(previous))
+		if at < window {
+			return time.Duration(at) - elapsed
+		}
+	}
+
+	// Otherwise wait into the next window, where this window's count decays.
+	wait := rule.Window - elapsed
+	if float64(current) > limit-1 {
+		wait += time.Duration(window * (1 - (limit-1)/float64(current)))
+	}
+	return wait
+}
+
+// maybeSweep drops counters that can no longer affect any rule. It runs in
+// the background at most once per sweepInterval.
+func (l *Limiter) maybeSweep(now time.Time, window time.Duration) {
+	l.mu.Lock()
+	if window > l.maxWindow {
+		l.maxWindow = window
+	}
+	if now.Sub(l.lastSweep) < sweepInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.lastSweep = now
+	cutoff := now.Add(-2 * l.maxWindow)
+	l.mu.Unlock()
+
+	go l.store.DeleteBefore(cutoff)
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func newTestLimiter() (*Limiter, *testClock) {
+	clock := &testClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(NewMemoryStore())
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func allow(t *testing.T, l *Limiter, key string, rule Rule) Result {
+	t.Helper()
+	result, err := l.Allow(&domain.RateLimit{LimitKey: key}, rule)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	return result
+}
+
+func TestLimiterAllowsUpToLimitPerWindow(t *testing.T) {
+	limiter, _ := newTestLimiter()
+	rule := Rule{Limit: 3, Window: time.Minute}
+
+	for i := 1; i <= 3; i++ {
+		result := allow(t, limiter, "ip:1.2.3.4", rule)
+		if !result.Allowed {
+			t.Fatalf("request %d denied", i)
+		}
+		if result.Remaining != 3-i {
+			t.Errorf("request %d: remaining = %d, want %d", i, result.Remaining, 3-i)
+		}
+	}
+
+	denied := allow(t, limiter, "ip:1.2.3.4", rule)
+	if denied.Allowed {
+		t.Fatal("request over the limit was allowed")
+	}
+	if denied.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want a positive wait", denied.RetryAfter)
+	}
+
+	if other := allow(t, limiter, "ip:5.6.7.8", rule); !other.Allowed {
+		t.Error("a different key shares the exhausted budget")
+	}
+}
+
+func TestLimiterWeighsPreviousWindow(t *testing.T) {
+	limiter, clock := newTestLimiter()
+	rule := Rule{Limit: 10, Window: time.Minute}
+
+	for i := 0; i < 10; i++ {
+		allow(t, limiter, "k", rule)
+	}
+
+	// Halfway through the next window, half of the previous window's ten
+	// requests still count, leaving room for five.
+	clock.now = clock.now.Add(90 * time.Second)
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if allow(t, limiter, "k", rule).Allowed {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("allowed %d requests half a window later, want 5", allowed)
+	}
+
+	// Two windows later nothing of the burst is left.
+	clock.now = clock.now.Add(2 * time.Minute)
+	if result := allow(t, limiter, "k", rule); !result.Allowed || result.Remaining != 9 {
+		t.Errorf("after two windows: allowed=%v remaining=%d", result.Allowed, result.Remaining)
+	}
+}
+
+func TestLimiterRetryAfterIsWhenARequestFitsAgain(t *testing.T) {
+	rule := Rule{Limit: 10, Window: time.Minute}
+	for _, offset := range []time.Duration{0, 15 * time.Second, 45 * time.Second} {
+		limiter, clock := newTestLimiter()
+		clock.now = clock.now.Add(offset)
+
+		var denied Result
+		for i := 0; i < 11; i++ {
+			denied = allow(t, limiter, "k", rule)
+		}
+		if denied.Allowed {
+			t.Fatalf("offset %v: eleventh request allowed", offset)
+		}
+
+		clock.now = clock.now.Add(denied.RetryAfter + time.Millisecond)
+		if !allow(t, limiter, "k", rule).Allowed {
+			t.Errorf("offset %v: still denied after Retry-After %v", offset, denied.RetryAfter)
+		}
+	}
+}
+
+func TestLimiterCountsDeniedRequests(t *testing.T) {
+	limiter, clock := newTestLimiter()
+	rule := Rule{Limit: 2, Window: time.Minute}
+
+	for i := 0; i < 6; i++ {
+		allow(t, limiter, "k", rule)
+	}
+	// Six requests in the previous window, weighted by a half, still fill
+	// the limit of two.
+	clock.now = clock.now.Add(90 * time.Second)
+	if allow(t, limiter, "k", rule).Allowed {
+		t.Error("a client that kept retrying while limited was let straight back in")
+	}
+}
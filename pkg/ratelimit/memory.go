@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+)
+
+type windowKey struct {
+	key         string
+	windowStart int64
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	counters map[windowKey]int
+}
+
+// NewMemoryStore returns a process-local counter store. Limits are per
+// instance, so use the Postgres store when running more than one replica.
+func NewMemoryStore() domain.RateLimitRepository {
+	return &memoryStore{counters: make(map[windowKey]int)}
+}
+
+func (s *memoryStore) Hit(counter *domain.RateLimit, previousWindowStart time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := windowKey{key: counter.LimitKey, windowStart: counter.WindowStart.UnixNano()}
+	s.counters[current]++
+	counter.RequestCount = s.counters[current]
+
+	return s.counters[windowKey{key: counter.LimitKey, windowStart: previousWindowStart.UnixNano()}], nil
+}
+
+func (s *memoryStore) DeleteBefore(cutoff time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.counters {
+		if key.windowStart < cutoff.UnixNano() {
+			delete(s.counters, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package referrer turns raw Referer headers into a site and a traffic source
+// category, using a rules table compiled into the binary.
+package referrer
+
+import (
+	_ "embed"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Traffic source categories.
+const (
+	CategorySearch = "search"
+	CategorySocial = "social"
+	CategoryEmail  = "email"
+	CategoryDirect = "direct"
+	CategoryOther  = "other"
+)
+
+// Source is where a visit came from. Domain is the site that matched a rule,
+// so that l.facebook.com and m.facebook.com both count as facebook.com, or
+// else the referring host without a leading "www."; Android app referrers
+// use the app's package name. Domain is empty for direct traffic.
+type Source struct {
+	Domain   string
+	Category string
+}
+
+//go:embed rules.csv
+var rulesCSV string
+
+var rules = mustParseRules(rulesCSV)
+
+type ruleSet struct {
+	exact    map[string]string
+	wildcard map[string]string
+}
+
+func mustParseRules(data string) ruleSet {
+	set := ruleSet{exact: make(map[string]string), wildcard: make(map[string]string)}
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		host, category, ok := strings.Cut(line, ",")
+		if !ok {
+			panic(fmt.Sprintf("referrer: rules.csv line %d: missing category", n+1))
+		}
+		switch category {
+		case CategorySearch, CategorySocial, CategoryEmail:
+		default:
+			panic(fmt.Sprintf("referrer: rules.csv line %d: unknown category %q", n+1, category))
+		}
+		if name, ok := strings.CutSuffix(host, ".*"); ok {
+			set.wildcard[name] = category
+		} else {
+			set.exact[host] = category
+		}
+	}
+	return set
+}
+
+// Classify parses a Referer header. An empty header is direct traffic; a
+// referrer that is not a URL with a host, or whose host has no rule, is
+// other.
+func Classify(rawReferer string) Source {
+	rawReferer = strings.TrimSpace(rawReferer)
+	if rawReferer == "" {
+		return Source{Category: CategoryDirect}
+	}
+
+	parsed, err := url.Parse(rawReferer)
+	if err != nil || parsed.Hostname() == "" {
+		return Source{Category: CategoryOther}
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	host = strings.TrimPrefix(host, "www.")
+
+	domain, category := rules.classify(host)
+	return Source{Domain: domain, Category: category}
+}
+
+// classify returns the matched site and its category. It tries the host and
+// each parent domain against the exact rules, longest first, and only then
+// the wildcard rules, so that a specific rule such as mail.google.com beats
+// google.*.
+func (s ruleSet) classify(host string) (domain, category string) {
+	labels := strings.Split(host, ".")
+	for i := range labels {
+		domain = strings.Join(labels[i:], ".")
+		if category, ok := s.exact[domain]; ok {
+			return domain, category
+		}
+	}
+	for i, label := range labels {
+		suffixLabels := len(labels) - i - 1
+		if suffixLabels < 1 || suffixLabels > 2 {
+			continue
+		}
+		if category, ok := s.wildcard[label]; ok {
+			return strings.Join(labels[i:], "."), category
+		}
+	}
+	return host, CategoryOther
+}
+
+// CategoryForMedium maps common utm_medium values to a category, or returns
+// "" for mediums it does not recognise. Links in emails usually arrive
+// without a Referer, so the medium is the only hint that they are not direct
+// traffic.
+func CategoryForMedium(medium string) string {
+	switch strings.ToLower(strings.TrimSpace(medium)) {
+	case "email", "e-mail", "e_mail", "newsletter":
+		return CategoryEmail
+	case "social", "social-network", "social-media", "sm", "social network", "social media":
+		return CategorySocial
+	case "organic", "search":
+		return CategorySearch
+	default:
+		return ""
+	}
+}
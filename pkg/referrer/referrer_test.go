@@ -0,0 +1,58 @@
+package referrer
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		referer string
+		want    Source
+	}{
+		{"", Source{Category: CategoryDirect}},
+		{"   ", Source{Category: CategoryDirect}},
+		{"https://www.google.com/", Source{Domain: "google.com", Category: CategorySearch}},
+		{"https://www.google.co.id/search?q=x", Source{Domain: "google.co.id", Category: CategorySearch}},
+		{"https://mail.google.com/mail/u/0/", Source{Domain: "mail.google.com", Category: CategoryEmail}},
+		{"https://l.facebook.com/l.php?u=x", Source{Domain: "facebook.com", Category: CategorySocial}},
+		{"https://M.FACEBOOK.COM./story", Source{Domain: "facebook.com", Category: CategorySocial}},
+		{"https://t.co/abc", Source{Domain: "t.co", Category: CategorySocial}},
+		{"android-app://com.google.android.gm/", Source{Domain: "com.google.android.gm", Category: CategoryEmail}},
+		{"android-app://com.twitter.android", Source{Domain: "com.twitter.android", Category: CategorySocial}},
+		{"https://www.example.org/post", Source{Domain: "example.org", Category: CategoryOther}},
+		// google.* only matches under a one- or two-label suffix.
+		{"https://google.evil.example.com/", Source{Domain: "google.evil.example.com", Category: CategoryOther}},
+		// A site merely ending in a known domain's name is not that site.
+		{"https://notfacebook.com/", Source{Domain: "notfacebook.com", Category: CategoryOther}},
+		{"not a url", Source{Category: CategoryOther}},
+		{"/relative/path", Source{Category: CategoryOther}},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.referer); got != tt.want {
+			t.Errorf("Classify(%q) = %+v, want %+v", tt.referer, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryForMedium(t *testing.T) {
+	tests := map[string]string{
+		"email":        CategoryEmail,
+		" Newsletter ": CategoryEmail,
+		"social-media": CategorySocial,
+		"organic":      CategorySearch,
+		"cpc":          "",
+		"":             "",
+	}
+	for medium, want := range tests {
+		if got := CategoryForMedium(medium); got != want {
+			t.Errorf("CategoryForMedium(%q) = %q, want %q", medium, got, want)
+		}
+	}
+}
+
+func TestRulesRejectUnknownCategory(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("rules with an unknown category did not panic")
+		}
+	}()
+	mustParseRules("example.com,news\n")
+}
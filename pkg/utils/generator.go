@@ -2,9 +2,15 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 )
 
+// APIKeyPrefix starts every generated API key, which makes keys easy to
+// recognise in logs and secret scanners.
+const APIKeyPrefix = "usk_"
+
 func GenerateRandomString(length int) (string, error) {
 	bytes := make([]byte, length)
 	if _, err := rand.Read(bytes); err != nil {
@@ -14,9 +20,24 @@ func GenerateRandomString(length int) (string, error) {
 }
 
 func GenerateAPIKey() (string, error) {
-	return GenerateRandomString(32)
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + hex.EncodeToString(bytes), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of an API key. Keys are random, so a
+// fast unsalted hash is enough and allows lookup by hash.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
 }
 
 func GenerateShortCode() (string, error) {
 	return GenerateRandomString(6)
 }
+
+func GenerateVisitorID() (string, error) {
+	return GenerateRandomString(18)
+}
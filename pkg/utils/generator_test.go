@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	first, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first, APIKeyPrefix) || len(first) != len(APIKeyPrefix)+64 {
+		t.Errorf("key %q is not the prefix and 32 hex-encoded bytes", first)
+	}
+	if first == second {
+		t.Error("two keys are equal")
+	}
+	if HashAPIKey(first) != HashAPIKey(first) || HashAPIKey(first) == HashAPIKey(second) {
+		t.Error("hash is not a stable function of the key")
+	}
+}
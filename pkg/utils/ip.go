@@ -0,0 +1,23 @@
+package utils
+
+import "net/netip"
+
+// MaskIP zeroes the host part of an address, keeping the /24 of an IPv4
+// address and the /48 of an IPv6 address. Unparseable input yields "".
+func MaskIP(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.Addr().String()
+}
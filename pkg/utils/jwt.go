@@ -8,15 +8,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// JWTCustomClaims identifies the user and the server-side session a token
+// belongs to. Every token also gets a unique ID in the registered "jti" claim.
 type JWTCustomClaims struct {
-	UserID uuid.UUID `json:"user_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uuid.UUID, secretKey string, expiresIn time.Duration) (string, error) {
+func GenerateToken(userID, sessionID uuid.UUID, secretKey string, expiresIn time.Duration) (string, error) {
+	return GenerateTokenWithID(uuid.New(), userID, sessionID, secretKey, expiresIn)
+}
+
+// GenerateTokenWithID is GenerateToken with a caller-chosen jti, for tokens
+// that are tracked server-side by ID.
+func GenerateTokenWithID(tokenID, userID, sessionID uuid.UUID, secretKey string, expiresIn time.Duration) (string, error) {
 	claims := &JWTCustomClaims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID.String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
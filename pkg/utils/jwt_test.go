@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	userID, sessionID, tokenID := uuid.New(), uuid.New(), uuid.New()
+	token, err := GenerateTokenWithID(tokenID, userID, sessionID, "secret", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := ValidateToken(token, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != userID || claims.SessionID != sessionID || claims.ID != tokenID.String() {
+		t.Errorf("claims = %+v, want user %s, session %s and jti %s", claims, userID, sessionID, tokenID)
+	}
+}
+
+func TestValidateTokenRejects(t *testing.T) {
+	userID, sessionID := uuid.New(), uuid.New()
+	valid, err := GenerateToken(userID, sessionID, "secret", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := GenerateToken(userID, sessionID, "secret", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, &JWTCustomClaims{UserID: userID, SessionID: sessionID}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct{ token, secret string }{
+		"wrong secret": {valid, "other"},
+		"expired":      {expired, "secret"},
+		"unsigned":     {unsigned, "secret"},
+		"garbage":      {"not.a.token", "secret"},
+	}
+	for name, tt := range tests {
+		if _, err := ValidateToken(tt.token, tt.secret); err == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "golang.org/x/text/language"
+
+// PrimaryLanguage returns the base language ("en", "id", ...) of the most
+// preferred entry of an Accept-Language header, or "" when the header names
+// no specific language. Wildcards ("*") are skipped.
+func PrimaryLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return ""
+	}
+	for _, tag := range tags {
+		base, confidence := tag.Base()
+		if confidence == language.No {
+			continue
+		}
+		if code := base.String(); code != "und" && code != "mul" {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "testing"
+
+func TestPrimaryLanguage(t *testing.T) {
+	tests := map[string]string{
+		"id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7": "id",
+		"en-GB":                               "en",
+		"fr;q=0.5, de-CH;q=0.9":               "de",
+		"*, en;q=0.1":                         "en",
+		"zh-Hant-TW":                          "zh",
+		"*":                                   "",
+		"":                                    "",
+		"!!not a header!!":                    "",
+	}
+	for header, want := range tests {
+		if got := PrimaryLanguage(header); got != want {
+			t.Errorf("PrimaryLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "net/url"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 func GetDomainFromURL(rawURL string) (string, error) {
 	parsedURL, err := url.Parse(rawURL)
@@ -9,3 +13,94 @@ func GetDomainFromURL(rawURL string) (string, error) {
 	}
 	return parsedURL.Hostname(), nil
 }
+
+// BuildShortURL returns the public short link for a code. Links on a custom
+// domain reuse the scheme of the default base URL.
+func BuildShortURL(baseURL, customDomain, shortCode string) string {
+	if customDomain == "" {
+		return fmt.Sprintf("%s/%s", baseURL, shortCode)
+	}
+
+	scheme := "https"
+	if parsedURL, err := url.Parse(baseURL); err == nil && parsedURL.Scheme != "" {
+		scheme = parsedURL.Scheme
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, customDomain, shortCode)
+}
+
+// IsValidURL reports whether rawURL is an absolute http(s) URL with a host.
+func IsValidURL(rawURL string) bool {
+	parsedURL, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsedURL.Scheme == "http" || parsedURL.Scheme == "https") && parsedURL.Host != ""
+}
+
+// unsafeDeepLinkSchemes run code in the page or read local data when a
+// browser navigates to them.
+var unsafeDeepLinkSchemes = map[string]bool{
+	"javascript": true,
+	"vbscript":   true,
+	"data":       true,
+	"file":       true,
+	"blob":       true,
+	"about":      true,
+}
+
+// IsSafeDeepLink reports whether rawURL is an http(s) URL or a custom-scheme
+// app link such as myapp://product/42 that a browser can only hand to an app.
+func IsSafeDeepLink(rawURL string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Scheme == "" {
+		return false
+	}
+	scheme := strings.ToLower(parsedURL.Scheme)
+	if scheme == "http" || scheme == "https" {
+		return parsedURL.Host != ""
+	}
+	return !unsafeDeepLinkSchemes[scheme]
+}
+
+// QueryParam is one query parameter with its unescaped value.
+type QueryParam struct {
+	Key   string
+	Value string
+}
+
+// SetQueryParams sets params on rawURL, replacing every existing value of
+// the same keys. The rest of the query keeps its order and encoding, and the
+// fragment is preserved, so destinations that sign or parse their query
+// strictly keep working.
+func SetQueryParams(rawURL string, params []QueryParam) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	replaced := make(map[string]bool, len(params))
+	for _, p := range params {
+		replaced[p.Key] = true
+	}
+
+	var pairs []string
+	for _, pair := range strings.Split(parsedURL.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if !replaced[key] {
+			pairs = append(pairs, pair)
+		}
+	}
+	for _, p := range params {
+		pairs = append(pairs, url.QueryEscape(p.Key)+"="+url.QueryEscape(p.Value))
+	}
+
+	parsedURL.RawQuery = strings.Join(pairs, "&")
+	parsedURL.ForceQuery = false
+	return parsedURL.String(), nil
+}
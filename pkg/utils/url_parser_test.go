@@ -0,0 +1,80 @@
+package utils
+
+import "testing"
+
+func TestIsSafeDeepLink(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://apps.apple.com/app/id123", true},
+		{"http://example.com/product/42", true},
+		{"myapp://product/42", true},
+		{"intent://scan/#Intent;scheme=zxing;end", true},
+		{"javascript:alert(1)", false},
+		{"JavaScript:alert(1)", false},
+		{"data:text/html;base64,PHNjcmlwdD4=", false},
+		{"vbscript:msgbox", false},
+		{"file:///etc/passwd", false},
+		{"blob:https://example.com/uuid", false},
+		{"about:blank", false},
+		{"https:///no-host", false},
+		{"/relative/path", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsSafeDeepLink(tt.url); got != tt.want {
+			t.Errorf("IsSafeDeepLink(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSetQueryParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		params []QueryParam
+		want   string
+	}{
+		{
+			name:   "adds to a bare URL",
+			url:    "https://example.com/sale",
+			params: []QueryParam{{Key: "utm_source", Value: "newsletter"}},
+			want:   "https://example.com/sale?utm_source=newsletter",
+		},
+		{
+			name:   "replaces every value of a key and keeps the rest in order",
+			url:    "https://example.com/?b=2&utm_source=old&a=1&utm_source=older",
+			params: []QueryParam{{Key: "utm_source", Value: "new"}},
+			want:   "https://example.com/?b=2&a=1&utm_source=new",
+		},
+		{
+			name:   "keeps the encoding of other parameters and the fragment",
+			url:    "https://example.com/p?sig=a%2Bb%3D&q=x+y#section",
+			params: []QueryParam{{Key: "utm_campaign", Value: "spring sale"}},
+			want:   "https://example.com/p?sig=a%2Bb%3D&q=x+y&utm_campaign=spring+sale#section",
+		},
+		{
+			name:   "matches escaped keys",
+			url:    "https://example.com/?utm%5Fsource=old",
+			params: []QueryParam{{Key: "utm_source", Value: "new"}},
+			want:   "https://example.com/?utm_source=new",
+		},
+		{
+			name:   "drops an empty query",
+			url:    "https://example.com/?",
+			params: nil,
+			want:   "https://example.com/",
+		},
+	}
+	for _, tt := range tests {
+		got, err := SetQueryParams(tt.url, tt.params)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
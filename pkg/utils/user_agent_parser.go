@@ -1,15 +1,28 @@
 package utils
 
 import (
+	"strings"
+
 	"github.com/mssola/user_agent"
 )
 
+// Mobile platforms reported in ParsedUserAgent.Platform.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// ParsedUserAgent describes a client. Platform is PlatformIOS or
+// PlatformAndroid for phones and tablets running them, and "" otherwise.
+// IsBot is set for crawlers and link-preview fetchers.
 type ParsedUserAgent struct {
 	BrowserName    string
 	BrowserVersion string
 	OSName         string
 	OSVersion      string
 	DeviceType     string
+	Platform       string
+	IsBot          bool
 }
 
 func ParseUserAgent(uaString string) *ParsedUserAgent {
@@ -31,5 +44,19 @@ func ParseUserAgent(uaString string) *ParsedUserAgent {
 		OSName:         osInfo.Name,
 		OSVersion:      osInfo.Version,
 		DeviceType:     deviceType,
+		Platform:       mobilePlatform(ua, osInfo.Name),
+		IsBot:          ua.Bot(),
+	}
+}
+
+// mobilePlatform reads iOS from the platform token, because the OS name of
+// iPads and iPods is not reliable.
+func mobilePlatform(ua *user_agent.UserAgent, osName string) string {
+	switch {
+	case strings.HasPrefix(osName, "Android"):
+		return PlatformAndroid
+	case ua.Platform() == "iPhone", ua.Platform() == "iPad", ua.Platform() == "iPod":
+		return PlatformIOS
 	}
+	return ""
 }
@@ -1,23 +1,40 @@
 package routes
 
 import (
-	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAnalyticsRoutes(router *gin.RouterGroup, analyticsHandler *handlers.AnalyticsHandler, cfg configs.Config, userRepo domain.UserRepository) {
+func SetupAnalyticsRoutes(router *gin.RouterGroup, analyticsHandler *handlers.AnalyticsHandler, mw Middleware) {
 	analyticsGroup := router.Group("/analytics")
-	analyticsGroup.Use(middleware.AuthMiddleware(cfg.JWT, userRepo))
+	analyticsGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
 	{
 		analyticsGroup.GET("/dashboard", analyticsHandler.GetUserDashboard)
 	}
 
 	urlAnalyticsGroup := router.Group("/urls/:urlID/analytics")
-	urlAnalyticsGroup.Use(middleware.AuthMiddleware(cfg.JWT, userRepo))
+	urlAnalyticsGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
 	{
 		urlAnalyticsGroup.GET("", analyticsHandler.GetURLAnalytics)
 	}
+
+	campaignAnalyticsGroup := router.Group("/campaigns/:campaignID/analytics")
+	campaignAnalyticsGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
+	{
+		campaignAnalyticsGroup.GET("", analyticsHandler.GetCampaignAnalytics)
+	}
+
+	tagAnalyticsGroup := router.Group("/tags/:tagID/analytics")
+	tagAnalyticsGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
+	{
+		tagAnalyticsGroup.GET("", analyticsHandler.GetTagAnalytics)
+	}
+
+	folderAnalyticsGroup := router.Group("/folders/:folderID/analytics")
+	folderAnalyticsGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
+	{
+		folderAnalyticsGroup.GET("", analyticsHandler.GetFolderAnalytics)
+	}
 }
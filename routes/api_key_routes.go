@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAPIKeyRoutes(router *gin.RouterGroup, apiKeyHandler *handlers.APIKeyHandler, mw Middleware) {
+	apiKeyGroup := router.Group("/profile/api-keys")
+	apiKeyGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireSession())
+	{
+		apiKeyGroup.GET("", apiKeyHandler.GetAPIKeys)
+		apiKeyGroup.POST("", apiKeyHandler.CreateAPIKey)
+		apiKeyGroup.DELETE("/:keyID", apiKeyHandler.RevokeAPIKey)
+	}
+}
@@ -1,24 +1,23 @@
 package routes
 
 import (
-	"github.com/HIUNCY/url-shortener-with-analytics/configs"
-	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthRoutes(router *gin.RouterGroup, authHandler *handlers.AuthHandler, cfg configs.Config, userRepo domain.UserRepository) {
+func SetupAuthRoutes(router *gin.RouterGroup, authHandler *handlers.AuthHandler, mw Middleware) {
 	authGroup := router.Group("/auth")
 	{
-		authGroup.POST("/register", authHandler.Register)
-		authGroup.POST("/login", authHandler.Login)
-		authGroup.POST("/refresh", authHandler.RefreshToken)
+		authGroup.POST("/register", mw.AuthRateLimit, authHandler.Register)
+		authGroup.POST("/login", mw.AuthRateLimit, authHandler.Login)
+		authGroup.POST("/refresh", mw.AuthRateLimit, authHandler.RefreshToken)
 
 		protected := authGroup.Group("")
-		protected.Use(middleware.AuthMiddleware(cfg.JWT, userRepo))
+		protected.Use(mw.Auth, mw.APIRateLimit, middleware.RequireSession())
 		{
 			protected.POST("/logout", authHandler.Logout)
+			protected.POST("/logout-all", authHandler.LogoutAll)
 		}
 	}
 }
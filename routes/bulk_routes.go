@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBulkRoutes(router *gin.RouterGroup, bulkHandler *handlers.BulkHandler, mw Middleware) {
+	bulkGroup := router.Group("/bulk")
+	bulkGroup.Use(mw.Auth, mw.APIRateLimit)
+	{
+		bulkGroup.POST("/urls", middleware.RequireScope(domain.ScopeURLsWrite), bulkHandler.SubmitURLOperation)
+		bulkGroup.GET("/:operationID", middleware.RequireScope(domain.ScopeURLsRead), bulkHandler.GetOperation)
+		bulkGroup.GET("/:operationID/result", middleware.RequireScope(domain.ScopeURLsRead), bulkHandler.DownloadResult)
+	}
+}
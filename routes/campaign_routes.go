@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCampaignRoutes registers campaign management. Campaigns group links,
+// so they use the link scopes.
+func SetupCampaignRoutes(router *gin.RouterGroup, campaignHandler *handlers.CampaignHandler, mw Middleware) {
+	campaignGroup := router.Group("/campaigns")
+	campaignGroup.Use(mw.Auth, mw.APIRateLimit)
+	{
+		campaignGroup.POST("", middleware.RequireScope(domain.ScopeURLsWrite), campaignHandler.CreateCampaign)
+		campaignGroup.GET("", middleware.RequireScope(domain.ScopeURLsRead), campaignHandler.GetUserCampaigns)
+		campaignGroup.GET("/:campaignID", middleware.RequireScope(domain.ScopeURLsRead), campaignHandler.GetCampaign)
+		campaignGroup.PUT("/:campaignID", middleware.RequireScope(domain.ScopeURLsWrite), campaignHandler.UpdateCampaign)
+		campaignGroup.DELETE("/:campaignID", middleware.RequireScope(domain.ScopeURLsWrite), campaignHandler.DeleteCampaign)
+	}
+}
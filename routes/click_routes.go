@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupClickRoutes(router *gin.RouterGroup, clickHandler *handlers.ClickHandler, mw Middleware) {
+	urlClickGroup := router.Group("/urls/:urlID/clicks")
+	urlClickGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
+	{
+		urlClickGroup.GET("", clickHandler.GetURLClicks)
+		urlClickGroup.GET("/export", clickHandler.ExportURLClicks)
+	}
+
+	clickExportGroup := router.Group("/analytics/clicks")
+	clickExportGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeAnalyticsRead))
+	{
+		clickExportGroup.GET("/export", clickHandler.ExportUserClicks)
+	}
+}
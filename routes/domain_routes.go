@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDomainRoutes(router *gin.RouterGroup, domainHandler *handlers.DomainHandler, mw Middleware) {
+	domainGroup := router.Group("/domains")
+	domainGroup.Use(mw.Auth, mw.APIRateLimit)
+	{
+		domainGroup.POST("", middleware.RequireScope(domain.ScopeDomainsWrite), domainHandler.AddDomain)
+		domainGroup.GET("", middleware.RequireScope(domain.ScopeDomainsRead), domainHandler.GetUserDomains)
+		domainGroup.GET("/:domainID", middleware.RequireScope(domain.ScopeDomainsRead), domainHandler.GetDomain)
+		domainGroup.PUT("/:domainID", middleware.RequireScope(domain.ScopeDomainsWrite), domainHandler.UpdateDomain)
+		domainGroup.DELETE("/:domainID", middleware.RequireScope(domain.ScopeDomainsWrite), domainHandler.DeleteDomain)
+		domainGroup.POST("/:domainID/verify", middleware.RequireScope(domain.ScopeDomainsWrite), domainHandler.VerifyDomain)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupFolderRoutes registers folder management. Folders organise links, so
+// they use the link scopes.
+func SetupFolderRoutes(router *gin.RouterGroup, folderHandler *handlers.FolderHandler, mw Middleware) {
+	folderGroup := router.Group("/folders")
+	folderGroup.Use(mw.Auth, mw.APIRateLimit)
+	{
+		folderGroup.POST("", middleware.RequireScope(domain.ScopeURLsWrite), folderHandler.CreateFolder)
+		folderGroup.GET("", middleware.RequireScope(domain.ScopeURLsRead), folderHandler.GetUserFolders)
+		folderGroup.GET("/:folderID", middleware.RequireScope(domain.ScopeURLsRead), folderHandler.GetFolder)
+		folderGroup.PUT("/:folderID", middleware.RequireScope(domain.ScopeURLsWrite), folderHandler.UpdateFolder)
+		folderGroup.DELETE("/:folderID", middleware.RequireScope(domain.ScopeURLsWrite), folderHandler.DeleteFolder)
+	}
+}
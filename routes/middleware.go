@@ -0,0 +1,14 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// Middleware bundles the handlers shared by the route groups, so that route
+// setup does not need to know how authentication or limiting are built.
+type Middleware struct {
+	// Auth authenticates the caller and sets "userID".
+	Auth gin.HandlerFunc
+	// APIRateLimit applies the caller's plan quota; it runs after Auth.
+	APIRateLimit gin.HandlerFunc
+	// AuthRateLimit limits the public credential endpoints per IP.
+	AuthRateLimit gin.HandlerFunc
+}
@@ -1,20 +1,18 @@
 package routes
 
 import (
-	"github.com/HIUNCY/url-shortener-with-analytics/configs"
-	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupProfileRoutes(router *gin.RouterGroup, profileHandler *handlers.ProfileHandler, cfg configs.Config, userRepo domain.UserRepository) {
+func SetupProfileRoutes(router *gin.RouterGroup, profileHandler *handlers.ProfileHandler, mw Middleware) {
 	profileGroup := router.Group("/profile")
-	profileGroup.Use(middleware.AuthMiddleware(cfg.JWT, userRepo))
+	profileGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireSession())
 	{
 		profileGroup.GET("", profileHandler.GetProfile)
 		profileGroup.PUT("", profileHandler.UpdateProfile)
 		profileGroup.PUT("/password", profileHandler.ChangePassword)
-		profileGroup.POST("/api-key/regenerate", profileHandler.RegenerateAPIKey)
+		profileGroup.PUT("/privacy", profileHandler.UpdatePrivacy)
 	}
 }
@@ -1,16 +1,15 @@
 package routes
 
 import (
-	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupQRCodeRoutes(router *gin.RouterGroup, qrCodeHandler *handlers.QRCodeHandler, cfg configs.Config, userRepo domain.UserRepository) {
+func SetupQRCodeRoutes(router *gin.RouterGroup, qrCodeHandler *handlers.QRCodeHandler, mw Middleware) {
 	qrGroup := router.Group("/urls/:urlID/qr")
-	qrGroup.Use(middleware.AuthMiddleware(cfg.JWT, userRepo))
+	qrGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeURLsRead))
 	{
 		qrGroup.GET("", qrCodeHandler.GetQRCode)
 		qrGroup.GET("/download", qrCodeHandler.DownloadQRCode)
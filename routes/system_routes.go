@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSystemRoutes registers the operational endpoints. They are not
+// authenticated, so router must belong to the internal listener and never
+// to the public API.
+func SetupSystemRoutes(router *gin.RouterGroup, metricsHandler *handlers.MetricsHandler) {
+	systemGroup := router.Group("/system")
+	{
+		systemGroup.GET("/metrics", metricsHandler.GetMetrics)
+	}
+}
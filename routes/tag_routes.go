@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
+	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
+	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTagRoutes registers tag management and bulk tagging. Tags organise
+// links, so they use the link scopes.
+func SetupTagRoutes(router *gin.RouterGroup, tagHandler *handlers.TagHandler, mw Middleware) {
+	tagGroup := router.Group("/tags")
+	tagGroup.Use(mw.Auth, mw.APIRateLimit)
+	{
+		tagGroup.POST("", middleware.RequireScope(domain.ScopeURLsWrite), tagHandler.CreateTag)
+		tagGroup.GET("", middleware.RequireScope(domain.ScopeURLsRead), tagHandler.GetUserTags)
+		tagGroup.GET("/:tagID", middleware.RequireScope(domain.ScopeURLsRead), tagHandler.GetTag)
+		tagGroup.PUT("/:tagID", middleware.RequireScope(domain.ScopeURLsWrite), tagHandler.UpdateTag)
+		tagGroup.DELETE("/:tagID", middleware.RequireScope(domain.ScopeURLsWrite), tagHandler.DeleteTag)
+	}
+
+	urlTagGroup := router.Group("/urls/tags")
+	urlTagGroup.Use(mw.Auth, mw.APIRateLimit, middleware.RequireScope(domain.ScopeURLsWrite))
+	{
+		urlTagGroup.POST("", tagHandler.BulkTagURLs)
+	}
+}
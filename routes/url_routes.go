@@ -1,21 +1,22 @@
 package routes
 
 import (
-	"github.com/HIUNCY/url-shortener-with-analytics/configs"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/domain"
 	"github.com/HIUNCY/url-shortener-with-analytics/internal/handlers"
 	"github.com/HIUNCY/url-shortener-with-analytics/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupURLRoutes(router *gin.RouterGroup, urlHandler *handlers.URLHandler, cfg configs.Config, userRepo domain.UserRepository) {
+func SetupURLRoutes(router *gin.RouterGroup, urlHandler *handlers.URLHandler, mw Middleware) {
 	urlGroup := router.Group("/urls")
-	urlGroup.Use(middleware.AuthMiddleware(cfg.JWT, userRepo))
+	urlGroup.Use(mw.Auth, mw.APIRateLimit)
 	{
-		urlGroup.POST("", urlHandler.CreateShortURL)
-		urlGroup.GET("", urlHandler.GetUserURLs)
-		urlGroup.GET("/:urlID", urlHandler.GetURLDetails)
-		urlGroup.PUT("/:urlID", urlHandler.UpdateURL)
-		urlGroup.DELETE("/:urlID", urlHandler.DeleteURL)
+		urlGroup.POST("", middleware.RequireScope(domain.ScopeURLsWrite), urlHandler.CreateShortURL)
+		urlGroup.GET("", middleware.RequireScope(domain.ScopeURLsRead), urlHandler.GetUserURLs)
+		urlGroup.GET("/trash", middleware.RequireScope(domain.ScopeURLsRead), urlHandler.GetTrash)
+		urlGroup.GET("/:urlID", middleware.RequireScope(domain.ScopeURLsRead), urlHandler.GetURLDetails)
+		urlGroup.PUT("/:urlID", middleware.RequireScope(domain.ScopeURLsWrite), urlHandler.UpdateURL)
+		urlGroup.DELETE("/:urlID", middleware.RequireScope(domain.ScopeURLsWrite), urlHandler.DeleteURL)
+		urlGroup.POST("/:urlID/restore", middleware.RequireScope(domain.ScopeURLsWrite), urlHandler.RestoreURL)
 	}
 }
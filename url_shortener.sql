@@ -15,21 +15,58 @@ CREATE TABLE users (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
-    api_key VARCHAR(64) UNIQUE NOT NULL,
     first_name VARCHAR(100),
     last_name VARCHAR(100),
     is_active BOOLEAN DEFAULT true,
     plan_type VARCHAR(20) DEFAULT 'free' CHECK (plan_type IN ('free', 'pro', 'enterprise')),
+    ip_privacy VARCHAR(10) DEFAULT 'masked' CHECK (ip_privacy IN ('full', 'masked', 'hidden')),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     last_login_at TIMESTAMP WITH TIME ZONE
 );
 
+-- Create sessions table; every issued token belongs to one session
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create refresh_tokens table; the tokens of one session form a rotation family
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY, -- the token's jti
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create api_keys table; only a SHA-256 hash of each key is stored
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create custom domains table
 CREATE TABLE domains (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    domain_name VARCHAR(255) UNIQUE NOT NULL,
+    domain_name VARCHAR(255) NOT NULL, -- unique among verified claims, see idx_domains_verified_name
     is_verified BOOLEAN DEFAULT false,
     verification_token VARCHAR(255),
     is_active BOOLEAN DEFAULT true,
@@ -37,24 +74,75 @@ CREATE TABLE domains (
     verified_at TIMESTAMP WITH TIME ZONE
 );
 
+-- Create campaigns table; links created in a campaign get its UTM parameters
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    utm_source VARCHAR(255),
+    utm_medium VARCHAR(255),
+    utm_campaign VARCHAR(255),
+    utm_term VARCHAR(255),
+    utm_content VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create folders table; a link is in at most one folder
+CREATE TABLE folders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create tags table; links and tags are paired in url_tags
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create urls table (main table for shortened URLs)
 CREATE TABLE urls (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id UUID REFERENCES users(id) ON DELETE SET NULL,
     original_url TEXT NOT NULL,
-    short_code VARCHAR(20) UNIQUE NOT NULL,
+    short_code VARCHAR(20) NOT NULL, -- unique per domain, see idx_urls_domain_short_code
     custom_alias VARCHAR(50),
     domain_id UUID REFERENCES domains(id) ON DELETE SET NULL,
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
+    folder_id UUID REFERENCES folders(id) ON DELETE SET NULL,
     title VARCHAR(500),
     description TEXT,
     password_hash VARCHAR(255), -- for password-protected URLs
+    geo_rules JSONB, -- ordered country/region targeting rules, see domain.GeoRule
+    device_rules JSONB, -- iOS/Android app destinations, see domain.DeviceRule
+    split_destinations JSONB, -- weighted A/B destinations, see domain.SplitDestination
     is_active BOOLEAN DEFAULT true,
     click_count INTEGER DEFAULT 0,
     unique_click_count INTEGER DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE, -- launch time; NULL means live at once
     expires_at TIMESTAMP WITH TIME ZONE,
+    schedule JSONB, -- recurring availability windows, see domain.Schedule
+    expired_redirect_url TEXT, -- destination once expires_at has passed
+    expired_message TEXT, -- shown once expires_at has passed, without expired_redirect_url
+    max_clicks INTEGER CHECK (max_clicks > 0), -- NULL means no click cap
+    redirect_count INTEGER NOT NULL DEFAULT 0, -- redirects counted against max_clicks
+    cap_fallback_url TEXT, -- destination once max_clicks is reached; NULL deactivates the link
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    last_clicked_at TIMESTAMP WITH TIME ZONE
+    last_clicked_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE -- set while the link is in the trash
+);
+
+-- Create url_tags table (tags of each link)
+CREATE TABLE url_tags (
+    url_id UUID NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (url_id, tag_id)
 );
 
 -- Create clicks table for detailed analytics
@@ -64,12 +152,22 @@ CREATE TABLE clicks (
     ip_address INET,
     user_agent TEXT,
     referer TEXT,
+    referrer_domain VARCHAR(255), -- site of the referer, see pkg/referrer
+    referrer_category VARCHAR(10) CHECK (referrer_category IN ('search', 'social', 'email', 'direct', 'other')),
+    utm_source VARCHAR(255),
+    utm_medium VARCHAR(255),
+    utm_campaign VARCHAR(255),
+    utm_term VARCHAR(255),
+    utm_content VARCHAR(255),
+    variant VARCHAR(255), -- destination chosen by targeting rules, e.g. geo:US
     country VARCHAR(2), -- ISO country code
     region VARCHAR(100),
     city VARCHAR(100),
     browser VARCHAR(50),
     os VARCHAR(50),
     device_type VARCHAR(20) CHECK (device_type IN ('desktop', 'mobile', 'tablet', 'unknown')),
+    language VARCHAR(8), -- base language of the preferred Accept-Language entry
+    visitor_hash VARCHAR(64), -- sha256 of the visitor cookie, or daily-salted hash of IP + user agent
     is_unique BOOLEAN DEFAULT false,
     clicked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -87,6 +185,7 @@ CREATE TABLE qr_codes (
 -- Create rate_limits table for tracking API usage
 CREATE TABLE rate_limits (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    limit_key VARCHAR(255) NOT NULL, -- e.g. user:<id> or ip:<addr>|<route>
     user_id UUID REFERENCES users(id) ON DELETE CASCADE,
     api_key VARCHAR(64),
     ip_address INET,
@@ -96,26 +195,73 @@ CREATE TABLE rate_limits (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Create bulk_operations table for asynchronous bulk link jobs
+CREATE TABLE bulk_operations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    operation_type VARCHAR(20) NOT NULL CHECK (operation_type IN ('create', 'update', 'deactivate', 'delete')),
+    file_format VARCHAR(10) NOT NULL CHECK (file_format IN ('csv', 'json')),
+    total_count INTEGER NOT NULL,
+    processed_count INTEGER DEFAULT 0,
+    success_count INTEGER DEFAULT 0,
+    failed_count INTEGER DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    node_id VARCHAR(255), -- instance holding the upload; NULL when storage is shared
+    file_path TEXT, -- the upload; removed, and set to NULL, when the job ends
+    result_file_path TEXT,
+    result_file_size BIGINT NOT NULL DEFAULT 0, -- result file size at the last checkpoint
+    error_details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
 -- Create indexes for better performance
 
 -- Users table indexes
 CREATE INDEX idx_users_email ON users(email);
-CREATE INDEX idx_users_api_key ON users(api_key);
 CREATE INDEX idx_users_created_at ON users(created_at);
 
+-- Sessions table indexes
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
+
+-- Refresh tokens table indexes
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+CREATE INDEX idx_refresh_tokens_parent_id ON refresh_tokens(parent_id);
+
+-- API keys table indexes
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
 -- Domains table indexes
 CREATE INDEX idx_domains_user_id ON domains(user_id);
-CREATE INDEX idx_domains_domain_name ON domains(domain_name);
+CREATE UNIQUE INDEX idx_domains_user_domain_name ON domains(user_id, domain_name);
+CREATE UNIQUE INDEX idx_domains_verified_name ON domains(domain_name) WHERE is_verified;
+CREATE INDEX idx_domains_unverified_created_at ON domains(created_at) WHERE NOT is_verified;
 CREATE INDEX idx_domains_is_active ON domains(is_active);
 
+-- Campaigns table indexes
+CREATE UNIQUE INDEX idx_campaigns_user_name ON campaigns(user_id, lower(name));
+
+-- Folders and tags table indexes
+CREATE UNIQUE INDEX idx_folders_user_name ON folders(user_id, lower(name));
+CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, lower(name));
+CREATE INDEX idx_url_tags_tag_id ON url_tags(tag_id);
+
 -- URLs table indexes
 CREATE INDEX idx_urls_user_id ON urls(user_id);
 CREATE INDEX idx_urls_short_code ON urls(short_code);
+CREATE UNIQUE INDEX idx_urls_domain_short_code ON urls(COALESCE(domain_id, '00000000-0000-0000-0000-000000000000'::uuid), short_code);
+CREATE INDEX idx_urls_domain_id ON urls(domain_id);
+CREATE INDEX idx_urls_campaign_id ON urls(campaign_id);
+CREATE INDEX idx_urls_folder_id ON urls(folder_id);
 CREATE INDEX idx_urls_custom_alias ON urls(custom_alias);
 CREATE INDEX idx_urls_is_active ON urls(is_active);
 CREATE INDEX idx_urls_expires_at ON urls(expires_at);
 CREATE INDEX idx_urls_created_at ON urls(created_at);
 CREATE INDEX idx_urls_click_count ON urls(click_count);
+CREATE INDEX idx_urls_deleted_at ON urls(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- Clicks table indexes
 CREATE INDEX idx_clicks_url_id ON clicks(url_id);
@@ -133,11 +279,18 @@ CREATE INDEX idx_rate_limits_user_id ON rate_limits(user_id);
 CREATE INDEX idx_rate_limits_api_key ON rate_limits(api_key);
 CREATE INDEX idx_rate_limits_ip_address ON rate_limits(ip_address);
 CREATE INDEX idx_rate_limits_window_start ON rate_limits(window_start);
+CREATE UNIQUE INDEX idx_rate_limits_key_window ON rate_limits(limit_key, window_start);
+
+-- Bulk operations table indexes
+CREATE INDEX idx_bulk_operations_user_id ON bulk_operations(user_id);
+CREATE INDEX idx_bulk_operations_status_created ON bulk_operations(status, created_at);
 
 -- Create composite indexes for common queries
 CREATE INDEX idx_urls_user_active ON urls(user_id, is_active);
+CREATE INDEX idx_urls_user_created ON urls(user_id, created_at, id);
 CREATE INDEX idx_clicks_url_date ON clicks(url_id, clicked_at);
 CREATE INDEX idx_clicks_unique_url ON clicks(url_id, is_unique);
+CREATE INDEX idx_clicks_url_visitor ON clicks(url_id, visitor_hash, clicked_at);
 
 -- Create functions for automatic timestamp updates
 CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -157,6 +310,10 @@ CREATE TRIGGER update_urls_updated_at
     BEFORE UPDATE ON urls
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_campaigns_updated_at
+    BEFORE UPDATE ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
 -- Create function to generate short codes
 CREATE OR REPLACE FUNCTION generate_short_code(length INTEGER DEFAULT 6)
 RETURNS TEXT AS $$
@@ -198,7 +355,7 @@ SELECT
     COALESCE(SUM(urls.unique_click_count), 0) as total_unique_clicks,
     u.created_at as user_created_at
 FROM users u
-LEFT JOIN urls ON u.id = urls.user_id
+LEFT JOIN urls ON u.id = urls.user_id AND urls.deleted_at IS NULL
 GROUP BY u.id, u.email, u.plan_type, u.created_at;
 
 -- Top URLs view
@@ -214,14 +371,14 @@ SELECT
     us.email as user_email
 FROM urls u
 LEFT JOIN users us ON u.user_id = us.id
-WHERE u.is_active = true
+WHERE u.is_active = true AND u.deleted_at IS NULL
 ORDER BY u.click_count DESC;
 
 -- Insert sample data for development
-INSERT INTO users (email, password_hash, api_key, first_name, last_name, plan_type) VALUES
-('john.doe@example.com', '$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LeVMpYlqhRVtCSpeW', 'api_key_john_123456789', 'John', 'Doe', 'free'),
-('jane.smith@example.com', '$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LeVMpYlqhRVtCSpeW', 'api_key_jane_987654321', 'Jane', 'Smith', 'pro'),
-('admin@urlshortener.com', '$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LeVMpYlqhRVtCSpeW', 'api_key_admin_admin123', 'Admin', 'User', 'enterprise');
+INSERT INTO users (email, password_hash, first_name, last_name, plan_type) VALUES
+('john.doe@example.com', '$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LeVMpYlqhRVtCSpeW', 'John', 'Doe', 'free'),
+('jane.smith@example.com', '$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LeVMpYlqhRVtCSpeW', 'Jane', 'Smith', 'pro'),
+('admin@urlshortener.com', '$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LeVMpYlqhRVtCSpeW', 'Admin', 'User', 'enterprise');
 
 -- Insert sample URLs
 INSERT INTO urls (user_id, original_url, short_code, title, custom_alias) VALUES
@@ -250,7 +407,7 @@ BEGIN
     SET
         click_count = click_count + 1,
         last_clicked_at = CURRENT_TIMESTAMP
-    WHERE short_code = url_short_code AND is_active = true;
+    WHERE short_code = url_short_code AND is_active = true AND deleted_at IS NULL;
 END;
 $$ LANGUAGE plpgsql;
 
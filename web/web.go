@@ -0,0 +1,17 @@
+// Package web embeds the HTML pages served on short links, so the binary
+// does not depend on the working directory it is started from.
+package web
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Templates parses the embedded pages. Each is named after its file, e.g.
+// "404.html".
+func Templates() *template.Template {
+	return template.Must(template.ParseFS(templateFS, "templates/*.html"))
+}